@@ -9,9 +9,14 @@ import (
 )
 
 type Config struct {
-	Database DatabaseConfig
-	JWT      JWTConfig
-	Server   ServerConfig
+	Database  DatabaseConfig
+	JWT       JWTConfig
+	Server    ServerConfig
+	Currency  CurrencyConfig
+	Scheduler SchedulerConfig
+	Alert     AlertConfig
+	Storage   StorageConfig
+	Trash     TrashConfig
 }
 
 type DatabaseConfig struct {
@@ -35,6 +40,35 @@ type ServerConfig struct {
 	Mode string
 }
 
+type CurrencyConfig struct {
+	ExchangeRatesFile string
+}
+
+type SchedulerConfig struct {
+	IntervalMinutes int
+}
+
+type AlertConfig struct {
+	WebhookURL    string
+	WebhookSecret string
+}
+
+type StorageConfig struct {
+	Driver      string
+	LocalPath   string
+	MaxUploadMB int
+	S3Endpoint  string
+	S3Region    string
+	S3Bucket    string
+	S3AccessKey string
+	S3SecretKey string
+	S3PathStyle bool
+}
+
+type TrashConfig struct {
+	RetentionDays int
+}
+
 func LoadConfig() *Config {
 	err := godotenv.Load()
 	if err != nil {
@@ -43,6 +77,19 @@ func LoadConfig() *Config {
 
 	expireHours, _ := strconv.Atoi(getEnv("JWT_EXPIRE_HOURS", "24"))
 	refreshExpireHours, _ := strconv.Atoi(getEnv("JWT_REFRESH_EXPIRE_HOURS", "168")) //expired after 7 days
+	schedulerIntervalMinutes, _ := strconv.Atoi(getEnv("SCHEDULER_INTERVAL_MINUTES", "15"))
+	if schedulerIntervalMinutes < 1 {
+		schedulerIntervalMinutes = 15
+	}
+	maxUploadMB, _ := strconv.Atoi(getEnv("STORAGE_MAX_UPLOAD_MB", "10"))
+	if maxUploadMB < 1 {
+		maxUploadMB = 10
+	}
+	s3PathStyle, _ := strconv.ParseBool(getEnv("STORAGE_S3_PATH_STYLE", "false"))
+	trashRetentionDays, _ := strconv.Atoi(getEnv("TRASH_RETENTION_DAYS", "30"))
+	if trashRetentionDays < 1 {
+		trashRetentionDays = 30
+	}
 
 	return &Config{
 		Database: DatabaseConfig{
@@ -63,6 +110,30 @@ func LoadConfig() *Config {
 			Port: getEnv("SERVER_PORT", "8080"),
 			Mode: getEnv("SERVER_MODE", "debug"),
 		},
+		Currency: CurrencyConfig{
+			ExchangeRatesFile: getEnv("EXCHANGE_RATES_FILE", ""),
+		},
+		Scheduler: SchedulerConfig{
+			IntervalMinutes: schedulerIntervalMinutes,
+		},
+		Alert: AlertConfig{
+			WebhookURL:    getEnv("ALERT_WEBHOOK_URL", ""),
+			WebhookSecret: getEnv("ALERT_WEBHOOK_SECRET", ""),
+		},
+		Storage: StorageConfig{
+			Driver:      getEnv("STORAGE_DRIVER", "local"),
+			LocalPath:   getEnv("STORAGE_LOCAL_PATH", "./uploads"),
+			MaxUploadMB: maxUploadMB,
+			S3Endpoint:  getEnv("STORAGE_S3_ENDPOINT", ""),
+			S3Region:    getEnv("STORAGE_S3_REGION", "us-east-1"),
+			S3Bucket:    getEnv("STORAGE_S3_BUCKET", ""),
+			S3AccessKey: getEnv("STORAGE_S3_ACCESS_KEY", ""),
+			S3SecretKey: getEnv("STORAGE_S3_SECRET_KEY", ""),
+			S3PathStyle: s3PathStyle,
+		},
+		Trash: TrashConfig{
+			RetentionDays: trashRetentionDays,
+		},
 	}
 }
 
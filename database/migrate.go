@@ -6,16 +6,95 @@ import (
 )
 
 func AutoMigrate() {
+	// CONVERT LEGACY COLUMNS BEFORE GORM COMPARES SCHEMAS
+	migrateExpenseAmountToNumeric()
+
 	err := DB.AutoMigrate(
 		&models.User{},
+		&models.Ledger{},
+		&models.LedgerMember{},
+		&models.LedgerInvitation{},
 		&models.Category{},
+		&models.CategoryOverride{},
+		&models.Tag{},
+		&models.Payee{},
+		&models.Account{},
+		&models.Goal{},
+		&models.PayeeAlias{},
+		&models.CategorizationRule{},
 		&models.Expense{},
+		&models.Transfer{},
+		&models.ExpenseSplit{},
+		&models.ExpenseShare{},
+		&models.Settlement{},
 		&models.RefreshToken{},
+		&models.ExchangeRate{},
+		&models.RecurringExpense{},
+		&models.Budget{},
+		&models.BudgetAlert{},
+		&models.Notification{},
+		&models.WebhookDelivery{},
+		&models.Attachment{},
 	)
 
 	if err != nil {
 		log.Fatal("Failed to migrate database:", err)
 	}
 
+	// BACKFILL DATA FOR NEWLY ADDED COLUMNS
+	backfillExpenseSpentAt()
+	backfillExpenseBaseAmount()
+	backfillExpenseDeletedAt()
+
 	log.Println("Database migration completed!")
 }
+
+// EXPENSES CREATED BEFORE spent_at EXISTED WERE SPENT WHEN THEY WERE CREATED
+func backfillExpenseSpentAt() {
+	err := DB.Exec("UPDATE expenses SET spent_at = created_at WHERE spent_at IS NULL").Error
+	if err != nil {
+		log.Fatal("Failed to backfill expenses.spent_at:", err)
+	}
+}
+
+// EXPENSES CREATED BEFORE CURRENCIES EXISTED ARE IN THE DEFAULT CURRENCY
+func backfillExpenseBaseAmount() {
+	err := DB.Exec("UPDATE expenses SET base_amount = amount WHERE base_amount IS NULL").Error
+	if err != nil {
+		log.Fatal("Failed to backfill expenses.base_amount:", err)
+	}
+}
+
+// deleted_at USED TO BE WRITTEN AS A ZERO TIME ON EVERY EXPENSE, ONLY A REAL TIME MOVES ONE TO THE TRASH NOW
+func backfillExpenseDeletedAt() {
+	err := DB.Exec("UPDATE expenses SET deleted_at = NULL WHERE deleted_at < '1900-01-01'").Error
+	if err != nil {
+		log.Fatal("Failed to backfill expenses.deleted_at:", err)
+	}
+}
+
+// AMOUNTS USED TO BE double precision, CONVERT THEM TO AN EXACT numeric(19,4)
+func migrateExpenseAmountToNumeric() {
+	if !DB.Migrator().HasTable("expenses") {
+		return
+	}
+
+	var dataType string
+	err := DB.Raw(
+		"SELECT data_type FROM information_schema.columns WHERE table_schema = CURRENT_SCHEMA() AND table_name = 'expenses' AND column_name = 'amount'",
+	).Scan(&dataType).Error
+	if err != nil {
+		log.Fatal("Failed to inspect expenses.amount:", err)
+	}
+
+	if dataType != "double precision" {
+		return
+	}
+
+	err = DB.Exec("ALTER TABLE expenses ALTER COLUMN amount TYPE numeric(19,4) USING round(amount::numeric, 4)").Error
+	if err != nil {
+		log.Fatal("Failed to convert expenses.amount to numeric:", err)
+	}
+
+	log.Println("Converted expenses.amount to numeric(19,4)")
+}
@@ -0,0 +1,433 @@
+package handlers
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type AccountHandler struct {
+	accountRepo *repositories.AccountRepository
+	userRepo    *repositories.UserRepository
+	validator   *validator.Validate
+}
+
+func NewAccountHandler(accountRepo *repositories.AccountRepository, userRepo *repositories.UserRepository) *AccountHandler {
+	return &AccountHandler{
+		accountRepo: accountRepo,
+		userRepo:    userRepo,
+		validator:   validator.New(),
+	}
+}
+
+// GET ACCOUNTS BY USER ID
+// GetAccountsByUserID godoc
+// @Summary Get accounts by user ID
+// @Description Get all accounts (wallets) for the authenticated user
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by account name"
+// @Param type query string false "Filter by account type (cash, bank, credit_card, savings, other)"
+// @Param currency query string false "Filter by currency"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Account]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /accounts [get]
+func (h *AccountHandler) GetAccountsByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET ACCOUNTS BY USER ID
+	accounts, total, totalPages, err := h.accountRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get accounts")
+		return
+	}
+
+	response := gin.H{
+		"data":        accounts,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Accounts retrieved successfully", response)
+}
+
+// GET ACCOUNT BALANCES
+// GetAccountBalances godoc
+// @Summary Get the balances of all accounts
+// @Description Get the balance of every account of the authenticated user in its own currency: the opening balance plus income and incoming transfers, minus expenses and outgoing transfers with their fees
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param at query string false "Balance at the end of this date (YYYY-MM-DD) or just before this time (RFC3339), defaults to now"
+// @Success 200 {object} utils.Response[[]models.AccountBalance]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /accounts/balances [get]
+func (h *AccountHandler) GetAccountBalances(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	asOf, ok := parseBalanceAsOf(c, user.Location())
+	if !ok {
+		return
+	}
+
+	balances, err := h.accountRepo.Balances(user.ID, 0, asOf)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get account balances")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account balances retrieved successfully", balances)
+}
+
+// GET ACCOUNT BY ID
+// GetAccountByID godoc
+// @Summary Get account by ID
+// @Description Get an account by ID for the authenticated user
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Success 200 {object} utils.Response[models.Account]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /accounts/{id} [get]
+func (h *AccountHandler) GetAccountByID(c *gin.Context) {
+	account, _, ok := h.getOwnedAccount(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account retrieved successfully", account)
+}
+
+// GET ACCOUNT BALANCE
+// GetAccountBalance godoc
+// @Summary Get the balance of an account
+// @Description Get the balance of an account in its own currency: the opening balance plus income and incoming transfers, minus expenses and outgoing transfers with their fees
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param at query string false "Balance at the end of this date (YYYY-MM-DD) or just before this time (RFC3339), defaults to now"
+// @Success 200 {object} utils.Response[models.AccountBalance]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /accounts/{id}/balance [get]
+func (h *AccountHandler) GetAccountBalance(c *gin.Context) {
+	account, user, ok := h.getOwnedAccount(c)
+	if !ok {
+		return
+	}
+
+	asOf, ok := parseBalanceAsOf(c, user.Location())
+	if !ok {
+		return
+	}
+
+	balances, err := h.accountRepo.Balances(user.ID, account.ID, asOf)
+	if err != nil || len(balances) != 1 {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get account balance")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account balance retrieved successfully", balances[0])
+}
+
+// CREATE ACCOUNT
+// CreateAccount godoc
+// @Summary Create an account
+// @Description Create an account (cash, bank, credit card, savings or other) with an opening balance, the currency defaults to the user base currency
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param request body models.AccountRequest true "Account data"
+// @Success 201 {object} utils.Response[models.Account]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /accounts [post]
+func (h *AccountHandler) CreateAccount(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	account := &models.Account{
+		UserID:   user.ID,
+		Currency: user.BaseCurrency,
+	}
+
+	if !h.bindAccount(c, account) {
+		return
+	}
+
+	if err := h.accountRepo.Create(account); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create account")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Account created successfully", account)
+}
+
+// UPDATE ACCOUNT
+// UpdateAccount godoc
+// @Summary Update an account
+// @Description Update an account, the currency can only change while no expenses or transfers are booked on it
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Param request body models.AccountRequest true "Account data"
+// @Success 200 {object} utils.Response[models.Account]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /accounts/{id} [put]
+func (h *AccountHandler) UpdateAccount(c *gin.Context) {
+	account, _, ok := h.getOwnedAccount(c)
+	if !ok {
+		return
+	}
+
+	currency := account.Currency
+
+	if !h.bindAccount(c, account) {
+		return
+	}
+
+	// EXPENSES AND TRANSFERS ARE IN THE ACCOUNT CURRENCY, IT CANNOT CHANGE UNDER THEM
+	if account.Currency != currency {
+		expenses, err := h.accountRepo.CountExpenses(account.ID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update account")
+			return
+		}
+		transfers, err := h.accountRepo.CountTransfers(account.ID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update account")
+			return
+		}
+		if expenses > 0 || transfers > 0 {
+			utils.ErrorResponse(c, http.StatusConflict, "Account currency cannot change while expenses or transfers are booked on it")
+			return
+		}
+	}
+
+	if err := h.accountRepo.Update(account); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update account")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account updated successfully", account)
+}
+
+// DELETE ACCOUNT
+// DeleteAccount godoc
+// @Summary Delete an account
+// @Description Delete an account, its expenses are kept without an account. Accounts with transfers cannot be deleted until the transfers are
+// @Tags accounts
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Account ID"
+// @Success 200 {object} utils.Response[models.Account]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /accounts/{id} [delete]
+func (h *AccountHandler) DeleteAccount(c *gin.Context) {
+	account, _, ok := h.getOwnedAccount(c)
+	if !ok {
+		return
+	}
+
+	// A TRANSFER NEEDS BOTH OF ITS ACCOUNTS
+	transfers, err := h.accountRepo.CountTransfers(account.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+	if transfers > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Account has transfers, delete them first")
+		return
+	}
+
+	if err := h.accountRepo.Delete(account); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete account")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Account deleted successfully", account)
+}
+
+// GET THE ACCOUNT FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *AccountHandler) getOwnedAccount(c *gin.Context) (*models.Account, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET ACCOUNT ID FROM URL PARAM
+	accountID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid account ID")
+		return nil, nil, false
+	}
+
+	// GET ACCOUNT BY ID
+	account, err := h.accountRepo.GetByID(uint(accountID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Account not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF ACCOUNT BELONGS TO USER
+	if account.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Account does not belong to this user")
+		return nil, nil, false
+	}
+
+	return account, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO account, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *AccountHandler) bindAccount(c *gin.Context, account *models.Account) bool {
+	var req models.AccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Account name is required")
+		return false
+	}
+
+	// ACCOUNT NAMES ARE UNIQUE PER USER
+	if existing, err := h.accountRepo.GetByName(account.UserID, name); err == nil && existing.ID != account.ID {
+		utils.ErrorResponse(c, http.StatusConflict, "Account already exists")
+		return false
+	}
+
+	// RESOLVE CURRENCY, KEEP THE CURRENT ONE WHEN OMITTED
+	if req.Currency != "" {
+		account.Currency = models.NormalizeCurrency(req.Currency)
+	}
+
+	// VALIDATE OPENING BALANCE PRECISION AGAINST THE CURRENCY MINOR UNITS
+	if !req.OpeningBalance.FitsExponent(models.CurrencyExponent(account.Currency)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Opening balance has more decimal places than "+account.Currency+" allows")
+		return false
+	}
+
+	account.Name = name
+	account.Type = req.Type
+	account.OpeningBalance = req.OpeningBalance
+
+	return true
+}
+
+// PARSE THE at QUERY PARAM OF THE BALANCE ENDPOINTS, A PLAIN DATE INCLUDES THE WHOLE DAY
+// WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func parseBalanceAsOf(c *gin.Context, loc *time.Location) (time.Time, bool) {
+	value := c.Query("at")
+	if value == "" {
+		return time.Now(), true
+	}
+
+	asOf, dateOnly, err := utils.ParseDateTime(value, loc)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "at: "+err.Error())
+		return time.Time{}, false
+	}
+	if dateOnly {
+		asOf = asOf.AddDate(0, 0, 1)
+	}
+
+	return asOf, true
+}
@@ -0,0 +1,272 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	attachmentRepo    *repositories.AttachmentRepository
+	expenseRepo       *repositories.ExpenseRepository
+	userRepo          *repositories.UserRepository
+	attachmentService *services.AttachmentService
+	ledgerService     *services.LedgerService
+}
+
+func NewAttachmentHandler(attachmentRepo *repositories.AttachmentRepository, expenseRepo *repositories.ExpenseRepository, userRepo *repositories.UserRepository, attachmentService *services.AttachmentService, ledgerService *services.LedgerService) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentRepo:    attachmentRepo,
+		expenseRepo:       expenseRepo,
+		userRepo:          userRepo,
+		attachmentService: attachmentService,
+		ledgerService:     ledgerService,
+	}
+}
+
+// GET ATTACHMENTS BY EXPENSE ID
+// GetAttachmentsByExpenseID godoc
+// @Summary Get the attachments of an expense
+// @Description Get the receipt attachments of an expense owned by the authenticated user
+// @Tags attachments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Expense ID"
+// @Success 200 {object} utils.Response[[]models.Attachment]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/{id}/attachments [get]
+func (h *AttachmentHandler) GetAttachmentsByExpenseID(c *gin.Context) {
+	expense, ok := h.getOwnedExpense(c, false)
+	if !ok {
+		return
+	}
+
+	attachments, err := h.attachmentRepo.GetByExpenseID(expense.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get attachments")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attachments retrieved successfully", attachments)
+}
+
+// UPLOAD ATTACHMENT
+// UploadAttachment godoc
+// @Summary Upload a receipt attachment
+// @Description Attach a JPEG, PNG, GIF, WebP or PDF file to an expense, the type is detected from the content. Files larger than STORAGE_MAX_UPLOAD_MB are rejected
+// @Tags attachments
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param id path int true "Expense ID"
+// @Param file formData file true "Receipt image or PDF"
+// @Success 201 {object} utils.Response[models.Attachment]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 413 {object} utils.Response[any]
+// @Failure 415 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/{id}/attachments [post]
+func (h *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	expense, ok := h.getOwnedExpense(c, true)
+	if !ok {
+		return
+	}
+
+	// LIMIT THE REQUEST BODY, WITH ROOM FOR THE MULTIPART HEADERS
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.attachmentService.MaxSize()+1<<20)
+
+	// GET UPLOADED FILE
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, services.ErrAttachmentTooLarge.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusBadRequest, "File is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	// STORE FILE
+	attachment, err := h.attachmentService.Upload(c.Request.Context(), expense, fileHeader.Filename, file, fileHeader.Size)
+	if err != nil {
+		switch {
+		case errors.Is(err, services.ErrAttachmentTooLarge):
+			utils.ErrorResponse(c, http.StatusRequestEntityTooLarge, err.Error())
+		case errors.Is(err, services.ErrAttachmentTypeForbidden):
+			utils.ErrorResponse(c, http.StatusUnsupportedMediaType, err.Error())
+		default:
+			log.Printf("Failed to store attachment of expense %d: %v", expense.ID, err)
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to upload attachment")
+		}
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Attachment uploaded successfully", attachment)
+}
+
+// DOWNLOAD ATTACHMENT
+// DownloadAttachment godoc
+// @Summary Download a receipt attachment
+// @Description Download the content of an attachment of an expense owned by the authenticated user
+// @Tags attachments
+// @Produce  image/jpeg,image/png,image/gif,image/webp,application/pdf
+// @Param id path int true "Expense ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Param download query bool false "Send as a download instead of inline" default(false)
+// @Success 200 {file} file
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/{id}/attachments/{attachment_id} [get]
+func (h *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	attachment, ok := h.getOwnedAttachment(c, false)
+	if !ok {
+		return
+	}
+
+	content, err := h.attachmentService.Open(c.Request.Context(), attachment)
+	if errors.Is(err, services.ErrObjectNotFound) {
+		utils.ErrorResponse(c, http.StatusNotFound, "Attachment content not found")
+		return
+	}
+	if err != nil {
+		log.Printf("Failed to open attachment %d: %v", attachment.ID, err)
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to download attachment")
+		return
+	}
+	defer content.Close()
+
+	disposition := "inline"
+	if download, _ := strconv.ParseBool(c.Query("download")); download {
+		disposition = "attachment"
+	}
+
+	c.Header("Content-Type", attachment.ContentType)
+	c.Header("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	c.Header("Content-Disposition", mime.FormatMediaType(disposition, map[string]string{"filename": attachment.FileName}))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		log.Printf("Failed to stream attachment %d: %v", attachment.ID, err)
+	}
+}
+
+// DELETE ATTACHMENT
+// DeleteAttachment godoc
+// @Summary Delete a receipt attachment
+// @Description Delete an attachment of an expense owned by the authenticated user
+// @Tags attachments
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Expense ID"
+// @Param attachment_id path int true "Attachment ID"
+// @Success 200 {object} utils.Response[models.Attachment]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/{id}/attachments/{attachment_id} [delete]
+func (h *AttachmentHandler) DeleteAttachment(c *gin.Context) {
+	attachment, ok := h.getOwnedAttachment(c, true)
+	if !ok {
+		return
+	}
+
+	if err := h.attachmentService.Delete(c.Request.Context(), attachment); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete attachment")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Attachment deleted successfully", attachment)
+}
+
+// GET THE EXPENSE FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR MAY NOT READ IT, OR CHANGE IT WHEN write IS SET
+func (h *AttachmentHandler) getOwnedExpense(c *gin.Context, write bool) (*models.Expense, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, false
+	}
+
+	// GET EXPENSE ID FROM URL PARAM
+	expenseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid expense ID")
+		return nil, false
+	}
+
+	// GET EXPENSE BY ID
+	expense, err := h.expenseRepo.GetByID(uint(expenseID), user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Expense not found")
+		return nil, false
+	}
+
+	// CHECK IF EXPENSE BELONGS TO USER OR TO ONE OF THEIR LEDGERS, CHANGES NEED THE OWNER OR EDITOR ROLE
+	_, err = h.ledgerService.Authorize(user.ID, expense.UserID, expense.LedgerID, write)
+	if ledgerAccessError(c, err, http.StatusUnauthorized, "Expense does not belong to this user") {
+		return nil, false
+	}
+
+	return expense, true
+}
+
+// GET THE ATTACHMENT FROM THE URL PARAMS, IT MUST BELONG TO THE OWNED EXPENSE
+func (h *AttachmentHandler) getOwnedAttachment(c *gin.Context, write bool) (*models.Attachment, bool) {
+	expense, ok := h.getOwnedExpense(c, write)
+	if !ok {
+		return nil, false
+	}
+
+	// GET ATTACHMENT ID FROM URL PARAM
+	attachmentID, err := strconv.ParseUint(c.Param("attachment_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid attachment ID")
+		return nil, false
+	}
+
+	// GET ATTACHMENT BY ID
+	attachment, err := h.attachmentRepo.GetByID(uint(attachmentID))
+	if err != nil || attachment.ExpenseID != expense.ID {
+		utils.ErrorResponse(c, http.StatusNotFound, "Attachment not found")
+		return nil, false
+	}
+
+	return attachment, true
+}
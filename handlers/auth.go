@@ -75,9 +75,11 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	// CREATE NEW USER
 	user := &models.User{
-		Name:     req.Name,
-		Email:    req.Email,
-		Password: hashedPassword,
+		Name:         req.Name,
+		Email:        req.Email,
+		Password:     hashedPassword,
+		BaseCurrency: models.NormalizeCurrency(req.BaseCurrency),
+		Timezone:     req.Timezone,
 	}
 
 	if err := h.userRepo.Create(user); err != nil {
@@ -116,10 +118,12 @@ func (h *AuthHandler) Register(c *gin.Context) {
 
 	response := gin.H{
 		"user": models.UserResponse{
-			ID:        user.ID,
-			Email:     user.Email,
-			Name:      user.Name,
-			CreatedAt: user.CreatedAt,
+			ID:           user.ID,
+			Email:        user.Email,
+			Name:         user.Name,
+			BaseCurrency: user.BaseCurrency,
+			Timezone:     user.Timezone,
+			CreatedAt:    user.CreatedAt,
 		},
 		"token":         token,
 		"refresh_token": refreshToken,
@@ -0,0 +1,387 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type BudgetHandler struct {
+	budgetRepo    *repositories.BudgetRepository
+	userRepo      *repositories.UserRepository
+	categoryRepo  *repositories.CategoryRepository
+	budgetService *services.BudgetService
+	validator     *validator.Validate
+}
+
+func NewBudgetHandler(budgetRepo *repositories.BudgetRepository, userRepo *repositories.UserRepository, categoryRepo *repositories.CategoryRepository, budgetService *services.BudgetService) *BudgetHandler {
+	return &BudgetHandler{
+		budgetRepo:    budgetRepo,
+		userRepo:      userRepo,
+		categoryRepo:  categoryRepo,
+		budgetService: budgetService,
+		validator:     validator.New(),
+	}
+}
+
+// GET BUDGETS BY USER ID
+// GetBudgetsByUserID godoc
+// @Summary Get budgets by user ID
+// @Description Get all budgets for the authenticated user
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param period query string false "Filter by period (weekly, monthly, custom)"
+// @Param category_name query string false "Filter by category name"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Budget]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /budgets [get]
+func (h *BudgetHandler) GetBudgetsByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET BUDGETS BY USER ID
+	budgets, total, totalPages, err := h.budgetRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get budgets")
+		return
+	}
+
+	response := gin.H{
+		"data":        budgets,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Budgets retrieved successfully", response)
+}
+
+// GET BUDGETS STATUS
+// GetBudgetsStatus godoc
+// @Summary Get budgets status
+// @Description Get spent, remaining and percentage used of every budget for its current period, contributions to savings goals do not count as spent
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} utils.Response[[]models.BudgetStatus]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /budgets/status [get]
+func (h *BudgetHandler) GetBudgetsStatus(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET ALL BUDGETS OF USER
+	budgets, err := h.budgetRepo.GetAllByUserID(user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get budgets")
+		return
+	}
+
+	// COMPUTE STATUS FOR THE CURRENT PERIOD
+	now := time.Now()
+	statuses := make([]*models.BudgetStatus, 0, len(budgets))
+	for i := range budgets {
+		status, err := h.budgetService.Status(user, &budgets[i], now)
+		if errors.Is(err, services.ErrExchangeRateNotFound) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to compute budget status")
+			return
+		}
+		statuses = append(statuses, status)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Budgets status retrieved successfully", statuses)
+}
+
+// GET BUDGET BY ID
+// GetBudgetByID godoc
+// @Summary Get a budget by ID
+// @Description Get a budget by ID for the authenticated user
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Budget ID"
+// @Success 200 {object} utils.Response[models.Budget]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /budgets/{id} [get]
+func (h *BudgetHandler) GetBudgetByID(c *gin.Context) {
+	budget, _, ok := h.getOwnedBudget(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Budget retrieved successfully", budget)
+}
+
+// CREATE BUDGET
+// CreateBudget godoc
+// @Summary Create a budget
+// @Description Create a budget for an expense category, the amount is in the user base currency
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param request body models.BudgetRequest true "Budget data"
+// @Success 201 {object} utils.Response[models.Budget]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /budgets [post]
+func (h *BudgetHandler) CreateBudget(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	budget := &models.Budget{
+		UserID: user.ID,
+	}
+
+	if !h.bindBudget(c, user, budget) {
+		return
+	}
+
+	if err := h.budgetRepo.Create(budget); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create budget")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Budget created successfully", budget)
+}
+
+// UPDATE BUDGET
+// UpdateBudget godoc
+// @Summary Update a budget
+// @Description Update a budget for the authenticated user
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Budget ID"
+// @Param request body models.BudgetRequest true "Budget data"
+// @Success 200 {object} utils.Response[models.Budget]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /budgets/{id} [put]
+func (h *BudgetHandler) UpdateBudget(c *gin.Context) {
+	budget, user, ok := h.getOwnedBudget(c)
+	if !ok {
+		return
+	}
+
+	if !h.bindBudget(c, user, budget) {
+		return
+	}
+
+	if err := h.budgetRepo.Update(budget); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update budget")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Budget updated successfully", budget)
+}
+
+// DELETE BUDGET
+// DeleteBudget godoc
+// @Summary Delete a budget
+// @Description Delete a budget for the authenticated user
+// @Tags budgets
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Budget ID"
+// @Success 200 {object} utils.Response[models.Budget]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /budgets/{id} [delete]
+func (h *BudgetHandler) DeleteBudget(c *gin.Context) {
+	budget, _, ok := h.getOwnedBudget(c)
+	if !ok {
+		return
+	}
+
+	if err := h.budgetRepo.Delete(budget); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete budget")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Budget deleted successfully", budget)
+}
+
+// GET THE BUDGET FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *BudgetHandler) getOwnedBudget(c *gin.Context) (*models.Budget, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET BUDGET ID FROM URL PARAM
+	budgetID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid budget ID")
+		return nil, nil, false
+	}
+
+	// GET BUDGET BY ID
+	budget, err := h.budgetRepo.GetByID(uint(budgetID), user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Budget not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF BUDGET BELONGS TO USER
+	if budget.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Budget does not belong to this user")
+		return nil, nil, false
+	}
+
+	return budget, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO budget, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *BudgetHandler) bindBudget(c *gin.Context, user *models.User, budget *models.Budget) bool {
+	var req models.BudgetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	// VALIDATE AMOUNT PRECISION AGAINST THE BASE CURRENCY MINOR UNITS
+	if !req.Amount.FitsExponent(models.CurrencyExponent(user.BaseCurrency)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Amount has more decimal places than "+user.BaseCurrency+" allows")
+		return false
+	}
+
+	// PARSE CUSTOM PERIOD DATES
+	var startDate, endDate *time.Time
+	if req.Period == models.BudgetPeriodCustom {
+		start, _, err := utils.ParseDateTime(req.StartDate, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "start_date: "+err.Error())
+			return false
+		}
+		end, _, err := utils.ParseDateTime(req.EndDate, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "end_date: "+err.Error())
+			return false
+		}
+		if end.Before(start) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "end_date must not be before start_date")
+			return false
+		}
+		startDate, endDate = &start, &end
+	}
+
+	// VALIDATE CATEGORY ID
+	category, err := h.categoryRepo.GetEffectiveByID(user.ID, req.CategoryID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return false
+	}
+
+	// VALIDATE CATEGORY BELONGING TO USER, LEDGER CATEGORIES ARE NOT FOR PERSONAL EXPENSES
+	if !categoryUsable(category, user.ID, nil) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Category does not belong to this user")
+		return false
+	}
+
+	// ONLY SPENDING CAN BE BUDGETED
+	if category.Type != "expense" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Budget category must be an expense category")
+		return false
+	}
+
+	budget.CategoryID = category.ID
+	budget.Category = *category
+	budget.Amount = req.Amount
+	budget.Currency = user.BaseCurrency
+	budget.Period = req.Period
+	budget.StartDate = startDate
+	budget.EndDate = endDate
+	budget.AlertThresholds = models.DefaultAlertThresholds
+	if len(req.AlertThresholds) > 0 {
+		budget.AlertThresholds = models.NewThresholds(req.AlertThresholds)
+	}
+
+	return true
+}
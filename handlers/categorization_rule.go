@@ -0,0 +1,561 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+// MOST CHANGES RETURNED BY A PREVIEW OR APPLY, THE COUNTS COVER ALL OF THEM
+const maxCategorizationChanges = 100
+
+type CategorizationRuleHandler struct {
+	ruleRepo              *repositories.CategorizationRuleRepository
+	userRepo              *repositories.UserRepository
+	categoryRepo          *repositories.CategoryRepository
+	payeeRepo             *repositories.PayeeRepository
+	tagRepo               *repositories.TagRepository
+	categorizationService *services.CategorizationService
+	budgetAlertService    *services.BudgetAlertService
+	validator             *validator.Validate
+}
+
+func NewCategorizationRuleHandler(ruleRepo *repositories.CategorizationRuleRepository, userRepo *repositories.UserRepository, categoryRepo *repositories.CategoryRepository, payeeRepo *repositories.PayeeRepository, tagRepo *repositories.TagRepository, categorizationService *services.CategorizationService, budgetAlertService *services.BudgetAlertService) *CategorizationRuleHandler {
+	return &CategorizationRuleHandler{
+		ruleRepo:              ruleRepo,
+		userRepo:              userRepo,
+		categoryRepo:          categoryRepo,
+		payeeRepo:             payeeRepo,
+		tagRepo:               tagRepo,
+		categorizationService: categorizationService,
+		budgetAlertService:    budgetAlertService,
+		validator:             validator.New(),
+	}
+}
+
+// GET CATEGORIZATION RULES BY USER ID
+// GetCategorizationRulesByUserID godoc
+// @Summary Get categorization rules by user ID
+// @Description Get all categorization rules of the authenticated user
+// @Tags categorization-rules
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by rule name"
+// @Param category_id query int false "Filter by category ID"
+// @Param is_active query bool false "Filter by active state"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.CategorizationRule]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categorization-rules [get]
+func (h *CategorizationRuleHandler) GetCategorizationRulesByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET RULES BY USER ID
+	rules, total, totalPages, err := h.ruleRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		if errors.Is(err, repositories.ErrInvalidFilter) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get categorization rules")
+		return
+	}
+
+	response := gin.H{
+		"data":        rules,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categorization rules retrieved successfully", response)
+}
+
+// GET CATEGORIZATION RULE BY ID
+// GetCategorizationRuleByID godoc
+// @Summary Get categorization rule by ID
+// @Description Get a categorization rule of the authenticated user
+// @Tags categorization-rules
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} utils.Response[models.CategorizationRule]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categorization-rules/{id} [get]
+func (h *CategorizationRuleHandler) GetCategorizationRuleByID(c *gin.Context) {
+	rule, _, ok := h.getOwnedRule(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categorization rule retrieved successfully", rule)
+}
+
+// CREATE CATEGORIZATION RULE
+// CreateCategorizationRule godoc
+// @Summary Create a categorization rule
+// @Description Create a rule that sets the category and adds tags to personal expenses created or imported without a category. Conditions are a name pattern (contains or regex, case insensitive), an amount range and a payee, every condition set must hold. Active rules are tried by priority, lowest first, and the first match wins
+// @Tags categorization-rules
+// @Accept  json
+// @Produce  json
+// @Param request body models.CategorizationRuleRequest true "Rule data"
+// @Success 201 {object} utils.Response[models.CategorizationRule]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categorization-rules [post]
+func (h *CategorizationRuleHandler) CreateCategorizationRule(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	rule := &models.CategorizationRule{
+		UserID: user.ID,
+	}
+
+	if !h.bindRule(c, rule, true) {
+		return
+	}
+
+	if err := h.ruleRepo.Create(rule); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create categorization rule")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Categorization rule created successfully", rule)
+}
+
+// UPDATE CATEGORIZATION RULE
+// UpdateCategorizationRule godoc
+// @Summary Update a categorization rule
+// @Description Replace the conditions and actions of a categorization rule, expenses already categorized are not changed
+// @Tags categorization-rules
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Rule ID"
+// @Param request body models.CategorizationRuleRequest true "Rule data"
+// @Success 200 {object} utils.Response[models.CategorizationRule]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categorization-rules/{id} [put]
+func (h *CategorizationRuleHandler) UpdateCategorizationRule(c *gin.Context) {
+	rule, _, ok := h.getOwnedRule(c)
+	if !ok {
+		return
+	}
+
+	if !h.bindRule(c, rule, true) {
+		return
+	}
+
+	if err := h.ruleRepo.Update(rule); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update categorization rule")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categorization rule updated successfully", rule)
+}
+
+// DELETE CATEGORIZATION RULE
+// DeleteCategorizationRule godoc
+// @Summary Delete a categorization rule
+// @Description Delete a categorization rule, expenses it categorized keep their category and tags
+// @Tags categorization-rules
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Rule ID"
+// @Success 200 {object} utils.Response[models.CategorizationRule]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categorization-rules/{id} [delete]
+func (h *CategorizationRuleHandler) DeleteCategorizationRule(c *gin.Context) {
+	rule, _, ok := h.getOwnedRule(c)
+	if !ok {
+		return
+	}
+
+	if err := h.ruleRepo.Delete(rule); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete categorization rule")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categorization rule deleted successfully", rule)
+}
+
+// PREVIEW CATEGORIZATION RULE
+// PreviewCategorizationRule godoc
+// @Summary Test a categorization rule against existing expenses
+// @Description Run a rule, without saving it, over the personal expenses of the user that are not split and whose category has the same type as the rule category, and list what it would change. Nothing is saved, missing tags are not created
+// @Tags categorization-rules
+// @Accept  json
+// @Produce  json
+// @Param request body models.CategorizationRuleRequest true "Rule data"
+// @Success 200 {object} utils.Response[models.CategorizationResult]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categorization-rules/preview [post]
+func (h *CategorizationRuleHandler) PreviewCategorizationRule(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	rule := &models.CategorizationRule{
+		UserID: user.ID,
+	}
+
+	if !h.bindRule(c, rule, false) {
+		return
+	}
+
+	// AN INACTIVE RULE IS PREVIEWED AS IF IT WERE ACTIVE
+	result, err := h.categorizationService.Preview(user.ID, rule)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to preview categorization rule")
+		return
+	}
+
+	result.Changes = truncateChanges(result.Changes)
+
+	utils.SuccessResponse(c, http.StatusOK, "Categorization rule previewed successfully", result)
+}
+
+// APPLY CATEGORIZATION RULES
+// ApplyCategorizationRules godoc
+// @Summary Apply categorization rules to existing expenses
+// @Description Run the active rules over the personal expenses of the user that are not split, optionally within a date range or limited to the expenses currently in category_id, and add the tags of the first matching rule whose category has the same type (expense or income) as the current one. With overwrite the rule also replaces the current category, even one chosen by hand, use dry_run or category_id to check or narrow the changes first. One call changes at most 1000 expenses, call again while has_more is true. With dry_run nothing is saved and the first changes are listed, otherwise only the counts are returned
+// @Tags categorization-rules
+// @Accept  json
+// @Produce  json
+// @Param request body models.CategorizationApplyRequest true "Apply options"
+// @Success 200 {object} utils.Response[models.CategorizationResult]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categorization-rules/apply [post]
+func (h *CategorizationRuleHandler) ApplyCategorizationRules(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// VALIDATE REQUEST BODY
+	var req models.CategorizationApplyRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// PARSE THE DATE RANGE, A PLAIN to DATE INCLUDES THE WHOLE DAY
+	var from, to *time.Time
+	if req.From != "" {
+		parsed, _, err := utils.ParseDateTime(req.From, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "from: "+err.Error())
+			return
+		}
+		from = &parsed
+	}
+	if req.To != "" {
+		parsed, dateOnly, err := utils.ParseDateTime(req.To, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "to: "+err.Error())
+			return
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		to = &parsed
+	}
+	if from != nil && to != nil && !to.After(*from) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "to must be after from")
+		return
+	}
+
+	// ONLY RECATEGORIZE EXPENSES IN ONE CATEGORY OF THE USER
+	if req.CategoryID != 0 {
+		category, err := h.categoryRepo.GetEffectiveByID(user.ID, req.CategoryID)
+		if err != nil || !categoryUsable(category, user.ID, nil) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+			return
+		}
+	}
+
+	result, err := h.categorizationService.Apply(user.ID, from, to, req.CategoryID, req.Overwrite, req.DryRun)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to apply categorization rules")
+		return
+	}
+
+	// RAISE BUDGET ALERTS FOR THE CATEGORIES SPENDING MOVED TO
+	if !req.DryRun {
+		categoryIDs := make([]uint, 0, len(result.Changes))
+		for _, change := range result.Changes {
+			if change.CategoryID != change.FromCategoryID {
+				categoryIDs = append(categoryIDs, change.CategoryID)
+			}
+		}
+		if len(categoryIDs) > 0 {
+			if _, err := h.budgetAlertService.Evaluate(user, categoryIDs...); err != nil {
+				log.Printf("Failed to evaluate budget alerts for user %d: %v", user.ID, err)
+			}
+		}
+	}
+
+	// THE CHANGES ARE ONLY LISTED ON A DRY RUN
+	result.Changes = truncateChanges(result.Changes)
+	if !req.DryRun {
+		result.Changes = nil
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Categorization rules applied successfully", result)
+}
+
+// GET THE RULE FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *CategorizationRuleHandler) getOwnedRule(c *gin.Context) (*models.CategorizationRule, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET RULE ID FROM URL PARAM
+	ruleID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid categorization rule ID")
+		return nil, nil, false
+	}
+
+	// GET RULE BY ID
+	rule, err := h.ruleRepo.GetByID(uint(ruleID), user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Categorization rule not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF RULE BELONGS TO USER
+	if rule.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Categorization rule does not belong to this user")
+		return nil, nil, false
+	}
+
+	return rule, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO rule, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+// createTags CREATES THE MISSING TAGS, OTHERWISE THEY ARE LEFT UNSAVED WITHOUT AN ID
+func (h *CategorizationRuleHandler) bindRule(c *gin.Context, rule *models.CategorizationRule, createTags bool) bool {
+	var req models.CategorizationRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Rule name is required")
+		return false
+	}
+
+	// A PATTERN WITHOUT A MATCH TYPE IS A CONTAINS MATCH
+	pattern := strings.TrimSpace(req.Pattern)
+	matchType := req.MatchType
+	if pattern != "" && matchType == "" {
+		matchType = models.RuleMatchContains
+	}
+	if pattern == "" {
+		matchType = ""
+	}
+	if matchType == models.RuleMatchRegex {
+		if _, err := services.CompileRulePattern(pattern); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return false
+		}
+	}
+
+	if pattern == "" && req.MinAmount == nil && req.MaxAmount == nil && req.PayeeID == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "A rule needs a pattern, an amount range or a payee")
+		return false
+	}
+	if req.MinAmount != nil && req.MaxAmount != nil && *req.MinAmount > *req.MaxAmount {
+		utils.ErrorResponse(c, http.StatusBadRequest, "min_amount must not be greater than max_amount")
+		return false
+	}
+
+	// VALIDATE PAYEE BELONGING TO USER
+	var payee *models.Payee
+	if req.PayeeID != nil {
+		var err error
+		payee, err = h.payeeRepo.GetByID(*req.PayeeID)
+		if err != nil || payee.UserID != rule.UserID {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid payee ID")
+			return false
+		}
+		payee.Aliases = nil
+	}
+
+	// RULES CATEGORIZE PERSONAL EXPENSES, SO THE CATEGORY IS A DEFAULT OR PERSONAL ONE
+	category, err := h.categoryRepo.GetEffectiveByID(rule.UserID, req.CategoryID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return false
+	}
+	if !categoryUsable(category, rule.UserID, nil) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Category does not belong to this user")
+		return false
+	}
+
+	tags, ok := h.resolveRuleTags(c, rule.UserID, req.Tags, createTags)
+	if !ok {
+		return false
+	}
+
+	rule.Name = name
+	rule.Priority = req.Priority
+	rule.IsActive = req.IsActive == nil || *req.IsActive
+	rule.MatchType = matchType
+	rule.Pattern = pattern
+	rule.MinAmount = req.MinAmount
+	rule.MaxAmount = req.MaxAmount
+	rule.PayeeID = req.PayeeID
+	rule.Payee = payee
+	rule.CategoryID = category.ID
+	rule.Category = *category
+	rule.Tags = tags
+
+	return true
+}
+
+// GET THE USER TAGS WITH THE GIVEN NAMES, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+// WITHOUT create THE MISSING ONES ARE RETURNED UNSAVED
+func (h *CategorizationRuleHandler) resolveRuleTags(c *gin.Context, userID uint, names []string, create bool) ([]models.Tag, bool) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = models.NormalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+
+	if create {
+		tags, err := h.tagRepo.FindOrCreateByNames(userID, normalized)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to save tags")
+			return nil, false
+		}
+		return tags, true
+	}
+
+	tags := make([]models.Tag, 0, len(normalized))
+	for _, name := range normalized {
+		tag, err := h.tagRepo.GetByName(userID, name)
+		if err != nil {
+			tags = append(tags, models.Tag{UserID: userID, Name: name})
+			continue
+		}
+		tags = append(tags, *tag)
+	}
+
+	return tags, true
+}
+
+// KEEP THE FIRST maxCategorizationChanges CHANGES
+func truncateChanges(changes []models.CategorizationChange) []models.CategorizationChange {
+	if len(changes) > maxCategorizationChanges {
+		return changes[:maxCategorizationChanges]
+	}
+
+	return changes
+}
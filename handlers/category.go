@@ -1,11 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"go-expense-tracker-api/middleware"
 	"go-expense-tracker-api/models"
 	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
 	"go-expense-tracker-api/utils"
 	"net/http"
+	"slices"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -13,23 +16,25 @@ import (
 )
 
 type CategoryHandler struct {
-	categoryRepo *repositories.CategoryRepository
-	userRepo     *repositories.UserRepository
-	validator    *validator.Validate
+	categoryRepo  *repositories.CategoryRepository
+	userRepo      *repositories.UserRepository
+	ledgerService *services.LedgerService
+	validator     *validator.Validate
 }
 
-func NewCategoryHandler(categoryRepo *repositories.CategoryRepository, userRepo *repositories.UserRepository) *CategoryHandler {
+func NewCategoryHandler(categoryRepo *repositories.CategoryRepository, userRepo *repositories.UserRepository, ledgerService *services.LedgerService) *CategoryHandler {
 	return &CategoryHandler{
-		categoryRepo: categoryRepo,
-		userRepo:     userRepo,
-		validator:    validator.New(),
+		categoryRepo:  categoryRepo,
+		userRepo:      userRepo,
+		ledgerService: ledgerService,
+		validator:     validator.New(),
 	}
 }
 
 // GET CATEGORIES BY USER ID
 // GetCategoriesByUserID godoc
 // @Summary Get categories by user ID
-// @Description Get all categories for the authenticated user
+// @Description Get the categories of the authenticated user or ledger and the default categories the user did not hide, under the name the user gave them. With view=tree every category is returned by name, nested under its parent in children, without pagination or filters
 // @Tags categories
 // @Accept  json
 // @Produce  json
@@ -39,7 +44,11 @@ func NewCategoryHandler(categoryRepo *repositories.CategoryRepository, userRepo
 // @Param order query string false "Sort order (asc or desc)" default(asc)
 // @Param name query string false "Filter by category name"
 // @Param type query string false "Filter by category type"
+// @Param parent_id query int false "Filter by parent category, 0 for top level categories"
+// @Param view query string false "Set to tree for the nested category tree"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
 // @Success 200 {object} utils.ResponseWithPagination[[]models.Category]
+// @Failure 400 {object} utils.Response[any]
 // @Failure 401 {object} utils.Response[any]
 // @Failure 500 {object} utils.Response[any]
 // @Security BearerAuth
@@ -61,9 +70,27 @@ func (h *CategoryHandler) GetCategoriesByUserID(c *gin.Context) {
 
 	// GET QUERY PARAMETERS
 	queryParams, _ := c.Get("queryParams")
+	params := queryParams.(middleware.QueryParams)
+	params.LedgerID = currentLedgerID(c)
+
+	// TREE VIEW OF EVERY CATEGORY
+	if params.Filters["view"] == "tree" {
+		categories, err := h.categoryRepo.GetAllByUserID(user.ID, params.LedgerID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get categories")
+			return
+		}
 
-	// GET CATEGORIES BY USER ID
-	categories, total, totalPages, err := h.categoryRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+		utils.SuccessResponse(c, http.StatusOK, "Categories retrieved successfully", models.BuildCategoryTree(categories))
+		return
+	}
+
+	// GET CATEGORIES BY USER ID OR LEDGER
+	categories, total, totalPages, err := h.categoryRepo.GetByUserID(user.ID, params)
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get categories")
 		return
@@ -83,17 +110,25 @@ func (h *CategoryHandler) GetCategoriesByUserID(c *gin.Context) {
 // GET DEFAULT CATEGORIES
 // GetDefaultCategories godoc
 // @Summary Get default categories
-// @Description Get default categories for all users
+// @Description Get the default categories shared by all users, with the name the authenticated user gave them and hidden set on the ones they hid
 // @Tags categories
 // @Accept  json
 // @Produce  json
 // @Success 200 {object} utils.Response[[]models.Category]
+// @Failure 401 {object} utils.Response[any]
 // @Failure 500 {object} utils.Response[any]
 // @Security BearerAuth
 // @Router /categories/default [get]
 func (h *CategoryHandler) GetDefaultCategories(c *gin.Context) {
-	// GET DEFAULT CATEGORIES BY USER ID
-	categories, err := h.categoryRepo.GetDefaultCategories()
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// GET DEFAULT CATEGORIES WITH THE OVERRIDES OF THE USER
+	categories, err := h.categoryRepo.GetDefaultCategories(userID.(uint))
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get default categories")
 		return
@@ -110,6 +145,7 @@ func (h *CategoryHandler) GetDefaultCategories(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param request body models.CategoryRequest true "Category data"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
 // @Success 201 {object} utils.Response[models.Category]
 // @Failure 400 {object} utils.Response[any]
 // @Failure 401 {object} utils.Response[any]
@@ -131,6 +167,12 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 		return
 	}
 
+	// VIEWERS CANNOT ADD CATEGORIES TO THE SELECTED LEDGER
+	if member := currentLedgerMember(c); member != nil && !member.CanWrite() {
+		utils.ErrorResponse(c, http.StatusForbidden, "Viewers cannot change this ledger")
+		return
+	}
+
 	var req models.Category
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -148,9 +190,13 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 	category := &models.Category{
 		Name:      req.Name,
 		UserID:    &user.ID,
+		LedgerID:  currentLedgerID(c),
 		Type:      req.Type,
 		IsDefault: false,
 	}
+	if !h.resolveParent(c, category, req.ParentID) {
+		return
+	}
 	categories := []*models.Category{category}
 
 	if err := h.categoryRepo.CreateMany(categories); err != nil {
@@ -169,6 +215,7 @@ func (h *CategoryHandler) CreateCategory(c *gin.Context) {
 // @Accept  json
 // @Produce  json
 // @Param request body []models.CategoryRequest true "Category data"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
 // @Success 201 {object} utils.Response[[]models.Category]
 // @Failure 400 {object} utils.Response[any]
 // @Failure 401 {object} utils.Response[any]
@@ -190,6 +237,12 @@ func (h *CategoryHandler) CreateMultipleCategories(c *gin.Context) {
 		return
 	}
 
+	// VIEWERS CANNOT ADD CATEGORIES TO THE SELECTED LEDGER
+	if member := currentLedgerMember(c); member != nil && !member.CanWrite() {
+		utils.ErrorResponse(c, http.StatusForbidden, "Viewers cannot change this ledger")
+		return
+	}
+
 	var req []models.CategoryRequest
 
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -208,12 +261,17 @@ func (h *CategoryHandler) CreateMultipleCategories(c *gin.Context) {
 	// CREATE CATEGORIES
 	categories := make([]*models.Category, 0, len(req))
 	for _, item := range req {
-		categories = append(categories, &models.Category{
+		category := &models.Category{
 			Name:      item.Name,
 			UserID:    &user.ID,
+			LedgerID:  currentLedgerID(c),
 			Type:      item.Type,
 			IsDefault: false,
-		})
+		}
+		if !h.resolveParent(c, category, item.ParentID) {
+			return
+		}
+		categories = append(categories, category)
 	}
 
 	if err := h.categoryRepo.CreateMany(categories); err != nil {
@@ -267,9 +325,8 @@ func (h *CategoryHandler) GetCategoryByID(c *gin.Context) {
 		return
 	}
 
-	// CHECK IF CATEGORY BELONGS TO USER
-	if category.UserID == nil || *category.UserID != user.ID {
-		utils.ErrorResponse(c, http.StatusForbidden, "You do not have permission to access this category")
+	// CHECK IF CATEGORY BELONGS TO USER OR TO ONE OF THEIR LEDGERS
+	if !h.authorizeCategory(c, user, category, false, "You do not have permission to access this category") {
 		return
 	}
 
@@ -332,16 +389,36 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 		return
 	}
 
-	// CHECK IF CATEGORY BELONGS TO USER
-	if category.UserID == nil || *category.UserID != user.ID {
-		utils.ErrorResponse(c, http.StatusForbidden, "You do not have permission to update this category")
+	// CHECK IF CATEGORY BELONGS TO USER OR TO ONE OF THEIR LEDGERS
+	if !h.authorizeCategory(c, user, category, true, "You do not have permission to update this category") {
 		return
 	}
 
-	// UPDATE CATEGORY
+	// A PARENT AND ITS SUBCATEGORIES SHARE ONE TYPE
+	if req.Type != category.Type {
+		ids, err := h.categoryRepo.GetDescendantIDs(category.ID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update category")
+			return
+		}
+		if len(ids) > 1 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Move the subcategories out before changing the type of this category")
+			return
+		}
+	}
+
+	// UPDATE CATEGORY, AN OMITTED PARENT IS KEPT AND CHECKED AGAINST THE NEW TYPE
 	category.Name = req.Name
 	category.Type = req.Type
 
+	parentID := req.ParentID
+	if parentID == nil {
+		parentID = category.ParentID
+	}
+	if !h.resolveParent(c, category, parentID) {
+		return
+	}
+
 	if err := h.categoryRepo.Update(category); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update category")
 		return
@@ -353,13 +430,18 @@ func (h *CategoryHandler) UpdateCategory(c *gin.Context) {
 // DELETE CATEGORY BY ID
 // DeleteCategory godoc
 // @Summary Delete a category
-// @Description Delete a category for the authenticated user
+// @Description Delete a category for the authenticated user, its subcategories move up to its parent. A category still used by expenses (trashed ones included), split lines, recurring expenses, budgets or categorization rules needs reassign_to, everything on it then moves to that category as in a merge, keeping the budgets already on that category
 // @Tags categories
 // @Accept  json
 // @Produce  json
 // @Param id path int true "Category ID"
-// @Success 200 {object} utils.Response[models.DeleteCategoryResponse]
+// @Param reassign_to query int false "Category to move the expenses, split lines, recurring expenses, budgets, categorization rules and subcategories to"
+// @Success 200 {object} utils.Response[models.CategoryMergeResult]
+// @Failure 400 {object} utils.Response[any]
 // @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
 // @Failure 500 {object} utils.Response[any]
 // @Security BearerAuth
 // @Router /categories/{id} [delete]
@@ -392,17 +474,432 @@ func (h *CategoryHandler) DeleteCategory(c *gin.Context) {
 		return
 	}
 
-	// CHECK IF CATEGORY BELONGS TO USER
-	if category.UserID == nil || *category.UserID != user.ID {
-		utils.ErrorResponse(c, http.StatusForbidden, "You do not have permission to delete this category")
+	// CHECK IF CATEGORY BELONGS TO USER OR TO ONE OF THEIR LEDGERS
+	if !h.authorizeCategory(c, user, category, true, "You do not have permission to delete this category") {
+		return
+	}
+
+	// MOVE EVERYTHING TO THE REASSIGN TARGET
+	if value := c.Query("reassign_to"); value != "" {
+		targetID, err := strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid reassign_to category ID")
+			return
+		}
+
+		result, ok := h.mergeCategory(c, category, uint(targetID))
+		if !ok {
+			return
+		}
+
+		utils.SuccessResponse(c, http.StatusOK, "Category deleted successfully", result)
+		return
+	}
+
+	// A CATEGORY IN USE NEEDS A TARGET SO NOTHING LOSES ITS CATEGORY
+	count, err := h.categoryRepo.CountUsage(category.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete category")
+		return
+	}
+	if count > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Category is in use, pass reassign_to to move its expenses, recurring expenses, budgets and rules to another category")
 		return
 	}
 
 	// DELETE CATEGORY
-	if err := h.categoryRepo.Delete(uint(categoryID)); err != nil {
+	if err := h.categoryRepo.Delete(category); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete category")
 		return
 	}
 
-	utils.SuccessResponse(c, http.StatusOK, "Category deleted successfully", category)
+	utils.SuccessResponse(c, http.StatusOK, "Category deleted successfully", models.CategoryMergeResult{Category: *category})
+}
+
+// MERGE CATEGORY
+// MergeCategory godoc
+// @Summary Merge a category into another
+// @Description Move the expenses (trashed ones included), split lines, recurring expenses, budgets, categorization rules and subcategories of a category to a category of the same type in one transaction, then delete it. A budget on the category is deleted instead when its owner already has a budget on the target. Reports how many rows moved
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Category ID"
+// @Param request body models.CategoryMergeRequest true "Target category"
+// @Success 200 {object} utils.Response[models.CategoryMergeResult]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categories/{id}/merge [post]
+func (h *CategoryHandler) MergeCategory(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET CATEGORY ID FROM PATH
+	categoryID, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return
+	}
+
+	// GET CATEGORY BY ID
+	category, err := h.categoryRepo.GetByID(uint(categoryID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Category not found")
+		return
+	}
+
+	var req models.CategoryMergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// CHECK IF CATEGORY BELONGS TO USER OR TO ONE OF THEIR LEDGERS
+	if !h.authorizeCategory(c, user, category, true, "You do not have permission to merge this category") {
+		return
+	}
+
+	result, ok := h.mergeCategory(c, category, req.TargetID)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category merged successfully", result)
+}
+
+// MERGE source INTO THE CATEGORY targetID, WHICH MUST BE OF THE SAME TYPE, USABLE BY THE EXPENSES OF source
+// AND NOT ONE OF ITS SUBCATEGORIES. WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *CategoryHandler) mergeCategory(c *gin.Context, source *models.Category, targetID uint) (*models.CategoryMergeResult, bool) {
+	if targetID == source.ID {
+		utils.ErrorResponse(c, http.StatusBadRequest, "A category cannot be merged into itself")
+		return nil, false
+	}
+
+	target, err := h.categoryRepo.GetByID(targetID)
+	if err != nil || !categoryUsable(target, *source.UserID, source.LedgerID) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid target category ID")
+		return nil, false
+	}
+
+	if target.Type != source.Type {
+		utils.ErrorResponse(c, http.StatusBadRequest, "The target category must be of the same type")
+		return nil, false
+	}
+
+	ids, err := h.categoryRepo.GetDescendantIDs(source.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to merge category")
+		return nil, false
+	}
+	if slices.Contains(ids, target.ID) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "A category cannot be merged into one of its subcategories")
+		return nil, false
+	}
+
+	// SUBCATEGORIES MOVE UNDER THE TARGET, OR UP TO THE PARENT OF THE SOURCE WHEN THE TARGET IS IN ANOTHER SCOPE
+	childParentID := source.ParentID
+	if canParentCategory(target, source) {
+		childParentID = &target.ID
+	}
+
+	result, err := h.categoryRepo.Merge(source, target, childParentID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to merge category")
+		return nil, false
+	}
+
+	return result, true
+}
+
+// OVERRIDE DEFAULT CATEGORY
+// SetCategoryOverride godoc
+// @Summary Rename or hide a default category
+// @Description Rename or hide a default category for the authenticated user only. Hidden categories are left out of the category list, expenses may still use them
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Default category ID"
+// @Param request body models.CategoryOverrideRequest true "Override data"
+// @Success 200 {object} utils.Response[models.Category]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categories/{id}/override [put]
+func (h *CategoryHandler) SetCategoryOverride(c *gin.Context) {
+	category, user, ok := h.getDefaultCategory(c)
+	if !ok {
+		return
+	}
+
+	var req models.CategoryOverrideRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	override := &models.CategoryOverride{
+		UserID:     user.ID,
+		CategoryID: category.ID,
+		Name:       req.Name,
+		Hidden:     req.Hidden,
+	}
+
+	if err := h.categoryRepo.SaveOverride(override); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to override category")
+		return
+	}
+
+	// RESPOND WITH THE CATEGORY AS THE USER NOW SEES IT
+	effective, err := h.categoryRepo.GetEffectiveByID(user.ID, category.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get category")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category overridden successfully", effective)
+}
+
+// RESET DEFAULT CATEGORY
+// DeleteCategoryOverride godoc
+// @Summary Reset a default category
+// @Description Remove the name and hidden flag the authenticated user set on a default category
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Default category ID"
+// @Success 200 {object} utils.Response[models.Category]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categories/{id}/override [delete]
+func (h *CategoryHandler) DeleteCategoryOverride(c *gin.Context) {
+	category, user, ok := h.getDefaultCategory(c)
+	if !ok {
+		return
+	}
+
+	if err := h.categoryRepo.DeleteOverride(user.ID, category.ID); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to reset category")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Category reset successfully", category)
+}
+
+// CLONE DEFAULT CATEGORY
+// CloneCategory godoc
+// @Summary Clone a default category
+// @Description Create a personal category from a default category, which the user may then change like any of their categories. Optionally hide the default category
+// @Tags categories
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Default category ID"
+// @Param request body models.CategoryCloneRequest false "Clone options"
+// @Success 201 {object} utils.Response[models.Category]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /categories/{id}/clone [post]
+func (h *CategoryHandler) CloneCategory(c *gin.Context) {
+	category, user, ok := h.getDefaultCategory(c)
+	if !ok {
+		return
+	}
+
+	// THE BODY IS OPTIONAL
+	var req models.CategoryCloneRequest
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// START FROM THE DEFAULT CATEGORY AS THE USER SEES IT
+	effective, err := h.categoryRepo.GetEffectiveByID(user.ID, category.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to clone category")
+		return
+	}
+
+	clone := &models.Category{
+		Name:      effective.Name,
+		UserID:    &user.ID,
+		Type:      effective.Type,
+		IsDefault: false,
+	}
+	if req.Name != "" {
+		clone.Name = req.Name
+	}
+	if req.Type != "" {
+		clone.Type = req.Type
+	}
+
+	if err := h.categoryRepo.CreateMany([]*models.Category{clone}); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to clone category")
+		return
+	}
+
+	// HIDE THE DEFAULT CATEGORY, KEEPING THE NAME THE USER GAVE IT
+	if req.HideDefault {
+		override := &models.CategoryOverride{
+			UserID:     user.ID,
+			CategoryID: category.ID,
+			Hidden:     true,
+		}
+		if effective.Name != category.Name {
+			override.Name = effective.Name
+		}
+
+		if err := h.categoryRepo.SaveOverride(override); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Category cloned but failed to hide the default category")
+			return
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Category cloned successfully", clone)
+}
+
+// GET THE DEFAULT CATEGORY FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR IT IS NOT A DEFAULT CATEGORY
+func (h *CategoryHandler) getDefaultCategory(c *gin.Context) (*models.Category, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET CATEGORY ID FROM URL PARAM
+	categoryID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return nil, nil, false
+	}
+
+	// GET CATEGORY BY ID
+	category, err := h.categoryRepo.GetByID(uint(categoryID))
+	if err != nil || !category.IsDefault || category.UserID != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Default category not found")
+		return nil, nil, false
+	}
+
+	return category, user, true
+}
+
+// CHECK THE USER MAY READ, OR CHANGE WHEN write IS SET, A CATEGORY OF THEIR OWN OR OF ONE OF THEIR LEDGERS
+// DEFAULT CATEGORIES BELONG TO NOBODY. WRITES AN ERROR RESPONSE AND RETURNS FALSE WHEN DENIED
+func (h *CategoryHandler) authorizeCategory(c *gin.Context, user *models.User, category *models.Category, write bool, deniedMessage string) bool {
+	if category.UserID == nil {
+		utils.ErrorResponse(c, http.StatusForbidden, deniedMessage)
+		return false
+	}
+
+	_, err := h.ledgerService.Authorize(user.ID, *category.UserID, category.LedgerID, write)
+
+	return !ledgerAccessError(c, err, http.StatusForbidden, deniedMessage)
+}
+
+// SET THE PARENT OF category, NIL OR 0 MAKES IT A TOP LEVEL CATEGORY. THE PARENT MUST BE OF THE SAME SCOPE AND TYPE
+// AND NOT category ITSELF OR ONE OF ITS SUBCATEGORIES. WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *CategoryHandler) resolveParent(c *gin.Context, category *models.Category, parentID *uint) bool {
+	if parentID == nil || *parentID == 0 {
+		category.ParentID = nil
+		return true
+	}
+
+	parent, err := h.categoryRepo.GetByID(*parentID)
+	if err != nil || !canParentCategory(parent, category) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid parent category ID")
+		return false
+	}
+
+	if parent.Type != category.Type {
+		utils.ErrorResponse(c, http.StatusBadRequest, "The parent category must be of the same type")
+		return false
+	}
+
+	// PREVENT CYCLES
+	if category.ID != 0 {
+		ids, err := h.categoryRepo.GetDescendantIDs(category.ID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to check the parent category")
+			return false
+		}
+		if slices.Contains(ids, parent.ID) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "A category cannot be moved under itself or one of its subcategories")
+			return false
+		}
+	}
+
+	category.ParentID = &parent.ID
+
+	return true
+}
+
+// CHECK parent MAY HAVE child AS A SUBCATEGORY: A CATEGORY OF THE SAME SCOPE, OR A DEFAULT CATEGORY
+// SUBCATEGORIES OF DEFAULT CATEGORIES ARE NEVER DEFAULT THEMSELVES
+func canParentCategory(parent, child *models.Category) bool {
+	if child.IsDefault {
+		return false
+	}
+	if parent.IsDefault {
+		return true
+	}
+
+	return sameCategoryScope(parent, child)
+}
+
+// CHECK TWO CATEGORIES BELONG TO THE SAME LEDGER, OR ARE BOTH PERSONAL CATEGORIES OF THE SAME USER
+func sameCategoryScope(a, b *models.Category) bool {
+	if a.LedgerID != nil || b.LedgerID != nil {
+		return a.LedgerID != nil && b.LedgerID != nil && *a.LedgerID == *b.LedgerID
+	}
+
+	return a.UserID != nil && b.UserID != nil && *a.UserID == *b.UserID
 }
@@ -0,0 +1,41 @@
+package handlers
+
+import (
+	"go-expense-tracker-api/models"
+	"testing"
+)
+
+func TestCanParentCategory(t *testing.T) {
+	user, otherUser := uint(1), uint(2)
+	ledger, otherLedger := uint(10), uint(11)
+
+	defaultCategory := &models.Category{ID: 1, IsDefault: true}
+	personal := &models.Category{ID: 2, UserID: &user}
+	otherPersonal := &models.Category{ID: 3, UserID: &otherUser}
+	ledgerCategory := &models.Category{ID: 4, UserID: &user, LedgerID: &ledger}
+	otherLedgerCategory := &models.Category{ID: 5, UserID: &user, LedgerID: &otherLedger}
+
+	tests := []struct {
+		name          string
+		parent, child *models.Category
+		want          bool
+	}{
+		{"default parent of a personal category", defaultCategory, personal, true},
+		{"default parent of a ledger category", defaultCategory, ledgerCategory, true},
+		{"personal parent of a personal category of the same user", personal, &models.Category{UserID: &user}, true},
+		{"personal parent of another user's category", personal, otherPersonal, false},
+		{"personal parent of a ledger category", personal, ledgerCategory, false},
+		{"ledger parent of a personal category", ledgerCategory, personal, false},
+		{"ledger parent of a category of the same ledger", ledgerCategory, &models.Category{UserID: &otherUser, LedgerID: &ledger}, true},
+		{"ledger parent of a category of another ledger", ledgerCategory, otherLedgerCategory, false},
+		{"a default category never gets a parent", personal, &models.Category{IsDefault: true}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canParentCategory(tt.parent, tt.child); got != tt.want {
+				t.Errorf("canParentCategory() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExchangeRateHandler struct {
+	exchangeRateRepo    *repositories.ExchangeRateRepository
+	userRepo            *repositories.UserRepository
+	exchangeRateService *services.ExchangeRateService
+}
+
+func NewExchangeRateHandler(exchangeRateRepo *repositories.ExchangeRateRepository, userRepo *repositories.UserRepository, exchangeRateService *services.ExchangeRateService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{
+		exchangeRateRepo:    exchangeRateRepo,
+		userRepo:            userRepo,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// GET EXCHANGE RATES
+// GetExchangeRates godoc
+// @Summary Get exchange rates
+// @Description Get shared exchange rates and the rates imported by the authenticated user
+// @Tags exchange-rates
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param base query string false "Filter by base currency"
+// @Param currency query string false "Filter by quote currency"
+// @Param date query string false "Filter by date (YYYY-MM-DD)"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.ExchangeRate]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /exchange-rates [get]
+func (h *ExchangeRateHandler) GetExchangeRates(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET EXCHANGE RATES
+	rates, total, totalPages, err := h.exchangeRateRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get exchange rates")
+		return
+	}
+
+	response := gin.H{
+		"data":        rates,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Exchange rates retrieved successfully", response)
+}
+
+// IMPORT EXCHANGE RATES
+// ImportExchangeRates godoc
+// @Summary Import exchange rates
+// @Description Import exchange rates from a CSV (date,base,currency,rate) or ECB eurofxref XML file
+// @Tags exchange-rates
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV or XML file"
+// @Success 201 {object} utils.Response[models.ExchangeRateImportResponse]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /exchange-rates/import [post]
+func (h *ExchangeRateHandler) ImportExchangeRates(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET UPLOADED FILE
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	// PARSE AND SAVE RATES
+	imported, err := h.exchangeRateService.Import(user.ID, fileHeader.Filename, file)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to import exchange rates: "+err.Error())
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Exchange rates imported successfully", models.ExchangeRateImportResponse{Imported: imported})
+}
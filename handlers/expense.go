@@ -1,30 +1,57 @@
 package handlers
 
 import (
+	"encoding/csv"
+	"errors"
 	"go-expense-tracker-api/middleware"
 	"go-expense-tracker-api/models"
 	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
 	"go-expense-tracker-api/utils"
+	"log"
 	"net/http"
 	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
 
 	"github.com/gin-gonic/gin"
 	"github.com/go-playground/validator/v10"
 )
 
 type ExpenseHandler struct {
-	expenseRepo  *repositories.ExpenseRepository
-	userRepo     *repositories.UserRepository
-	categoryRepo *repositories.CategoryRepository
-	validator    *validator.Validate
+	expenseRepo           *repositories.ExpenseRepository
+	userRepo              *repositories.UserRepository
+	categoryRepo          *repositories.CategoryRepository
+	tagRepo               *repositories.TagRepository
+	payeeRepo             *repositories.PayeeRepository
+	accountRepo           *repositories.AccountRepository
+	goalRepo              *repositories.GoalRepository
+	exchangeRateService   *services.ExchangeRateService
+	payeeService          *services.PayeeService
+	budgetAlertService    *services.BudgetAlertService
+	trashService          *services.TrashService
+	ledgerService         *services.LedgerService
+	categorizationService *services.CategorizationService
+	validator             *validator.Validate
 }
 
-func NewExpenseHandler(expenseRepo *repositories.ExpenseRepository, userRepo *repositories.UserRepository, categoryRepo *repositories.CategoryRepository) *ExpenseHandler {
+func NewExpenseHandler(expenseRepo *repositories.ExpenseRepository, userRepo *repositories.UserRepository, categoryRepo *repositories.CategoryRepository, tagRepo *repositories.TagRepository, payeeRepo *repositories.PayeeRepository, accountRepo *repositories.AccountRepository, goalRepo *repositories.GoalRepository, exchangeRateService *services.ExchangeRateService, payeeService *services.PayeeService, budgetAlertService *services.BudgetAlertService, trashService *services.TrashService, ledgerService *services.LedgerService, categorizationService *services.CategorizationService) *ExpenseHandler {
 	return &ExpenseHandler{
-		expenseRepo:  expenseRepo,
-		userRepo:     userRepo,
-		categoryRepo: categoryRepo,
-		validator:    validator.New(),
+		expenseRepo:           expenseRepo,
+		userRepo:              userRepo,
+		categoryRepo:          categoryRepo,
+		tagRepo:               tagRepo,
+		payeeRepo:             payeeRepo,
+		accountRepo:           accountRepo,
+		goalRepo:              goalRepo,
+		exchangeRateService:   exchangeRateService,
+		payeeService:          payeeService,
+		budgetAlertService:    budgetAlertService,
+		trashService:          trashService,
+		ledgerService:         ledgerService,
+		categorizationService: categorizationService,
+		validator:             validator.New(),
 	}
 }
 
@@ -41,7 +68,17 @@ func NewExpenseHandler(expenseRepo *repositories.ExpenseRepository, userRepo *re
 // @Param order query string false "Sort order (asc or desc)" default(asc)
 // @Param name query string false "Filter by expense name"
 // @Param category_name query string false "Filter by category name"
+// @Param category_id query int false "Filter by category, including its subcategories"
 // @Param category_type query string false "Filter by category type"
+// @Param from query string false "Spent on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Spent on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param tags query string false "Comma separated tag names"
+// @Param tags_mode query string false "Match any or all of the tags" default(any)
+// @Param payee_id query int false "Filter by payee ID"
+// @Param account_id query int false "Filter by account ID"
+// @Param account_type query string false "Filter by account type (cash, bank, credit_card, savings, other)"
+// @Param goal_id query int false "Filter by savings goal"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
 // @Success 200 {object} utils.ResponseWithPagination[[]models.Expense]
 // @Failure 401 {object} utils.Response[any]
 // @Failure 500 {object} utils.Response[any]
@@ -64,9 +101,16 @@ func (h *ExpenseHandler) GetExpensesByUserID(c *gin.Context) {
 
 	// GET QUERY PARAMETERS
 	queryParams, _ := c.Get("queryParams")
+	params := queryParams.(middleware.QueryParams)
+	params.Location = user.Location()
+	params.LedgerID = currentLedgerID(c)
 
 	// GET EXPENSES BY USER ID
-	expenses, total, totalPages, err := h.expenseRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	expenses, total, totalPages, err := h.expenseRepo.GetByUserID(user.ID, params)
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get expenses")
 		return
@@ -84,14 +128,136 @@ func (h *ExpenseHandler) GetExpensesByUserID(c *gin.Context) {
 	utils.SuccessResponse(c, http.StatusOK, "Expenses retrieved successfully", response)
 }
 
+// EXPORT EXPENSES AS CSV
+// ExportExpenses godoc
+// @Summary Export expenses as CSV
+// @Description Stream every expense matching the filters and sorting of GET /expenses as CSV, without pagination. Names starting with =, +, - or @ are prefixed with ' so spreadsheets do not run them as formulas
+// @Tags expenses
+// @Produce  text/csv
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by expense name"
+// @Param category_name query string false "Filter by category name"
+// @Param category_id query int false "Filter by category, including its subcategories"
+// @Param category_type query string false "Filter by category type"
+// @Param from query string false "Spent on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Spent on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param tags query string false "Comma separated tag names"
+// @Param tags_mode query string false "Match any or all of the tags" default(any)
+// @Param payee_id query int false "Filter by payee ID"
+// @Param account_id query int false "Filter by account ID"
+// @Param account_type query string false "Filter by account type (cash, bank, credit_card, savings, other)"
+// @Param goal_id query int false "Filter by savings goal"
+// @Param delimiter query string false "Field delimiter, a single character or one of comma, semicolon, tab, pipe" default(comma)
+// @Param header query bool false "Include a header row" default(true)
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
+// @Success 200 {string} string "CSV file"
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/export.csv [get]
+func (h *ExpenseHandler) ExportExpenses(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// PARSE CSV OPTIONS
+	delimiter, err := parseCSVDelimiter(c.DefaultQuery("delimiter", "comma"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	withHeader, err := strconv.ParseBool(c.DefaultQuery("header", "true"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "header must be true or false")
+		return
+	}
+
+	// GET QUERY PARAMETERS, CSV OPTIONS ARE NOT FILTERS
+	queryParams, _ := c.Get("queryParams")
+	params := queryParams.(middleware.QueryParams)
+	params.Location = user.Location()
+	params.LedgerID = currentLedgerID(c)
+	params.Filters = make(map[string]string, len(params.Filters))
+	for key, value := range queryParams.(middleware.QueryParams).Filters {
+		if key != "delimiter" && key != "header" {
+			params.Filters[key] = value
+		}
+	}
+
+	writer := csv.NewWriter(c.Writer)
+	writer.Comma = delimiter
+
+	// START THE RESPONSE ON THE FIRST ROW, SO ERRORS BEFORE IT CAN STILL BE REPORTED AS JSON
+	started := false
+	start := func() error {
+		started = true
+		c.Header("Content-Type", "text/csv; charset=utf-8")
+		c.Header("Content-Disposition", `attachment; filename="expenses.csv"`)
+		c.Status(http.StatusOK)
+
+		if !withHeader {
+			return nil
+		}
+		return writer.Write(expenseCSVHeader)
+	}
+
+	loc := user.Location()
+	err = h.expenseRepo.EachByUserID(user.ID, params, func(expense *models.Expense) error {
+		if !started {
+			if err := start(); err != nil {
+				return err
+			}
+		}
+
+		return writer.Write(expenseCSVRow(expense, loc))
+	})
+	if err == nil && !started {
+		err = start()
+	}
+
+	if err != nil {
+		if started {
+			// THE STATUS IS ALREADY SENT, THE CLIENT GETS A TRUNCATED FILE
+			log.Printf("Failed to export expenses for user %d: %v", user.ID, err)
+			c.Abort()
+			return
+		}
+		if errors.Is(err, repositories.ErrInvalidFilter) {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to export expenses")
+		return
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		log.Printf("Failed to export expenses for user %d: %v", user.ID, err)
+	}
+}
+
 // CREATE EXPENSE
 // CreateExpense godoc
 // @Summary Create a new expense
-// @Description Create a new expense for the authenticated user
+// @Description Create a new expense for the authenticated user. Optional split lines book parts of the amount under other categories of the same type, they must sum to the amount and reports count each line under its own category. A personal expense without category_id takes the category and tags of the first matching categorization rule
 // @Tags expenses
 // @Accept  json
 // @Produce  json
 // @Param   expense  body  models.ExpenseRequest  true  "Expense data"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
 // @Success 201 {object} utils.Response[models.Expense]
 // @Failure 400 {object} utils.Response[any]
 // @Failure 401 {object} utils.Response[any]
@@ -126,27 +292,153 @@ func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
 		return
 	}
 
+	// VIEWERS CANNOT ADD EXPENSES TO THE SELECTED LEDGER
+	member := currentLedgerMember(c)
+	if member != nil && !member.CanWrite() {
+		utils.ErrorResponse(c, http.StatusForbidden, "Viewers cannot change this ledger")
+		return
+	}
+	ledgerID := currentLedgerID(c)
+	baseCurrency := ledgerBaseCurrency(user, member)
+
+	// RESOLVE GOAL
+	var goal *models.Goal
+	if req.GoalID != nil && *req.GoalID != 0 {
+		var ok bool
+		if goal, ok = h.resolveGoal(c, user, ledgerID, *req.GoalID); !ok {
+			return
+		}
+	}
+
+	// RESOLVE ACCOUNT, A GOAL CONTRIBUTION DEFAULTS TO THE GOAL ACCOUNT
+	var account *models.Account
+	if req.AccountID == nil && goal != nil && goal.AccountID != nil {
+		req.AccountID = goal.AccountID
+	}
+	if req.AccountID != nil && *req.AccountID != 0 {
+		var ok bool
+		if account, ok = h.resolveAccount(c, user, *req.AccountID); !ok {
+			return
+		}
+	}
+
+	// RESOLVE CURRENCY, DEFAULTS TO THE ACCOUNT CURRENCY OR THE LEDGER OR USER BASE CURRENCY
+	currency := baseCurrency
+	if account != nil {
+		currency = account.Currency
+	}
+	if req.Currency != "" {
+		currency = models.NormalizeCurrency(req.Currency)
+	}
+
+	// AN ACCOUNT BALANCE IS KEPT IN ONE CURRENCY
+	if account != nil && currency != account.Currency {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Currency must match the account currency "+account.Currency)
+		return
+	}
+
+	// VALIDATE AMOUNT PRECISION AGAINST THE CURRENCY MINOR UNITS
+	if !req.Amount.FitsExponent(models.CurrencyExponent(currency)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Amount has more decimal places than "+currency+" allows")
+		return
+	}
+
+	// RESOLVE PAYEE, DETECTED FROM THE NAME WHEN OMITTED
+	payee, ok := h.resolvePayee(c, user, req.PayeeID, req.Name)
+	if !ok {
+		return
+	}
+
+	// A SPLIT EXPENSE DEFAULTS TO THE CATEGORY OF ITS FIRST LINE
+	if req.CategoryID == 0 && len(req.Splits) > 0 {
+		req.CategoryID = req.Splits[0].CategoryID
+	}
+
+	// A PERSONAL EXPENSE WITHOUT A CATEGORY TAKES THE CATEGORY AND TAGS OF THE FIRST MATCHING RULE
+	var category *models.Category
+	if req.CategoryID == 0 && ledgerID == nil {
+		var payeeID *uint
+		if payee != nil {
+			payeeID = &payee.ID
+		}
+		if category, ok = h.categorizeExpense(c, user, &req, payeeID); !ok {
+			return
+		}
+	}
+
 	// VALIDATE CATEGORY ID
-	category, err := h.categoryRepo.GetByID(req.CategoryID)
-	if err != nil {
-		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+	if category == nil {
+		category, err = h.categoryRepo.GetEffectiveByID(user.ID, req.CategoryID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+			return
+		}
+	}
+
+	// VALIDATE CATEGORY BELONGING TO USER OR LEDGER
+	if !categoryUsable(category, user.ID, ledgerID) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Category does not belong to this user")
 		return
 	}
 
-	// VALIDATE CATEGORY BELONGING TO USER
-	if !category.IsDefault {
-		if category.UserID == nil || *category.UserID != user.ID {
-			utils.ErrorResponse(c, http.StatusBadRequest, "Category does not belong to this user")
+	// VALIDATE SPLIT LINES
+	splits, ok := h.resolveSplits(c, user.ID, ledgerID, category, req.Amount, currency, req.Splits)
+	if !ok {
+		return
+	}
+
+	// VALIDATE SHARES AMONG LEDGER MEMBERS
+	paidByID, shares, ok := h.resolveShares(c, user.ID, ledgerID, category, req.PaidByID, req.Amount, currency, req.Share)
+	if !ok {
+		return
+	}
+
+	// PARSE SPENT AT, DEFAULTS TO NOW
+	spentAt := time.Now()
+	if req.SpentAt != "" {
+		spentAt, _, err = utils.ParseDateTime(req.SpentAt, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
 	}
 
+	// RESOLVE TAGS, CREATING THE MISSING ONES
+	tags, ok := h.resolveTags(c, user, req.Tags)
+	if !ok {
+		return
+	}
+
 	// CREATE EXPENSE
 	expense := models.Expense{
 		Name:       req.Name,
 		Amount:     req.Amount,
+		Currency:   currency,
+		SpentAt:    spentAt,
 		UserID:     user.ID,
+		LedgerID:   ledgerID,
 		CategoryID: category.ID,
+		Tags:       tags,
+		Splits:     splits,
+		PaidByID:   paidByID,
+		Shares:     shares,
+	}
+	if req.Share != nil {
+		expense.ShareMethod = req.Share.Method
+	}
+	if payee != nil {
+		expense.PayeeID = &payee.ID
+	}
+	if account != nil {
+		expense.AccountID = &account.ID
+	}
+	if goal != nil {
+		expense.GoalID = &goal.ID
+	}
+
+	// CONVERT TO THE LEDGER OR USER BASE CURRENCY
+	if !h.convertToBaseCurrency(c, user, baseCurrency, &expense) {
+		return
 	}
 
 	// SAVE EXPENSE
@@ -155,8 +447,13 @@ func (h *ExpenseHandler) CreateExpense(c *gin.Context) {
 		return
 	}
 
-	// SET CATEGORY TO EXPENSE STRUCT MANUALLY
+	// SET CATEGORY, PAYEE AND ACCOUNT TO EXPENSE STRUCT MANUALLY
 	expense.Category = *category
+	expense.Payee = payee
+	expense.Account = account
+
+	// CHECK BUDGET THRESHOLDS
+	h.evaluateBudgets(user, expense.CategoryIDs()...)
 
 	// RETURN CREATED EXPENSE
 	utils.SuccessResponse(c, http.StatusCreated, "Expense created successfully", expense)
@@ -200,15 +497,15 @@ func (h *ExpenseHandler) GetExpenseByID(c *gin.Context) {
 	}
 
 	// GET EXPENSE BY ID
-	expense, err := h.expenseRepo.GetByID(uint(expenseID))
+	expense, err := h.expenseRepo.GetByID(uint(expenseID), user.ID)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Expense not found")
 		return
 	}
 
-	// CHECK IF EXPENSE BELONGS TO USER
-	if expense.UserID != user.ID {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Expense does not belong to this user")
+	// CHECK IF EXPENSE BELONGS TO USER OR TO ONE OF THEIR LEDGERS
+	_, err = h.ledgerService.Authorize(user.ID, expense.UserID, expense.LedgerID, false)
+	if ledgerAccessError(c, err, http.StatusUnauthorized, "Expense does not belong to this user") {
 		return
 	}
 
@@ -219,7 +516,7 @@ func (h *ExpenseHandler) GetExpenseByID(c *gin.Context) {
 // UPDATE EXPENSE
 // UpdateExpense godoc
 // @Summary Update an expense
-// @Description Update an expense for the authenticated user
+// @Description Update an expense for the authenticated user. Current split lines are kept when splits is omitted and must still sum to the new amount, an empty list removes them. Payee, tags and account of a ledger expense are those of the member who created it
 // @Tags expenses
 // @Accept  json
 // @Produce  json
@@ -254,18 +551,27 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 	}
 
 	// GET EXPENSE BY ID
-	expense, err := h.expenseRepo.GetByID(uint(expenseID))
+	expense, err := h.expenseRepo.GetByID(uint(expenseID), user.ID)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Expense not found")
 		return
 	}
 
-	// CHECK IF EXPENSE BELONGS TO USER
-	if expense.UserID != user.ID {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Expense does not belong to this user")
+	// CHECK IF EXPENSE BELONGS TO USER OR TO A LEDGER THEY CAN EDIT
+	member, err := h.ledgerService.Authorize(user.ID, expense.UserID, expense.LedgerID, true)
+	if ledgerAccessError(c, err, http.StatusUnauthorized, "Expense does not belong to this user") {
 		return
 	}
 
+	// PAYEE, TAGS AND ACCOUNT ARE RESOLVED AGAINST THE OWNER, ANOTHER LEDGER MEMBER MAY BE EDITING THE EXPENSE
+	owner := user
+	if expense.UserID != user.ID {
+		if owner, err = h.userRepo.GetByID(expense.UserID); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get expense owner")
+			return
+		}
+	}
+
 	// VALIDATE REQUEST BODY
 	var req models.ExpenseRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -279,25 +585,154 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 		return
 	}
 
+	// RESOLVE GOAL, KEEP THE CURRENT ONE WHEN OMITTED AND REMOVE IT ON 0
+	if req.GoalID != nil {
+		expense.GoalID = nil
+		if *req.GoalID != 0 {
+			goal, ok := h.resolveGoal(c, user, expense.LedgerID, *req.GoalID)
+			if !ok {
+				return
+			}
+			expense.GoalID = &goal.ID
+		}
+	}
+
+	// RESOLVE ACCOUNT, KEEP THE CURRENT ONE WHEN OMITTED AND REMOVE IT ON 0
+	account := expense.Account
+	if req.AccountID != nil {
+		account = nil
+		if *req.AccountID != 0 {
+			var ok bool
+			if account, ok = h.resolveAccount(c, owner, *req.AccountID); !ok {
+				return
+			}
+		}
+	}
+
+	// RESOLVE CURRENCY, DEFAULTS TO THE ACCOUNT CURRENCY OR KEEPS THE CURRENT ONE
+	currency := expense.Currency
+	if account != nil {
+		currency = account.Currency
+	}
+	if req.Currency != "" {
+		currency = models.NormalizeCurrency(req.Currency)
+	}
+
+	// AN ACCOUNT BALANCE IS KEPT IN ONE CURRENCY
+	if account != nil && currency != account.Currency {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Currency must match the account currency "+account.Currency)
+		return
+	}
+
+	// VALIDATE AMOUNT PRECISION AGAINST THE CURRENCY MINOR UNITS
+	if !req.Amount.FitsExponent(models.CurrencyExponent(currency)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Amount has more decimal places than "+currency+" allows")
+		return
+	}
+
+	// KEEP THE CURRENT SPLITS WHEN OMITTED, THEY ARE VALIDATED AGAIN AGAINST THE NEW AMOUNT
+	if req.Splits == nil {
+		for _, split := range expense.Splits {
+			req.Splits = append(req.Splits, models.ExpenseSplitRequest{CategoryID: split.CategoryID, Amount: split.Amount})
+		}
+	}
+
+	// A SPLIT EXPENSE DEFAULTS TO THE CATEGORY OF ITS FIRST LINE
+	if req.CategoryID == 0 && len(req.Splits) > 0 {
+		req.CategoryID = req.Splits[0].CategoryID
+	}
+
 	// VALIDATE CATEGORY ID
-	category, err := h.categoryRepo.GetByID(req.CategoryID)
+	category, err := h.categoryRepo.GetEffectiveByID(user.ID, req.CategoryID)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
 		return
 	}
 
-	// VALIDATE CATEGORY BELONGING TO USER
-	if !category.IsDefault {
-		if category.UserID == nil || *category.UserID != user.ID {
-			utils.ErrorResponse(c, http.StatusBadRequest, "Category does not belong to this user")
+	// VALIDATE CATEGORY BELONGING TO USER OR LEDGER
+	if !categoryUsable(category, user.ID, expense.LedgerID) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Category does not belong to this user")
+		return
+	}
+
+	// VALIDATE SPLIT LINES
+	splits, ok := h.resolveSplits(c, user.ID, expense.LedgerID, category, req.Amount, currency, req.Splits)
+	if !ok {
+		return
+	}
+
+	// KEEP THE CURRENT SHARES AND PAYER WHEN OMITTED, THEY ARE DIVIDED AGAIN OVER THE NEW AMOUNT
+	if req.Share == nil && len(expense.Shares) > 0 {
+		req.Share = &models.ExpenseShareRequest{Method: expense.ShareMethod}
+		for _, share := range expense.Shares {
+			req.Share.Participants = append(req.Share.Participants, models.ExpenseParticipantRequest{UserID: share.UserID, Shares: share.Shares, Amount: share.Amount})
+		}
+		if req.PaidByID == nil {
+			req.PaidByID = expense.PaidByID
+		}
+	}
+
+	// VALIDATE SHARES AMONG LEDGER MEMBERS
+	paidByID, shares, ok := h.resolveShares(c, user.ID, expense.LedgerID, category, req.PaidByID, req.Amount, currency, req.Share)
+	if !ok {
+		return
+	}
+
+	// PARSE SPENT AT, KEEP THE CURRENT VALUE WHEN OMITTED
+	if req.SpentAt != "" {
+		expense.SpentAt, _, err = utils.ParseDateTime(req.SpentAt, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
 			return
 		}
 	}
 
+	// KEEP PREVIOUS CATEGORIES TO RE-EVALUATE THEIR BUDGETS
+	previousCategoryIDs := expense.CategoryIDs()
+
+	// RESOLVE TAGS, KEEP THE CURRENT ONES WHEN OMITTED
+	if req.Tags != nil {
+		tags, ok := h.resolveTags(c, owner, req.Tags)
+		if !ok {
+			return
+		}
+		expense.Tags = tags
+	}
+
+	// RESOLVE PAYEE, DETECTED FROM THE NAME WHEN OMITTED
+	payee, ok := h.resolvePayee(c, owner, req.PayeeID, req.Name)
+	if !ok {
+		return
+	}
+
 	// UPDATE EXPENSE FIELDS
 	expense.Name = req.Name
 	expense.Amount = req.Amount
+	expense.Currency = currency
 	expense.CategoryID = category.ID
+	expense.Category = *category
+	expense.Splits = splits
+	expense.PaidByID = paidByID
+	expense.Shares = shares
+	expense.ShareMethod = ""
+	if len(shares) > 0 {
+		expense.ShareMethod = req.Share.Method
+	}
+	expense.PayeeID = nil
+	expense.Payee = payee
+	if payee != nil {
+		expense.PayeeID = &payee.ID
+	}
+	expense.AccountID = nil
+	expense.Account = account
+	if account != nil {
+		expense.AccountID = &account.ID
+	}
+
+	// CONVERT TO THE LEDGER OR USER BASE CURRENCY
+	if !h.convertToBaseCurrency(c, user, ledgerBaseCurrency(user, member), expense) {
+		return
+	}
 
 	// SAVE UPDATED EXPENSE
 	if err := h.expenseRepo.Update(expense); err != nil {
@@ -305,6 +740,9 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 		return
 	}
 
+	// CHECK BUDGET THRESHOLDS
+	h.evaluateBudgets(user, append(previousCategoryIDs, expense.CategoryIDs()...)...)
+
 	// RETURN UPDATED EXPENSE
 	utils.SuccessResponse(c, http.StatusOK, "Expense updated successfully", expense)
 }
@@ -312,7 +750,7 @@ func (h *ExpenseHandler) UpdateExpense(c *gin.Context) {
 // DELETE EXPENSE
 // DeleteExpense godoc
 // @Summary Delete an expense
-// @Description Delete an expense for the authenticated user
+// @Description Move an expense of the authenticated user to the trash, it is purged with its attachments after the trash retention period
 // @Tags expenses
 // @Accept  json
 // @Produce  json
@@ -346,24 +784,572 @@ func (h *ExpenseHandler) DeleteExpense(c *gin.Context) {
 	}
 
 	// GET EXPENSE BY ID
-	expense, err := h.expenseRepo.GetByID(uint(expenseID))
+	expense, err := h.expenseRepo.GetByID(uint(expenseID), user.ID)
 	if err != nil {
 		utils.ErrorResponse(c, http.StatusNotFound, "Expense not found")
 		return
 	}
 
-	// CHECK IF EXPENSE BELONGS TO USER
-	if expense.UserID != user.ID {
-		utils.ErrorResponse(c, http.StatusUnauthorized, "Expense does not belong to this user")
+	// CHECK IF EXPENSE BELONGS TO USER OR TO A LEDGER THEY CAN EDIT
+	_, err = h.ledgerService.Authorize(user.ID, expense.UserID, expense.LedgerID, true)
+	if ledgerAccessError(c, err, http.StatusUnauthorized, "Expense does not belong to this user") {
 		return
 	}
 
-	// DELETE EXPENSE
+	// MOVE EXPENSE TO THE TRASH
 	if err := h.expenseRepo.Delete(expense); err != nil {
 		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete expense")
 		return
 	}
 
+	// CHECK BUDGET THRESHOLDS
+	h.evaluateBudgets(user, expense.CategoryIDs()...)
+
 	// RETURN SUCCESS MESSAGE
 	utils.SuccessResponse(c, http.StatusOK, "Expense deleted successfully", expense)
 }
+
+// GET TRASHED EXPENSES
+// GetTrashedExpenses godoc
+// @Summary Get deleted expenses
+// @Description Get the expenses in the trash of the authenticated user, or of the selected ledger, with the filters of GET /expenses
+// @Tags expenses
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by expense name"
+// @Param category_name query string false "Filter by category name"
+// @Param category_id query int false "Filter by category, including its subcategories"
+// @Param category_type query string false "Filter by category type"
+// @Param from query string false "Spent on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Spent on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Expense]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/trash [get]
+func (h *ExpenseHandler) GetTrashedExpenses(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+	params := queryParams.(middleware.QueryParams)
+	params.Location = user.Location()
+	params.LedgerID = currentLedgerID(c)
+
+	// GET TRASHED EXPENSES BY USER ID
+	expenses, total, totalPages, err := h.expenseRepo.GetTrashByUserID(user.ID, params)
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get deleted expenses")
+		return
+	}
+
+	response := gin.H{
+		"data":        expenses,
+		"total":       total,
+		"page":        params.Page,
+		"limit":       params.Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Deleted expenses retrieved successfully", response)
+}
+
+// RESTORE EXPENSE
+// RestoreExpense godoc
+// @Summary Restore a deleted expense
+// @Description Move an expense out of the trash with its tags, splits, shares and attachments
+// @Tags expenses
+// @Accept  json
+// @Produce  json
+// @Param   id  path  int  true  "Expense ID"
+// @Success 200 {object} utils.Response[models.Expense]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/{id}/restore [post]
+func (h *ExpenseHandler) RestoreExpense(c *gin.Context) {
+	expense, user, ok := h.getTrashedExpense(c)
+	if !ok {
+		return
+	}
+
+	if err := h.expenseRepo.Restore(expense); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to restore expense")
+		return
+	}
+
+	// CHECK BUDGET THRESHOLDS
+	h.evaluateBudgets(user, expense.CategoryIDs()...)
+
+	utils.SuccessResponse(c, http.StatusOK, "Expense restored successfully", expense)
+}
+
+// PURGE EXPENSE
+// PurgeExpense godoc
+// @Summary Purge a deleted expense
+// @Description Delete an expense in the trash permanently with its attachments
+// @Tags expenses
+// @Accept  json
+// @Produce  json
+// @Param   id  path  int  true  "Expense ID"
+// @Success 200 {object} utils.Response[models.Expense]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/trash/{id} [delete]
+func (h *ExpenseHandler) PurgeExpense(c *gin.Context) {
+	expense, _, ok := h.getTrashedExpense(c)
+	if !ok {
+		return
+	}
+
+	if err := h.trashService.Purge(c.Request.Context(), expense); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to purge expense")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Expense purged successfully", expense)
+}
+
+// EMPTY TRASH
+// EmptyTrash godoc
+// @Summary Empty the trash
+// @Description Delete every expense in the trash of the authenticated user, or of the selected ledger, permanently with their attachments
+// @Tags expenses
+// @Accept  json
+// @Produce  json
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
+// @Success 200 {object} utils.Response[map[string]int]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/trash [delete]
+func (h *ExpenseHandler) EmptyTrash(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// VIEWERS CANNOT PURGE THE EXPENSES OF THE SELECTED LEDGER
+	member := currentLedgerMember(c)
+	if member != nil && !member.CanWrite() {
+		utils.ErrorResponse(c, http.StatusForbidden, "Viewers cannot change this ledger")
+		return
+	}
+
+	expenses, err := h.expenseRepo.GetAllTrashByUserID(user.ID, currentLedgerID(c))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get deleted expenses")
+		return
+	}
+
+	for i := range expenses {
+		if err := h.trashService.Purge(c.Request.Context(), &expenses[i]); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to purge expenses")
+			return
+		}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trash emptied successfully", gin.H{"purged": len(expenses)})
+}
+
+// GET THE EXPENSE IN THE TRASH FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR CANNOT CHANGE IT
+func (h *ExpenseHandler) getTrashedExpense(c *gin.Context) (*models.Expense, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET EXPENSE ID FROM URL PARAM
+	expenseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid expense ID")
+		return nil, nil, false
+	}
+
+	// GET EXPENSE FROM THE TRASH BY ID
+	expense, err := h.expenseRepo.GetTrashedByID(uint(expenseID), user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Expense not found in the trash")
+		return nil, nil, false
+	}
+
+	// CHECK IF EXPENSE BELONGS TO USER OR TO A LEDGER THEY CAN EDIT
+	_, err = h.ledgerService.Authorize(user.ID, expense.UserID, expense.LedgerID, true)
+	if ledgerAccessError(c, err, http.StatusUnauthorized, "Expense does not belong to this user") {
+		return nil, nil, false
+	}
+
+	return expense, user, true
+}
+
+// SET THE BASE AMOUNT OF AN EXPENSE IN baseCurrency, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *ExpenseHandler) convertToBaseCurrency(c *gin.Context, user *models.User, baseCurrency string, expense *models.Expense) bool {
+	err := h.exchangeRateService.ConvertExpense(user.ID, baseCurrency, expense)
+	if errors.Is(err, services.ErrExchangeRateNotFound) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to convert amount to base currency")
+		return false
+	}
+
+	return true
+}
+
+// VALIDATE SPLIT LINES AGAINST THE EXPENSE, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+// EVERY LINE NEEDS A DIFFERENT CATEGORY OF THE USER OR LEDGER WITH THE TYPE OF category, AND THE LINES MUST SUM TO amount
+func (h *ExpenseHandler) resolveSplits(c *gin.Context, userID uint, ledgerID *uint, category *models.Category, amount models.Money, currency string, reqs []models.ExpenseSplitRequest) ([]models.ExpenseSplit, bool) {
+	if len(reqs) == 0 {
+		return nil, true
+	}
+
+	splits := make([]models.ExpenseSplit, 0, len(reqs))
+	seen := make(map[uint]bool, len(reqs))
+	var total models.Money
+
+	for _, req := range reqs {
+		if seen[req.CategoryID] {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Split lines must have different categories")
+			return nil, false
+		}
+		seen[req.CategoryID] = true
+
+		if !req.Amount.FitsExponent(models.CurrencyExponent(currency)) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Split amount has more decimal places than "+currency+" allows")
+			return nil, false
+		}
+
+		splitCategory, err := h.categoryRepo.GetEffectiveByID(userID, req.CategoryID)
+		if err != nil || !categoryUsable(splitCategory, userID, ledgerID) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid split category ID")
+			return nil, false
+		}
+		if splitCategory.Type != category.Type {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Split categories must have the same type as the expense category")
+			return nil, false
+		}
+
+		total += req.Amount
+		splits = append(splits, models.ExpenseSplit{
+			CategoryID: splitCategory.ID,
+			Amount:     req.Amount,
+			Category:   *splitCategory,
+		})
+	}
+
+	if total != amount {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Split amounts must sum to the expense amount")
+		return nil, false
+	}
+
+	return splits, true
+}
+
+// VALIDATE THE SHARES OF A LEDGER EXPENSE AND DIVIDE amount AMONG ITS PARTICIPANTS, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+// THE PAYER DEFAULTS TO THE USER, THE PAYER AND EVERY PARTICIPANT MUST BE MEMBERS OF THE LEDGER. RETURNS THE PAYER AND THE SHARES
+func (h *ExpenseHandler) resolveShares(c *gin.Context, userID uint, ledgerID *uint, category *models.Category, paidByID *uint, amount models.Money, currency string, req *models.ExpenseShareRequest) (*uint, []models.ExpenseShare, bool) {
+	if req == nil || req.Method == "" {
+		if paidByID != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "paid_by_id requires shares")
+			return nil, nil, false
+		}
+		return nil, nil, true
+	}
+
+	// ONLY SPENDING IN A SHARED LEDGER IS SPLIT AMONG MEMBERS
+	if ledgerID == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Shares require an expense in a shared ledger")
+		return nil, nil, false
+	}
+	if category.Type == "income" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Income cannot be shared")
+		return nil, nil, false
+	}
+
+	// VALIDATE THE PAYER
+	payerID := userID
+	if paidByID != nil {
+		payerID = *paidByID
+	}
+	_, err := h.ledgerService.Member(*ledgerID, payerID)
+	if ledgerAccessError(c, err, http.StatusBadRequest, "Payer is not a member of this ledger") {
+		return nil, nil, false
+	}
+
+	// VALIDATE THE PARTICIPANTS AND THEIR WEIGHTS
+	exponent := models.CurrencyExponent(currency)
+	shares := make([]models.ExpenseShare, 0, len(req.Participants))
+	weights := make([]int64, 0, len(req.Participants))
+	seen := make(map[uint]bool, len(req.Participants))
+	var total models.Money
+
+	for _, participant := range req.Participants {
+		if seen[participant.UserID] {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Participants must be different members")
+			return nil, nil, false
+		}
+		seen[participant.UserID] = true
+
+		_, err := h.ledgerService.Member(*ledgerID, participant.UserID)
+		if ledgerAccessError(c, err, http.StatusBadRequest, "Participant is not a member of this ledger") {
+			return nil, nil, false
+		}
+
+		share := models.ExpenseShare{UserID: participant.UserID}
+		switch req.Method {
+		case models.ShareMethodEqual:
+			weights = append(weights, 1)
+		case models.ShareMethodShares:
+			if participant.Shares <= 0 {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Every participant needs shares greater than 0")
+				return nil, nil, false
+			}
+			share.Shares = participant.Shares
+			weights = append(weights, int64(participant.Shares))
+		case models.ShareMethodExact:
+			if participant.Amount <= 0 {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Every participant needs an amount greater than 0")
+				return nil, nil, false
+			}
+			if !participant.Amount.FitsExponent(exponent) {
+				utils.ErrorResponse(c, http.StatusBadRequest, "Share amount has more decimal places than "+currency+" allows")
+				return nil, nil, false
+			}
+			share.Amount = participant.Amount
+			total += participant.Amount
+		}
+
+		shares = append(shares, share)
+	}
+
+	// DIVIDE THE AMOUNT, EXACT AMOUNTS MUST ADD UP TO IT INSTEAD
+	if req.Method == models.ShareMethodExact {
+		if total != amount {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Share amounts must sum to the expense amount")
+			return nil, nil, false
+		}
+	} else {
+		for i, part := range models.DivideMoney(amount, exponent, weights) {
+			shares[i].Amount = part
+		}
+	}
+
+	return &payerID, shares, true
+}
+
+// DEFAULT CATEGORIES, AND THE CATEGORIES OF THE LEDGER OR OF THE USER FOR A PERSONAL EXPENSE (ledgerID NIL)
+func categoryUsable(category *models.Category, userID uint, ledgerID *uint) bool {
+	if category.IsDefault {
+		return true
+	}
+	if ledgerID != nil {
+		return category.LedgerID != nil && *category.LedgerID == *ledgerID
+	}
+
+	return category.LedgerID == nil && category.UserID != nil && *category.UserID == userID
+}
+
+// GET AN ACCOUNT OF THE USER BY ID, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *ExpenseHandler) resolveAccount(c *gin.Context, user *models.User, accountID uint) (*models.Account, bool) {
+	account, err := h.accountRepo.GetByID(accountID)
+	if err != nil || account.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid account ID")
+		return nil, false
+	}
+
+	return account, true
+}
+
+// GET A GOAL OF THE USER BY ID FOR A PERSONAL EXPENSE (ledgerID NIL), WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *ExpenseHandler) resolveGoal(c *gin.Context, user *models.User, ledgerID *uint, goalID uint) (*models.Goal, bool) {
+	if ledgerID != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Ledger expenses cannot contribute to a goal")
+		return nil, false
+	}
+
+	goal, err := h.goalRepo.GetByID(goalID)
+	if err != nil || goal.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid goal ID")
+		return nil, false
+	}
+
+	return goal, true
+}
+
+// GET OR CREATE THE USER TAGS WITH THE GIVEN NAMES, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *ExpenseHandler) resolveTags(c *gin.Context, user *models.User, names []string) ([]models.Tag, bool) {
+	seen := make(map[string]bool, len(names))
+	normalized := make([]string, 0, len(names))
+	for _, name := range names {
+		name = models.NormalizeTagName(name)
+		if name == "" || seen[name] {
+			continue
+		}
+		seen[name] = true
+		normalized = append(normalized, name)
+	}
+
+	tags, err := h.tagRepo.FindOrCreateByNames(user.ID, normalized)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to save tags")
+		return nil, false
+	}
+
+	return tags, true
+}
+
+// GET THE PAYEE BY ID, OR MATCH THE EXPENSE NAME AGAINST THE USER PAYEE ALIASES WHEN payeeID IS NIL
+// WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE, THE PAYEE IS NIL WHEN NOTHING MATCHES
+func (h *ExpenseHandler) resolvePayee(c *gin.Context, user *models.User, payeeID *uint, name string) (*models.Payee, bool) {
+	if payeeID != nil {
+		payee, err := h.payeeRepo.GetByID(*payeeID)
+		if err != nil || payee.UserID != user.ID {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid payee ID")
+			return nil, false
+		}
+		payee.Aliases = nil
+		return payee, true
+	}
+
+	matcher, err := h.payeeService.Matcher(user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to match payee")
+		return nil, false
+	}
+
+	payee := matcher.Match(name)
+	if payee != nil {
+		payee.Aliases = nil
+	}
+
+	return payee, true
+}
+
+// MATCH A NEW PERSONAL EXPENSE AGAINST THE USER CATEGORIZATION RULES, ADDING THE RULE TAGS TO req.Tags
+// WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE OR WHEN NO RULE MATCHES
+func (h *ExpenseHandler) categorizeExpense(c *gin.Context, user *models.User, req *models.ExpenseRequest, payeeID *uint) (*models.Category, bool) {
+	matcher, err := h.categorizationService.Matcher(user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to match categorization rules")
+		return nil, false
+	}
+
+	rule := matcher.Match(req.Name, req.Amount, payeeID, "")
+	if rule == nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "category_id is required when no categorization rule matches")
+		return nil, false
+	}
+
+	req.CategoryID = rule.CategoryID
+	for _, tag := range rule.Tags {
+		req.Tags = append(req.Tags, tag.Name)
+	}
+
+	return &rule.Category, true
+}
+
+// RAISE BUDGET ALERTS FOR CHANGED SPENDING, FAILURES ARE LOGGED AND DO NOT FAIL THE REQUEST
+func (h *ExpenseHandler) evaluateBudgets(user *models.User, categoryIDs ...uint) {
+	if _, err := h.budgetAlertService.Evaluate(user, categoryIDs...); err != nil {
+		log.Printf("Failed to evaluate budget alerts for user %d: %v", user.ID, err)
+	}
+}
+
+var expenseCSVHeader = []string{"id", "spent_at", "name", "category_name", "category_type", "amount", "currency", "base_amount", "base_currency", "created_at", "updated_at"}
+
+// ONE EXPORTED ROW, TIMES IN loc
+func expenseCSVRow(expense *models.Expense, loc *time.Location) []string {
+	return []string{
+		strconv.FormatUint(uint64(expense.ID), 10),
+		expense.SpentAt.In(loc).Format(time.RFC3339),
+		csvText(expense.Name),
+		csvText(expense.Category.Name),
+		expense.Category.Type,
+		expense.Amount.StringFixed(models.CurrencyExponent(expense.Currency)),
+		expense.Currency,
+		expense.BaseAmount.StringFixed(models.CurrencyExponent(expense.BaseCurrency)),
+		expense.BaseCurrency,
+		expense.CreatedAt.In(loc).Format(time.RFC3339),
+		expense.UpdatedAt.In(loc).Format(time.RFC3339),
+	}
+}
+
+// PREFIX TEXT A SPREADSHEET WOULD READ AS A FORMULA WITH ', e.g. A PAYEE NAME STARTING WITH =
+func csvText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+
+	return value
+}
+
+// PARSE A CSV DELIMITER NAME OR SINGLE CHARACTER
+func parseCSVDelimiter(value string) (rune, error) {
+	switch value {
+	case "comma":
+		return ',', nil
+	case "semicolon":
+		return ';', nil
+	case "tab", "\\t":
+		return '\t', nil
+	case "pipe":
+		return '|', nil
+	}
+
+	runes := []rune(value)
+	if len(runes) != 1 || runes[0] == '"' || runes[0] == '\r' || runes[0] == '\n' || runes[0] == utf8.RuneError {
+		return 0, errors.New("delimiter must be a single character or one of comma, semicolon, tab, pipe")
+	}
+
+	return runes[0], nil
+}
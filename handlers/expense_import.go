@@ -0,0 +1,201 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ExpenseImportHandler struct {
+	userRepo             *repositories.UserRepository
+	expenseImportService *services.ExpenseImportService
+	budgetAlertService   *services.BudgetAlertService
+}
+
+func NewExpenseImportHandler(userRepo *repositories.UserRepository, expenseImportService *services.ExpenseImportService, budgetAlertService *services.BudgetAlertService) *ExpenseImportHandler {
+	return &ExpenseImportHandler{
+		userRepo:             userRepo,
+		expenseImportService: expenseImportService,
+		budgetAlertService:   budgetAlertService,
+	}
+}
+
+// IMPORT EXPENSES
+// ImportExpenses godoc
+// @Summary Import expenses from CSV, OFX or QIF
+// @Description Import expenses from a CSV file with a column mapping, or from an OFX/QFX (SGML or XML) or QIF bank statement. CSV columns are header names or 1-based column numbers. Categories are matched by name against the user and default categories; rows without a category take the category and tags of the first matching categorization rule, then statement debits without a matching expense category go to expense_category and credits without a matching income category to income_category. Statement transactions already imported before (by OFX FITID, or a hash for QIF) are skipped. With dry_run nothing is saved and every row error is reported; otherwise all rows are inserted in one transaction, or none if any row is invalid.
+// @Tags expenses
+// @Accept  multipart/form-data
+// @Produce  json
+// @Param file formData file true "CSV, OFX, QFX or QIF file"
+// @Param format formData string false "csv, ofx or qif, detected from the file extension when omitted"
+// @Param date_column formData string false "CSV date column" default(date)
+// @Param name_column formData string false "CSV name column" default(name)
+// @Param amount_column formData string false "CSV amount column" default(amount)
+// @Param category_column formData string false "CSV category name column" default(category)
+// @Param currency_column formData string false "CSV currency column, amounts are in the user base currency when omitted"
+// @Param date_format formData string false "CSV date format (DD/MM/YYYY, MM/DD/YYYY, DD-MM-YYYY, DD.MM.YYYY, YYYY/MM/DD), defaults to YYYY-MM-DD or RFC3339. QIF dates are read as MM/DD unless this is DD/MM/YYYY"
+// @Param delimiter formData string false "CSV field delimiter, a single character or one of comma, semicolon, tab, pipe" default(comma)
+// @Param header formData bool false "The first CSV row is a header row" default(true)
+// @Param expense_category formData string false "Category name for statement debits without a matching expense category"
+// @Param income_category formData string false "Category name for statement credits without a matching income category"
+// @Param dry_run formData bool false "Only validate, do not save" default(false)
+// @Success 200 {object} utils.Response[models.ExpenseImportResult] "Dry run result"
+// @Success 201 {object} utils.Response[models.ExpenseImportResult]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 422 {object} utils.Response[models.ExpenseImportResult] "Rows with errors, nothing was saved"
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /expenses/import [post]
+func (h *ExpenseImportHandler) ImportExpenses(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// PARSE OPTIONS
+	dryRun, err := strconv.ParseBool(c.DefaultPostForm("dry_run", "false"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "dry_run must be true or false")
+		return
+	}
+
+	opts := services.ExpenseImportOptions{
+		DryRun:          dryRun,
+		ExpenseCategory: c.PostForm("expense_category"),
+		IncomeCategory:  c.PostForm("income_category"),
+	}
+
+	// GET UPLOADED FILE
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "File is required")
+		return
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to read file")
+		return
+	}
+	defer file.Close()
+
+	// DETECT FORMAT FROM THE FILE EXTENSION
+	format := strings.ToLower(c.PostForm("format"))
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(fileHeader.Filename)) {
+		case ".ofx", ".qfx":
+			format = "ofx"
+		case ".qif":
+			format = "qif"
+		default:
+			format = "csv"
+		}
+	}
+
+	// PARSE ROWS
+	var candidates []services.ExpenseCandidate
+	var rowErrors []models.ExpenseImportError
+
+	switch format {
+	case "csv":
+		csvOpts, ok := bindCSVOptions(c)
+		if !ok {
+			return
+		}
+		candidates, rowErrors, err = services.ParseExpensesCSV(file, csvOpts, user.Location())
+	case "ofx":
+		candidates, rowErrors, err = services.ParseOFX(file, user.Location())
+	case "qif":
+		candidates, rowErrors, err = services.ParseQIF(file, c.PostForm("date_format") == "DD/MM/YYYY", user.Location())
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "format must be one of csv, ofx, qif")
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Failed to import expenses: "+err.Error())
+		return
+	}
+
+	// VALIDATE AND SAVE
+	result, err := h.expenseImportService.Import(user, candidates, rowErrors, opts)
+	if errors.Is(err, services.ErrInvalidImportOption) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to import expenses")
+		return
+	}
+
+	if dryRun {
+		utils.SuccessResponse(c, http.StatusOK, "Expenses validated successfully", result)
+		return
+	}
+
+	if len(result.Errors) > 0 {
+		c.JSON(http.StatusUnprocessableEntity, utils.Response[any]{
+			Success: false,
+			Message: "Validation Error",
+			Data:    result,
+			Error:   "Some rows are invalid, no expenses were imported",
+		})
+		return
+	}
+
+	// RAISE BUDGET ALERTS FOR THE IMPORTED CATEGORIES
+	categoryIDs := make([]uint, 0, len(result.Expenses))
+	for _, expense := range result.Expenses {
+		categoryIDs = append(categoryIDs, expense.CategoryID)
+	}
+	if _, err := h.budgetAlertService.Evaluate(user, categoryIDs...); err != nil {
+		log.Printf("Failed to evaluate budget alerts for user %d: %v", user.ID, err)
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Expenses imported successfully", result)
+}
+
+// READ THE CSV COLUMN MAPPING AND FORMAT OPTIONS FROM THE FORM
+func bindCSVOptions(c *gin.Context) (services.ExpenseCSVOptions, bool) {
+	delimiter, err := parseCSVDelimiter(c.DefaultPostForm("delimiter", "comma"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return services.ExpenseCSVOptions{}, false
+	}
+
+	hasHeader, err := strconv.ParseBool(c.DefaultPostForm("header", "true"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "header must be true or false")
+		return services.ExpenseCSVOptions{}, false
+	}
+
+	return services.ExpenseCSVOptions{
+		Delimiter:      delimiter,
+		HasHeader:      hasHeader,
+		DateColumn:     c.DefaultPostForm("date_column", "date"),
+		NameColumn:     c.DefaultPostForm("name_column", "name"),
+		AmountColumn:   c.DefaultPostForm("amount_column", "amount"),
+		CategoryColumn: c.DefaultPostForm("category_column", "category"),
+		CurrencyColumn: c.PostForm("currency_column"),
+		DateFormat:     c.PostForm("date_format"),
+	}, true
+}
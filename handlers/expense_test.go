@@ -0,0 +1,87 @@
+package handlers
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+func TestParseCSVDelimiter(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    rune
+		wantErr bool
+	}{
+		{value: "comma", want: ','},
+		{value: "semicolon", want: ';'},
+		{value: "tab", want: '\t'},
+		{value: `\t`, want: '\t'},
+		{value: "pipe", want: '|'},
+		{value: ":", want: ':'},
+		{value: "§", want: '§'},
+		{value: "", wantErr: true},
+		{value: ",;", wantErr: true},
+		{value: `"`, wantErr: true},
+		{value: "\n", wantErr: true},
+		{value: "\xff", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseCSVDelimiter(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseCSVDelimiter(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseCSVDelimiter(%q) = %q, want %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpenseCSVRow(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	at := time.Date(2025, 1, 15, 20, 0, 0, 0, time.UTC)
+
+	amount, _ := models.ParseMoney("1500")
+	baseAmount, _ := models.ParseMoney("0.0895")
+	expense := &models.Expense{
+		ID:           42,
+		Name:         "=HYPERLINK(\"http://example.com\")",
+		Amount:       amount,
+		Currency:     "JPY",
+		BaseAmount:   baseAmount,
+		BaseCurrency: "EUR",
+		SpentAt:      at,
+		Category:     models.Category{Name: "@Food", Type: "expense"},
+		CreatedAt:    at,
+		UpdatedAt:    at,
+	}
+
+	want := []string{"42", "2025-01-16T03:00:00+07:00", "'=HYPERLINK(\"http://example.com\")", "'@Food", "expense", "1500", "JPY", "0.09", "EUR", "2025-01-16T03:00:00+07:00", "2025-01-16T03:00:00+07:00"}
+	if got := expenseCSVRow(expense, jakarta); !reflect.DeepEqual(got, want) {
+		t.Errorf("expenseCSVRow() = %q, want %q", got, want)
+	}
+	if len(want) != len(expenseCSVHeader) {
+		t.Errorf("row has %d columns, header %d", len(want), len(expenseCSVHeader))
+	}
+}
+
+func TestCSVText(t *testing.T) {
+	for value, want := range map[string]string{
+		"":            "",
+		"Coffee":      "Coffee",
+		"=1+1":        "'=1+1",
+		"+31 Shop":    "'+31 Shop",
+		"-refund":     "'-refund",
+		"@SUM(A1:A2)": "'@SUM(A1:A2)",
+		"\tcmd":       "'\tcmd",
+		"Shop = fun":  "Shop = fun",
+	} {
+		if got := csvText(value); got != want {
+			t.Errorf("csvText(%q) = %q, want %q", value, got, want)
+		}
+	}
+}
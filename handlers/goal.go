@@ -0,0 +1,348 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type GoalHandler struct {
+	goalRepo    *repositories.GoalRepository
+	userRepo    *repositories.UserRepository
+	accountRepo *repositories.AccountRepository
+	goalService *services.GoalService
+	validator   *validator.Validate
+}
+
+func NewGoalHandler(goalRepo *repositories.GoalRepository, userRepo *repositories.UserRepository, accountRepo *repositories.AccountRepository, goalService *services.GoalService) *GoalHandler {
+	return &GoalHandler{
+		goalRepo:    goalRepo,
+		userRepo:    userRepo,
+		accountRepo: accountRepo,
+		goalService: goalService,
+		validator:   validator.New(),
+	}
+}
+
+// GET GOALS BY USER ID
+// GetGoalsByUserID godoc
+// @Summary Get goals by user ID
+// @Description Get all savings goals of the authenticated user
+// @Tags goals
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by goal name"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Goal]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /goals [get]
+func (h *GoalHandler) GetGoalsByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET GOALS BY USER ID
+	goals, total, totalPages, err := h.goalRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get goals")
+		return
+	}
+
+	response := gin.H{
+		"data":        goals,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Goals retrieved successfully", response)
+}
+
+// GET GOAL BY ID
+// GetGoalByID godoc
+// @Summary Get a goal by ID
+// @Description Get a savings goal by ID for the authenticated user
+// @Tags goals
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} utils.Response[models.Goal]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /goals/{id} [get]
+func (h *GoalHandler) GetGoalByID(c *gin.Context) {
+	goal, _, ok := h.getOwnedGoal(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Goal retrieved successfully", goal)
+}
+
+// GET GOAL PROGRESS
+// GetGoalProgress godoc
+// @Summary Get the progress of a goal
+// @Description Get saved, remaining and the monthly contribution needed to reach a goal by its target date, in the user base currency. Contributions are expenses with the goal_id of the goal
+// @Tags goals
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} utils.Response[models.GoalProgress]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /goals/{id}/progress [get]
+func (h *GoalHandler) GetGoalProgress(c *gin.Context) {
+	goal, user, ok := h.getOwnedGoal(c)
+	if !ok {
+		return
+	}
+
+	progress, err := h.goalService.Progress(user, goal, time.Now())
+	if errors.Is(err, services.ErrExchangeRateNotFound) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to compute goal progress")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Goal progress retrieved successfully", progress)
+}
+
+// CREATE GOAL
+// CreateGoal godoc
+// @Summary Create a goal
+// @Description Create a savings goal, the target amount is in the user base currency
+// @Tags goals
+// @Accept  json
+// @Produce  json
+// @Param request body models.GoalRequest true "Goal data"
+// @Success 201 {object} utils.Response[models.Goal]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /goals [post]
+func (h *GoalHandler) CreateGoal(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	goal := &models.Goal{
+		UserID: user.ID,
+	}
+
+	if !h.bindGoal(c, user, goal) {
+		return
+	}
+
+	if err := h.goalRepo.Create(goal); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create goal")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Goal created successfully", goal)
+}
+
+// UPDATE GOAL
+// UpdateGoal godoc
+// @Summary Update a goal
+// @Description Update a savings goal for the authenticated user
+// @Tags goals
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Goal ID"
+// @Param request body models.GoalRequest true "Goal data"
+// @Success 200 {object} utils.Response[models.Goal]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /goals/{id} [put]
+func (h *GoalHandler) UpdateGoal(c *gin.Context) {
+	goal, user, ok := h.getOwnedGoal(c)
+	if !ok {
+		return
+	}
+
+	if !h.bindGoal(c, user, goal) {
+		return
+	}
+
+	if err := h.goalRepo.Update(goal); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update goal")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Goal updated successfully", goal)
+}
+
+// DELETE GOAL
+// DeleteGoal godoc
+// @Summary Delete a goal
+// @Description Delete a savings goal for the authenticated user, its contributions are kept as ordinary expenses
+// @Tags goals
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Goal ID"
+// @Success 200 {object} utils.Response[models.Goal]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /goals/{id} [delete]
+func (h *GoalHandler) DeleteGoal(c *gin.Context) {
+	goal, _, ok := h.getOwnedGoal(c)
+	if !ok {
+		return
+	}
+
+	if err := h.goalRepo.Delete(goal); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete goal")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Goal deleted successfully", goal)
+}
+
+// GET THE GOAL FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *GoalHandler) getOwnedGoal(c *gin.Context) (*models.Goal, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET GOAL ID FROM URL PARAM
+	goalID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid goal ID")
+		return nil, nil, false
+	}
+
+	// GET GOAL BY ID
+	goal, err := h.goalRepo.GetByID(uint(goalID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Goal not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF GOAL BELONGS TO USER
+	if goal.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Goal does not belong to this user")
+		return nil, nil, false
+	}
+
+	return goal, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO goal, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *GoalHandler) bindGoal(c *gin.Context, user *models.User, goal *models.Goal) bool {
+	var req models.GoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	// VALIDATE AMOUNT PRECISION AGAINST THE BASE CURRENCY MINOR UNITS
+	if !req.TargetAmount.FitsExponent(models.CurrencyExponent(user.BaseCurrency)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Target amount has more decimal places than "+user.BaseCurrency+" allows")
+		return false
+	}
+
+	// PARSE TARGET DATE
+	var targetDate *time.Time
+	if req.TargetDate != "" {
+		date, _, err := utils.ParseDateTime(req.TargetDate, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "target_date: "+err.Error())
+			return false
+		}
+		targetDate = &date
+	}
+
+	// VALIDATE ACCOUNT BELONGING TO USER
+	var account *models.Account
+	if req.AccountID != nil && *req.AccountID != 0 {
+		var err error
+		account, err = h.accountRepo.GetByID(*req.AccountID)
+		if err != nil || account.UserID != user.ID {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid account ID")
+			return false
+		}
+	}
+
+	goal.Name = strings.TrimSpace(req.Name)
+	goal.TargetAmount = req.TargetAmount
+	goal.Currency = user.BaseCurrency
+	goal.TargetDate = targetDate
+	goal.AccountID = nil
+	goal.Account = account
+	if account != nil {
+		goal.AccountID = &account.ID
+	}
+
+	return true
+}
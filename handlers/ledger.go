@@ -0,0 +1,794 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type LedgerHandler struct {
+	ledgerRepo    *repositories.LedgerRepository
+	userRepo      *repositories.UserRepository
+	ledgerService *services.LedgerService
+	validator     *validator.Validate
+}
+
+func NewLedgerHandler(ledgerRepo *repositories.LedgerRepository, userRepo *repositories.UserRepository, ledgerService *services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{
+		ledgerRepo:    ledgerRepo,
+		userRepo:      userRepo,
+		ledgerService: ledgerService,
+		validator:     validator.New(),
+	}
+}
+
+// GET LEDGERS BY USER ID
+// GetLedgersByUserID godoc
+// @Summary Get ledgers by user ID
+// @Description Get the shared ledgers the authenticated user is a member of, with their role in each. Send the ledger ID in the X-Ledger-ID header to work on its expenses, categories and reports
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by ledger name"
+// @Param role query string false "Filter by role (owner, editor or viewer)"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Ledger]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers [get]
+func (h *LedgerHandler) GetLedgersByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET LEDGERS BY USER ID
+	ledgers, total, totalPages, err := h.ledgerRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledgers")
+		return
+	}
+
+	response := gin.H{
+		"data":        ledgers,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledgers retrieved successfully", response)
+}
+
+// GET LEDGER BY ID
+// GetLedgerByID godoc
+// @Summary Get ledger by ID
+// @Description Get a ledger with its members, for any of its members
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Success 200 {object} utils.Response[models.Ledger]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id} [get]
+func (h *LedgerHandler) GetLedgerByID(c *gin.Context) {
+	member, _, ok := h.getLedgerMember(c, false)
+	if !ok {
+		return
+	}
+
+	ledger, err := h.ledgerRepo.GetByID(member.LedgerID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger")
+		return
+	}
+	ledger.Role = member.Role
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger retrieved successfully", ledger)
+}
+
+// CREATE LEDGER
+// CreateLedger godoc
+// @Summary Create a ledger
+// @Description Create a shared ledger, the authenticated user becomes its owner
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param request body models.LedgerRequest true "Ledger data"
+// @Success 201 {object} utils.Response[models.Ledger]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers [post]
+func (h *LedgerHandler) CreateLedger(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	ledger := &models.Ledger{
+		OwnerID:      user.ID,
+		BaseCurrency: user.BaseCurrency,
+		Members: []models.LedgerMember{
+			{UserID: user.ID, Role: models.LedgerRoleOwner},
+		},
+	}
+
+	if !h.bindLedger(c, ledger) {
+		return
+	}
+
+	if err := h.ledgerRepo.Create(ledger); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create ledger")
+		return
+	}
+	ledger.Role = models.LedgerRoleOwner
+
+	utils.SuccessResponse(c, http.StatusCreated, "Ledger created successfully", ledger)
+}
+
+// UPDATE LEDGER
+// UpdateLedger godoc
+// @Summary Update a ledger
+// @Description Rename a ledger or change its base currency, owner only. The base currency cannot change once the ledger has expenses
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param request body models.LedgerRequest true "Ledger data"
+// @Success 200 {object} utils.Response[models.Ledger]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id} [put]
+func (h *LedgerHandler) UpdateLedger(c *gin.Context) {
+	member, _, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	ledger := member.Ledger
+	baseCurrency := ledger.BaseCurrency
+
+	if !h.bindLedger(c, ledger) {
+		return
+	}
+
+	// BASE AMOUNTS OF THE LEDGER EXPENSES ARE IN ITS BASE CURRENCY, IT CANNOT CHANGE UNDER THEM
+	if ledger.BaseCurrency != baseCurrency {
+		expenses, err := h.ledgerRepo.CountExpenses(ledger.ID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update ledger")
+			return
+		}
+		if expenses > 0 {
+			utils.ErrorResponse(c, http.StatusConflict, "Ledger base currency cannot change once it has expenses")
+			return
+		}
+	}
+
+	if err := h.ledgerRepo.Update(ledger); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update ledger")
+		return
+	}
+	ledger.Role = member.Role
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger updated successfully", ledger)
+}
+
+// DELETE LEDGER
+// DeleteLedger godoc
+// @Summary Delete a ledger
+// @Description Delete a ledger with its members, invitations, settlements and categories, owner only. Ledgers with expenses cannot be deleted until the expenses are
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Success 200 {object} utils.Response[models.Ledger]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id} [delete]
+func (h *LedgerHandler) DeleteLedger(c *gin.Context) {
+	member, _, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	expenses, err := h.ledgerRepo.CountExpenses(member.LedgerID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete ledger")
+		return
+	}
+	if expenses > 0 {
+		utils.ErrorResponse(c, http.StatusConflict, "Ledger has expenses, delete them and empty the trash first")
+		return
+	}
+
+	if err := h.ledgerRepo.Delete(member.Ledger); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete ledger")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger deleted successfully", member.Ledger)
+}
+
+// UPDATE LEDGER MEMBER
+// UpdateLedgerMember godoc
+// @Summary Change the role of a ledger member
+// @Description Make a member an editor or a viewer, owner only
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param user_id path int true "User ID of the member"
+// @Param request body models.LedgerMemberRequest true "Member role"
+// @Success 200 {object} utils.Response[models.LedgerMember]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/members/{user_id} [put]
+func (h *LedgerHandler) UpdateLedgerMember(c *gin.Context) {
+	owner, _, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	member, ok := h.getMemberParam(c, owner.LedgerID)
+	if !ok {
+		return
+	}
+
+	// THERE IS ALWAYS EXACTLY ONE OWNER
+	if member.Role == models.LedgerRoleOwner {
+		utils.ErrorResponse(c, http.StatusBadRequest, "The role of the ledger owner cannot change")
+		return
+	}
+
+	var req models.LedgerMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	member.Role = req.Role
+	if err := h.ledgerRepo.UpdateMemberRole(member); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update ledger member")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger member updated successfully", member)
+}
+
+// DELETE LEDGER MEMBER
+// DeleteLedgerMember godoc
+// @Summary Remove a ledger member
+// @Description The owner removes a member, or a member leaves the ledger with their own user ID. Expenses the member added stay in the ledger, a member with an open balance must settle it first
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param user_id path int true "User ID of the member"
+// @Success 200 {object} utils.Response[models.LedgerMember]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/members/{user_id} [delete]
+func (h *LedgerHandler) DeleteLedgerMember(c *gin.Context) {
+	current, user, ok := h.getLedgerMember(c, false)
+	if !ok {
+		return
+	}
+
+	member, ok := h.getMemberParam(c, current.LedgerID)
+	if !ok {
+		return
+	}
+
+	// ONLY THE OWNER REMOVES OTHER MEMBERS
+	if member.UserID != user.ID && current.Role != models.LedgerRoleOwner {
+		utils.ErrorResponse(c, http.StatusForbidden, "Only the ledger owner can remove members")
+		return
+	}
+	if member.Role == models.LedgerRoleOwner {
+		utils.ErrorResponse(c, http.StatusBadRequest, "The ledger owner cannot leave, delete the ledger instead")
+		return
+	}
+
+	// A MEMBER LEAVES ONLY ONCE THEIR SHARED EXPENSES ARE SETTLED
+	balances, err := h.ledgerRepo.MemberBalances(member.LedgerID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger balances")
+		return
+	}
+	for _, balance := range balances {
+		if balance.UserID == member.UserID && balance.Balance != 0 {
+			utils.ErrorResponse(c, http.StatusConflict, "The member has an open balance, settle it first")
+			return
+		}
+	}
+
+	if err := h.ledgerRepo.DeleteMember(member); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to remove ledger member")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger member removed successfully", member)
+}
+
+// GET LEDGER INVITATIONS
+// GetLedgerInvitations godoc
+// @Summary Get the pending invitations of a ledger
+// @Description Get the invitations of a ledger that were not accepted or declined yet, owner only
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Success 200 {object} utils.Response[[]models.LedgerInvitation]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/invitations [get]
+func (h *LedgerHandler) GetLedgerInvitations(c *gin.Context) {
+	owner, _, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	invitations, err := h.ledgerRepo.GetInvitationsByLedgerID(owner.LedgerID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger invitations")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger invitations retrieved successfully", invitations)
+}
+
+// CREATE LEDGER INVITATION
+// CreateLedgerInvitation godoc
+// @Summary Invite a user to a ledger
+// @Description Invite an email address as editor or viewer, owner only. The invited user accepts it with the invitation ID, registering with that email first if needed
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param request body models.LedgerInvitationRequest true "Invitation data"
+// @Success 201 {object} utils.Response[models.LedgerInvitation]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/invitations [post]
+func (h *LedgerHandler) CreateLedgerInvitation(c *gin.Context) {
+	owner, user, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	var req models.LedgerInvitationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	email := strings.ToLower(strings.TrimSpace(req.Email))
+
+	// A USER IS A MEMBER AT MOST ONCE
+	if invited, err := h.userRepo.GetByEmail(email); err == nil {
+		if _, err := h.ledgerRepo.GetMember(owner.LedgerID, invited.ID); err == nil {
+			utils.ErrorResponse(c, http.StatusConflict, "User is already a member of this ledger")
+			return
+		}
+	}
+	if _, err := h.ledgerRepo.GetInvitationByEmail(owner.LedgerID, email); err == nil {
+		utils.ErrorResponse(c, http.StatusConflict, "Email is already invited to this ledger")
+		return
+	}
+
+	invitation := &models.LedgerInvitation{
+		LedgerID:    owner.LedgerID,
+		Email:       email,
+		Role:        req.Role,
+		InvitedByID: user.ID,
+	}
+
+	if err := h.ledgerRepo.CreateInvitation(invitation); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create ledger invitation")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Ledger invitation created successfully", invitation)
+}
+
+// DELETE LEDGER INVITATION
+// DeleteLedgerInvitation godoc
+// @Summary Revoke a ledger invitation
+// @Description Revoke a pending invitation, owner only
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param invitation_id path int true "Invitation ID"
+// @Success 200 {object} utils.Response[models.LedgerInvitation]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/invitations/{invitation_id} [delete]
+func (h *LedgerHandler) DeleteLedgerInvitation(c *gin.Context) {
+	owner, _, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	// GET INVITATION ID FROM URL PARAM
+	invitationID, err := strconv.ParseUint(c.Param("invitation_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID")
+		return
+	}
+
+	// GET INVITATION BY ID
+	invitation, err := h.ledgerRepo.GetInvitationByID(uint(invitationID))
+	if err != nil || invitation.LedgerID != owner.LedgerID {
+		utils.ErrorResponse(c, http.StatusNotFound, "Invitation not found")
+		return
+	}
+
+	if err := h.ledgerRepo.DeleteInvitation(invitation); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to revoke ledger invitation")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger invitation revoked successfully", invitation)
+}
+
+// GET MY LEDGER INVITATIONS
+// GetMyLedgerInvitations godoc
+// @Summary Get the ledger invitations of the user
+// @Description Get the pending ledger invitations sent to the email of the authenticated user
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} utils.Response[[]models.LedgerInvitation]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/invitations [get]
+func (h *LedgerHandler) GetMyLedgerInvitations(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	invitations, err := h.ledgerRepo.GetInvitationsByEmail(user.Email)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger invitations")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger invitations retrieved successfully", invitations)
+}
+
+// ACCEPT LEDGER INVITATION
+// AcceptLedgerInvitation godoc
+// @Summary Accept a ledger invitation
+// @Description Join the ledger of an invitation sent to the email of the authenticated user, with the invited role
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param invitation_id path int true "Invitation ID"
+// @Success 200 {object} utils.Response[models.LedgerMember]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/invitations/{invitation_id}/accept [post]
+func (h *LedgerHandler) AcceptLedgerInvitation(c *gin.Context) {
+	invitation, user, ok := h.getOwnInvitation(c)
+	if !ok {
+		return
+	}
+
+	if _, err := h.ledgerRepo.GetMember(invitation.LedgerID, user.ID); err == nil {
+		utils.ErrorResponse(c, http.StatusConflict, "You are already a member of this ledger")
+		return
+	}
+
+	member := &models.LedgerMember{
+		LedgerID: invitation.LedgerID,
+		UserID:   user.ID,
+		Role:     invitation.Role,
+	}
+
+	if err := h.ledgerRepo.AcceptInvitation(invitation, member); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to accept ledger invitation")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger invitation accepted successfully", member)
+}
+
+// DECLINE LEDGER INVITATION
+// DeclineLedgerInvitation godoc
+// @Summary Decline a ledger invitation
+// @Description Decline an invitation sent to the email of the authenticated user
+// @Tags ledgers
+// @Accept  json
+// @Produce  json
+// @Param invitation_id path int true "Invitation ID"
+// @Success 200 {object} utils.Response[models.LedgerInvitation]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/invitations/{invitation_id}/decline [post]
+func (h *LedgerHandler) DeclineLedgerInvitation(c *gin.Context) {
+	invitation, _, ok := h.getOwnInvitation(c)
+	if !ok {
+		return
+	}
+
+	if err := h.ledgerRepo.DeleteInvitation(invitation); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to decline ledger invitation")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger invitation declined successfully", invitation)
+}
+
+// GET THE MEMBERSHIP OF THE USER IN THE LEDGER FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR NOT A MEMBER, OR NOT THE OWNER WHEN owner IS SET
+func (h *LedgerHandler) getLedgerMember(c *gin.Context, owner bool) (*models.LedgerMember, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET LEDGER ID FROM URL PARAM
+	ledgerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ledger ID")
+		return nil, nil, false
+	}
+
+	// LEDGERS ARE ONLY VISIBLE TO THEIR MEMBERS
+	member, err := h.ledgerService.Member(uint(ledgerID), user.ID)
+	if errors.Is(err, services.ErrLedgerAccessDenied) {
+		utils.ErrorResponse(c, http.StatusNotFound, "Ledger not found")
+		return nil, nil, false
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger")
+		return nil, nil, false
+	}
+
+	if owner && member.Role != models.LedgerRoleOwner {
+		utils.ErrorResponse(c, http.StatusForbidden, "Only the ledger owner can do this")
+		return nil, nil, false
+	}
+
+	return member, user, true
+}
+
+// GET THE MEMBER FROM THE user_id URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *LedgerHandler) getMemberParam(c *gin.Context, ledgerID uint) (*models.LedgerMember, bool) {
+	memberUserID, err := strconv.ParseUint(c.Param("user_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid user ID")
+		return nil, false
+	}
+
+	member, err := h.ledgerRepo.GetMember(ledgerID, uint(memberUserID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Ledger member not found")
+		return nil, false
+	}
+	member.Ledger = nil
+
+	return member, true
+}
+
+// GET THE INVITATION FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR IT WAS NOT SENT TO THEIR EMAIL
+func (h *LedgerHandler) getOwnInvitation(c *gin.Context) (*models.LedgerInvitation, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET INVITATION ID FROM URL PARAM
+	invitationID, err := strconv.ParseUint(c.Param("invitation_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid invitation ID")
+		return nil, nil, false
+	}
+
+	// GET INVITATION BY ID
+	invitation, err := h.ledgerRepo.GetInvitationByID(uint(invitationID))
+	if err != nil || !strings.EqualFold(invitation.Email, user.Email) {
+		utils.ErrorResponse(c, http.StatusNotFound, "Invitation not found")
+		return nil, nil, false
+	}
+
+	return invitation, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO ledger, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *LedgerHandler) bindLedger(c *gin.Context, ledger *models.Ledger) bool {
+	var req models.LedgerRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Ledger name is required")
+		return false
+	}
+
+	// RESOLVE BASE CURRENCY, KEEP THE CURRENT ONE WHEN OMITTED
+	if req.BaseCurrency != "" {
+		ledger.BaseCurrency = models.NormalizeCurrency(req.BaseCurrency)
+	}
+
+	ledger.Name = name
+
+	return true
+}
+
+// THE LEDGER MEMBERSHIP SELECTED WITH THE X-Ledger-ID HEADER, NIL FOR THE PERSONAL DATA OF THE USER
+func currentLedgerMember(c *gin.Context) *models.LedgerMember {
+	member, exists := c.Get("ledger_member")
+	if !exists {
+		return nil
+	}
+
+	return member.(*models.LedgerMember)
+}
+
+// THE LEDGER SELECTED WITH THE X-Ledger-ID HEADER, NIL FOR THE PERSONAL DATA OF THE USER
+func currentLedgerID(c *gin.Context) *uint {
+	member := currentLedgerMember(c)
+	if member == nil {
+		return nil
+	}
+
+	return &member.LedgerID
+}
+
+// BASE CURRENCY OF THE LEDGER OF member, OR OF THE USER FOR PERSONAL DATA
+func ledgerBaseCurrency(user *models.User, member *models.LedgerMember) string {
+	if member != nil && member.Ledger != nil {
+		return member.Ledger.BaseCurrency
+	}
+
+	return user.BaseCurrency
+}
+
+// WRITE THE ERROR RESPONSE OF A FAILED LedgerService.Authorize, RETURNS FALSE WHEN err IS NIL
+// deniedStatus AND deniedMessage ARE USED WHEN THE RECORD IS NEITHER THE USER'S NOR IN ONE OF THEIR LEDGERS
+func ledgerAccessError(c *gin.Context, err error, deniedStatus int, deniedMessage string) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, services.ErrLedgerAccessDenied):
+		utils.ErrorResponse(c, deniedStatus, deniedMessage)
+	case errors.Is(err, services.ErrLedgerReadOnly):
+		utils.ErrorResponse(c, http.StatusForbidden, "Viewers cannot change this ledger")
+	default:
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger")
+	}
+
+	return true
+}
@@ -0,0 +1,42 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-expense-tracker-api/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestLedgerAccessError(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name       string
+		err        error
+		wantStatus int
+	}{
+		{name: "authorized", err: nil, wantStatus: http.StatusOK},
+		{name: "not a member", err: services.ErrLedgerAccessDenied, wantStatus: http.StatusNotFound},
+		{name: "viewer writes", err: services.ErrLedgerReadOnly, wantStatus: http.StatusForbidden},
+		{name: "store error", err: errors.New("connection lost"), wantStatus: http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+
+			failed := ledgerAccessError(c, tt.err, http.StatusNotFound, "Expense not found")
+			if failed != (tt.err != nil) {
+				t.Errorf("ledgerAccessError() = %t, want %t", failed, tt.err != nil)
+			}
+			if w.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", w.Code, tt.wantStatus)
+			}
+		})
+	}
+}
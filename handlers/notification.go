@@ -0,0 +1,165 @@
+package handlers
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	notificationRepo *repositories.NotificationRepository
+	userRepo         *repositories.UserRepository
+}
+
+func NewNotificationHandler(notificationRepo *repositories.NotificationRepository, userRepo *repositories.UserRepository) *NotificationHandler {
+	return &NotificationHandler{
+		notificationRepo: notificationRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// GET NOTIFICATIONS BY USER ID
+// GetNotificationsByUserID godoc
+// @Summary Get notifications by user ID
+// @Description Get in-app notifications such as budget alerts for the authenticated user
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param type query string false "Filter by notification type"
+// @Param is_read query bool false "Filter by read state"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Notification]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /notifications [get]
+func (h *NotificationHandler) GetNotificationsByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET NOTIFICATIONS BY USER ID
+	notifications, total, totalPages, err := h.notificationRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get notifications")
+		return
+	}
+
+	response := gin.H{
+		"data":        notifications,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications retrieved successfully", response)
+}
+
+// MARK NOTIFICATION AS READ
+// MarkNotificationAsRead godoc
+// @Summary Mark a notification as read
+// @Description Mark a notification of the authenticated user as read
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Notification ID"
+// @Success 200 {object} utils.Response[models.Notification]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /notifications/{id}/read [put]
+func (h *NotificationHandler) MarkNotificationAsRead(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET NOTIFICATION ID FROM URL PARAM
+	notificationID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid notification ID")
+		return
+	}
+
+	// GET NOTIFICATION BY ID
+	notification, err := h.notificationRepo.GetByID(uint(notificationID))
+	if err != nil || notification.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusNotFound, "Notification not found")
+		return
+	}
+
+	if err := h.notificationRepo.MarkAsRead(notification); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update notification")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notification marked as read", notification)
+}
+
+// MARK ALL NOTIFICATIONS AS READ
+// MarkAllNotificationsAsRead godoc
+// @Summary Mark all notifications as read
+// @Description Mark every unread notification of the authenticated user as read
+// @Tags notifications
+// @Accept  json
+// @Produce  json
+// @Success 200 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /notifications/read-all [put]
+func (h *NotificationHandler) MarkAllNotificationsAsRead(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	updated, err := h.notificationRepo.MarkAllAsRead(user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update notifications")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Notifications marked as read", gin.H{"updated": updated})
+}
@@ -0,0 +1,301 @@
+package handlers
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type PayeeHandler struct {
+	payeeRepo *repositories.PayeeRepository
+	userRepo  *repositories.UserRepository
+	validator *validator.Validate
+}
+
+func NewPayeeHandler(payeeRepo *repositories.PayeeRepository, userRepo *repositories.UserRepository) *PayeeHandler {
+	return &PayeeHandler{
+		payeeRepo: payeeRepo,
+		userRepo:  userRepo,
+		validator: validator.New(),
+	}
+}
+
+// GET PAYEES BY USER ID
+// GetPayeesByUserID godoc
+// @Summary Get payees by user ID
+// @Description Get all payees with their aliases for the authenticated user
+// @Tags payees
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by payee name"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Payee]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /payees [get]
+func (h *PayeeHandler) GetPayeesByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET PAYEES BY USER ID
+	payees, total, totalPages, err := h.payeeRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get payees")
+		return
+	}
+
+	response := gin.H{
+		"data":        payees,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payees retrieved successfully", response)
+}
+
+// GET PAYEE BY ID
+// GetPayeeByID godoc
+// @Summary Get payee by ID
+// @Description Get a payee with its aliases for the authenticated user
+// @Tags payees
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Payee ID"
+// @Success 200 {object} utils.Response[models.Payee]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /payees/{id} [get]
+func (h *PayeeHandler) GetPayeeByID(c *gin.Context) {
+	payee, _, ok := h.getOwnedPayee(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payee retrieved successfully", payee)
+}
+
+// CREATE PAYEE
+// CreatePayee godoc
+// @Summary Create a payee
+// @Description Create a payee with alias rules (exact, prefix, contains or regex, case insensitive) that map raw expense names to it on create and import
+// @Tags payees
+// @Accept  json
+// @Produce  json
+// @Param request body models.PayeeRequest true "Payee data"
+// @Success 201 {object} utils.Response[models.Payee]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /payees [post]
+func (h *PayeeHandler) CreatePayee(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	payee := &models.Payee{
+		UserID: user.ID,
+	}
+
+	if !h.bindPayee(c, payee) {
+		return
+	}
+
+	if err := h.payeeRepo.Create(payee); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create payee")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Payee created successfully", payee)
+}
+
+// UPDATE PAYEE
+// UpdatePayee godoc
+// @Summary Update a payee
+// @Description Rename a payee and replace its alias rules, expenses already linked keep the payee
+// @Tags payees
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Payee ID"
+// @Param request body models.PayeeRequest true "Payee data"
+// @Success 200 {object} utils.Response[models.Payee]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /payees/{id} [put]
+func (h *PayeeHandler) UpdatePayee(c *gin.Context) {
+	payee, _, ok := h.getOwnedPayee(c)
+	if !ok {
+		return
+	}
+
+	if !h.bindPayee(c, payee) {
+		return
+	}
+
+	if err := h.payeeRepo.Update(payee); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update payee")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payee updated successfully", payee)
+}
+
+// DELETE PAYEE
+// DeletePayee godoc
+// @Summary Delete a payee
+// @Description Delete a payee and its aliases, its expenses are kept without a payee
+// @Tags payees
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Payee ID"
+// @Success 200 {object} utils.Response[models.Payee]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /payees/{id} [delete]
+func (h *PayeeHandler) DeletePayee(c *gin.Context) {
+	payee, _, ok := h.getOwnedPayee(c)
+	if !ok {
+		return
+	}
+
+	if err := h.payeeRepo.Delete(payee); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete payee")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payee deleted successfully", payee)
+}
+
+// GET THE PAYEE FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *PayeeHandler) getOwnedPayee(c *gin.Context) (*models.Payee, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET PAYEE ID FROM URL PARAM
+	payeeID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid payee ID")
+		return nil, nil, false
+	}
+
+	// GET PAYEE BY ID
+	payee, err := h.payeeRepo.GetByID(uint(payeeID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Payee not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF PAYEE BELONGS TO USER
+	if payee.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Payee does not belong to this user")
+		return nil, nil, false
+	}
+
+	return payee, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO payee, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *PayeeHandler) bindPayee(c *gin.Context, payee *models.Payee) bool {
+	var req models.PayeeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Payee name is required")
+		return false
+	}
+
+	// PAYEE NAMES ARE UNIQUE PER USER
+	if existing, err := h.payeeRepo.GetByName(payee.UserID, name); err == nil && existing.ID != payee.ID {
+		utils.ErrorResponse(c, http.StatusConflict, "Payee already exists")
+		return false
+	}
+
+	aliases := make([]models.PayeeAlias, 0, len(req.Aliases))
+	for _, alias := range req.Aliases {
+		if alias.MatchType == models.PayeeMatchRegex {
+			if _, err := services.CompilePayeePattern(alias.Pattern); err != nil {
+				utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+				return false
+			}
+		}
+		aliases = append(aliases, models.PayeeAlias{
+			MatchType: alias.MatchType,
+			Pattern:   alias.Pattern,
+		})
+	}
+
+	payee.Name = name
+	payee.Aliases = aliases
+
+	return true
+}
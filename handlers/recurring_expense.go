@@ -0,0 +1,368 @@
+package handlers
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type RecurringExpenseHandler struct {
+	recurringExpenseRepo *repositories.RecurringExpenseRepository
+	userRepo             *repositories.UserRepository
+	categoryRepo         *repositories.CategoryRepository
+	validator            *validator.Validate
+}
+
+func NewRecurringExpenseHandler(recurringExpenseRepo *repositories.RecurringExpenseRepository, userRepo *repositories.UserRepository, categoryRepo *repositories.CategoryRepository) *RecurringExpenseHandler {
+	return &RecurringExpenseHandler{
+		recurringExpenseRepo: recurringExpenseRepo,
+		userRepo:             userRepo,
+		categoryRepo:         categoryRepo,
+		validator:            validator.New(),
+	}
+}
+
+// GET RECURRING EXPENSES BY USER ID
+// GetRecurringExpensesByUserID godoc
+// @Summary Get recurring expenses by user ID
+// @Description Get all recurring expenses and incomes for the authenticated user
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by name"
+// @Param frequency query string false "Filter by frequency (daily, weekly, monthly, yearly)"
+// @Param is_active query bool false "Filter by active state"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.RecurringExpense]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /recurring [get]
+func (h *RecurringExpenseHandler) GetRecurringExpensesByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET RECURRING EXPENSES BY USER ID
+	recurringExpenses, total, totalPages, err := h.recurringExpenseRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get recurring expenses")
+		return
+	}
+
+	response := gin.H{
+		"data":        recurringExpenses,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring expenses retrieved successfully", response)
+}
+
+// GET RECURRING EXPENSE BY ID
+// GetRecurringExpenseByID godoc
+// @Summary Get a recurring expense by ID
+// @Description Get a recurring expense by ID for the authenticated user
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Recurring expense ID"
+// @Success 200 {object} utils.Response[models.RecurringExpense]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /recurring/{id} [get]
+func (h *RecurringExpenseHandler) GetRecurringExpenseByID(c *gin.Context) {
+	recurringExpense, _, ok := h.getOwnedRecurringExpense(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring expense retrieved successfully", recurringExpense)
+}
+
+// CREATE RECURRING EXPENSE
+// CreateRecurringExpense godoc
+// @Summary Create a recurring expense
+// @Description Create a recurring expense or income, expenses are generated by the scheduler from start_date on
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param request body models.RecurringExpenseRequest true "Recurring expense data"
+// @Success 201 {object} utils.Response[models.RecurringExpense]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /recurring [post]
+func (h *RecurringExpenseHandler) CreateRecurringExpense(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	recurringExpense := &models.RecurringExpense{
+		UserID:   user.ID,
+		Currency: user.BaseCurrency,
+	}
+
+	if !h.bindRecurringExpense(c, user, recurringExpense) {
+		return
+	}
+
+	// FIRST OCCURRENCE IS THE START DATE
+	recurringExpense.NextRunAt = services.NextOccurrence(recurringExpense)
+
+	if err := h.recurringExpenseRepo.Create(recurringExpense); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create recurring expense")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Recurring expense created successfully", recurringExpense)
+}
+
+// UPDATE RECURRING EXPENSE
+// UpdateRecurringExpense godoc
+// @Summary Update a recurring expense
+// @Description Update a recurring expense, changing the schedule or resuming a paused one does not backfill occurrences before now
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Recurring expense ID"
+// @Param request body models.RecurringExpenseRequest true "Recurring expense data"
+// @Success 200 {object} utils.Response[models.RecurringExpense]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /recurring/{id} [put]
+func (h *RecurringExpenseHandler) UpdateRecurringExpense(c *gin.Context) {
+	recurringExpense, user, ok := h.getOwnedRecurringExpense(c)
+	if !ok {
+		return
+	}
+
+	previous := *recurringExpense
+
+	if !h.bindRecurringExpense(c, user, recurringExpense) {
+		return
+	}
+
+	// RESTART THE SCHEDULE WHEN IT CHANGED, SKIPPING OCCURRENCES BEFORE NOW IF IT ALREADY RAN
+	scheduleChanged := previous.Frequency != recurringExpense.Frequency ||
+		previous.Interval != recurringExpense.Interval ||
+		!previous.StartDate.Equal(recurringExpense.StartDate)
+
+	// RESUMING A PAUSED RECURRING EXPENSE SKIPS THE OCCURRENCES MISSED WHILE IT WAS PAUSED
+	resumed := !previous.IsActive && recurringExpense.IsActive
+
+	if scheduleChanged {
+		recurringExpense.OccurrenceCount = 0
+	}
+	if (scheduleChanged && previous.OccurrenceCount > 0) || resumed {
+		services.SkipOccurrencesBefore(recurringExpense, time.Now())
+	}
+
+	recurringExpense.NextRunAt = services.NextOccurrence(recurringExpense)
+
+	if err := h.recurringExpenseRepo.Update(recurringExpense); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update recurring expense")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring expense updated successfully", recurringExpense)
+}
+
+// DELETE RECURRING EXPENSE
+// DeleteRecurringExpense godoc
+// @Summary Delete a recurring expense
+// @Description Delete a recurring expense, expenses it already generated are kept
+// @Tags recurring
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Recurring expense ID"
+// @Success 200 {object} utils.Response[models.RecurringExpense]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /recurring/{id} [delete]
+func (h *RecurringExpenseHandler) DeleteRecurringExpense(c *gin.Context) {
+	recurringExpense, _, ok := h.getOwnedRecurringExpense(c)
+	if !ok {
+		return
+	}
+
+	if err := h.recurringExpenseRepo.Delete(recurringExpense); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete recurring expense")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Recurring expense deleted successfully", recurringExpense)
+}
+
+// GET THE RECURRING EXPENSE FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *RecurringExpenseHandler) getOwnedRecurringExpense(c *gin.Context) (*models.RecurringExpense, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET RECURRING EXPENSE ID FROM URL PARAM
+	recurringExpenseID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid recurring expense ID")
+		return nil, nil, false
+	}
+
+	// GET RECURRING EXPENSE BY ID
+	recurringExpense, err := h.recurringExpenseRepo.GetByID(uint(recurringExpenseID), user.ID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Recurring expense not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF RECURRING EXPENSE BELONGS TO USER
+	if recurringExpense.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Recurring expense does not belong to this user")
+		return nil, nil, false
+	}
+
+	return recurringExpense, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO recurringExpense, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *RecurringExpenseHandler) bindRecurringExpense(c *gin.Context, user *models.User, recurringExpense *models.RecurringExpense) bool {
+	var req models.RecurringExpenseRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	// RESOLVE CURRENCY, KEEP THE CURRENT ONE WHEN OMITTED
+	currency := recurringExpense.Currency
+	if req.Currency != "" {
+		currency = models.NormalizeCurrency(req.Currency)
+	}
+
+	// VALIDATE AMOUNT PRECISION AGAINST THE CURRENCY MINOR UNITS
+	if !req.Amount.FitsExponent(models.CurrencyExponent(currency)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Amount has more decimal places than "+currency+" allows")
+		return false
+	}
+
+	// PARSE SCHEDULE DATES
+	startDate, _, err := utils.ParseDateTime(req.StartDate, user.Location())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "start_date: "+err.Error())
+		return false
+	}
+
+	var endDate *time.Time
+	if req.EndDate != "" {
+		parsed, dateOnly, err := utils.ParseDateTime(req.EndDate, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "end_date: "+err.Error())
+			return false
+		}
+		// A PLAIN END DATE INCLUDES THE WHOLE DAY
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		if parsed.Before(startDate) {
+			utils.ErrorResponse(c, http.StatusBadRequest, "end_date must not be before start_date")
+			return false
+		}
+		endDate = &parsed
+	}
+
+	// VALIDATE CATEGORY ID
+	category, err := h.categoryRepo.GetEffectiveByID(user.ID, req.CategoryID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+		return false
+	}
+
+	// VALIDATE CATEGORY BELONGING TO USER, LEDGER CATEGORIES ARE NOT FOR PERSONAL EXPENSES
+	if !categoryUsable(category, user.ID, nil) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Category does not belong to this user")
+		return false
+	}
+
+	interval := req.Interval
+	if interval == 0 {
+		interval = 1
+	}
+
+	isActive := true
+	if req.IsActive != nil {
+		isActive = *req.IsActive
+	}
+
+	recurringExpense.Name = req.Name
+	recurringExpense.Amount = req.Amount
+	recurringExpense.Currency = currency
+	recurringExpense.CategoryID = category.ID
+	recurringExpense.Category = *category
+	recurringExpense.Frequency = req.Frequency
+	recurringExpense.Interval = interval
+	recurringExpense.StartDate = startDate
+	recurringExpense.EndDate = endDate
+	recurringExpense.IsActive = isActive
+
+	return true
+}
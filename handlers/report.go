@@ -0,0 +1,501 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/utils"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MAXIMUM NUMBER OF BUCKETS IN A TREND REPORT
+const maxTrendBuckets = 1000
+
+type ReportHandler struct {
+	reportRepo *repositories.ReportRepository
+	userRepo   *repositories.UserRepository
+}
+
+func NewReportHandler(reportRepo *repositories.ReportRepository, userRepo *repositories.UserRepository) *ReportHandler {
+	return &ReportHandler{
+		reportRepo: reportRepo,
+		userRepo:   userRepo,
+	}
+}
+
+// GET SUMMARY REPORT
+// GetSummaryReport godoc
+// @Summary Get summary report
+// @Description Get count, sum, average, min, max and share per category, split into income and expense, with net cash flow. Contributions to savings goals are left out, expenses still on a deleted category keep counting under it
+// @Tags reports
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Start date (YYYY-MM-DD or RFC3339), defaults to the first day of the current month"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD or RFC3339), defaults to the last day of the current month"
+// @Param rollup query bool false "Count subcategories under their top level category" default(false)
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
+// @Success 200 {object} utils.Response[models.SummaryReport]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /reports/summary [get]
+func (h *ReportHandler) GetSummaryReport(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// PARSE REPORT PERIOD
+	from, to, err := parseReportRange(c, user.Location())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	rollup, err := strconv.ParseBool(c.DefaultQuery("rollup", "false"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "rollup must be true or false")
+		return
+	}
+
+	// AGGREGATE PER CATEGORY
+	summaries, err := h.reportRepo.SummaryByCategory(user.ID, currentLedgerID(c), from, to, rollup)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get summary report")
+		return
+	}
+
+	report := models.SummaryReport{
+		From:     from,
+		To:       to,
+		Currency: ledgerBaseCurrency(user, currentLedgerMember(c)),
+		Income:   []models.CategorySummary{},
+		Expense:  []models.CategorySummary{},
+	}
+
+	// SPLIT INCOME AND EXPENSE BY CATEGORY TYPE
+	for _, summary := range summaries {
+		if summary.CategoryType == "income" {
+			report.TotalIncome += summary.Total
+			report.Income = append(report.Income, summary)
+		} else {
+			report.TotalExpense += summary.Total
+			report.Expense = append(report.Expense, summary)
+		}
+	}
+
+	setShares(report.Income, report.TotalIncome)
+	setShares(report.Expense, report.TotalExpense)
+	report.NetCashFlow = report.TotalIncome - report.TotalExpense
+
+	utils.SuccessResponse(c, http.StatusOK, "Summary report retrieved successfully", report)
+}
+
+// GET TAG REPORT
+// GetTagReport godoc
+// @Summary Get tag report
+// @Description Get count, income, expense and net per tag, an expense with several tags counts under each of them
+// @Tags reports
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Start date (YYYY-MM-DD or RFC3339), defaults to the first day of the current month"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD or RFC3339), defaults to the last day of the current month"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
+// @Success 200 {object} utils.Response[models.TagReport]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /reports/tags [get]
+func (h *ReportHandler) GetTagReport(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// PARSE REPORT PERIOD
+	from, to, err := parseReportRange(c, user.Location())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// AGGREGATE PER TAG
+	summaries, err := h.reportRepo.SummaryByTag(user.ID, currentLedgerID(c), from, to)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get tag report")
+		return
+	}
+
+	report := models.TagReport{
+		From:     from,
+		To:       to,
+		Currency: ledgerBaseCurrency(user, currentLedgerMember(c)),
+		Tags:     summaries,
+	}
+	if report.Tags == nil {
+		report.Tags = []models.TagSummary{}
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag report retrieved successfully", report)
+}
+
+// GET PAYEE REPORT
+// GetPayeeReport godoc
+// @Summary Get top payees report
+// @Description Get the payees with the largest totals, expenses without a payee are grouped by their raw name
+// @Tags reports
+// @Accept  json
+// @Produce  json
+// @Param from query string false "Start date (YYYY-MM-DD or RFC3339), defaults to the first day of the current month"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD or RFC3339), defaults to the last day of the current month"
+// @Param type query string false "Category type (expense or income)" default(expense)
+// @Param limit query int false "Number of payees" default(10)
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
+// @Success 200 {object} utils.Response[models.PayeeReport]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /reports/payees [get]
+func (h *ReportHandler) GetPayeeReport(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// PARSE REPORT PERIOD
+	from, to, err := parseReportRange(c, user.Location())
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// VALIDATE TYPE AND LIMIT
+	categoryType := c.DefaultQuery("type", "expense")
+	if categoryType != "expense" && categoryType != "income" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "type must be expense or income")
+		return
+	}
+
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit < 1 || limit > 100 {
+		utils.ErrorResponse(c, http.StatusBadRequest, "limit must be between 1 and 100")
+		return
+	}
+
+	// AGGREGATE PER PAYEE
+	summaries, err := h.reportRepo.SummaryByPayee(user.ID, currentLedgerID(c), categoryType, from, to, limit)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get payee report")
+		return
+	}
+
+	total, err := h.reportRepo.TotalByCategoryType(user.ID, currentLedgerID(c), categoryType, from, to)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get payee report")
+		return
+	}
+
+	report := models.PayeeReport{
+		From:     from,
+		To:       to,
+		Type:     categoryType,
+		Currency: ledgerBaseCurrency(user, currentLedgerMember(c)),
+		Total:    total,
+		Payees:   []models.PayeeSummary{},
+	}
+
+	for _, summary := range summaries {
+		if total != 0 {
+			summary.Share = math.Round(summary.Total.Float64()/total.Float64()*10000) / 100
+		}
+		report.Payees = append(report.Payees, summary)
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Payee report retrieved successfully", report)
+}
+
+// GET TREND REPORT
+// GetTrendReport godoc
+// @Summary Get trend report
+// @Description Get zero-filled income and expense totals per day, week, month or year in the user timezone, optionally broken down by category, at most 1000 buckets. Contributions to savings goals are left out
+// @Tags reports
+// @Accept  json
+// @Produce  json
+// @Param interval query string false "Bucket size (day, week, month, year)" default(day)
+// @Param from query string false "Start date (YYYY-MM-DD or RFC3339), defaults to the first day of the current month"
+// @Param to query string false "End date, inclusive (YYYY-MM-DD or RFC3339), defaults to the last day of the current month"
+// @Param category_id query int false "Only include this category and its subcategories"
+// @Param group_by query string false "Set to category to break buckets down by category"
+// @Param rollup query bool false "Count subcategories under their top level category" default(false)
+// @Param tz query string false "IANA timezone, defaults to the user timezone"
+// @Param X-Ledger-ID header int false "Shared ledger ID, omit for the personal data of the user"
+// @Success 200 {object} utils.Response[models.TrendReport]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /reports/trend [get]
+func (h *ReportHandler) GetTrendReport(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// VALIDATE INTERVAL
+	interval := c.DefaultQuery("interval", models.TrendIntervalDay)
+	switch interval {
+	case models.TrendIntervalDay, models.TrendIntervalWeek, models.TrendIntervalMonth, models.TrendIntervalYear:
+	default:
+		utils.ErrorResponse(c, http.StatusBadRequest, "interval must be one of day, week, month, year")
+		return
+	}
+
+	// RESOLVE TIMEZONE
+	loc := user.Location()
+	if tz := c.Query("tz"); tz != "" {
+		loc, err = loadReportTimezone(tz)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid timezone "+tz)
+			return
+		}
+	}
+
+	// PARSE REPORT PERIOD
+	from, to, err := parseReportRange(c, loc)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// LIMIT THE NUMBER OF ZERO-FILLED BUCKETS
+	if countTrendBuckets(interval, from, to, loc, maxTrendBuckets) > maxTrendBuckets {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Period covers more than "+strconv.Itoa(maxTrendBuckets)+" intervals, use a longer interval or a shorter period")
+		return
+	}
+
+	// PARSE CATEGORY FILTER
+	var categoryID uint64
+	if value := c.Query("category_id"); value != "" {
+		categoryID, err = strconv.ParseUint(value, 10, 32)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid category ID")
+			return
+		}
+	}
+
+	rollup, err := strconv.ParseBool(c.DefaultQuery("rollup", "false"))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "rollup must be true or false")
+		return
+	}
+
+	// AGGREGATE PER BUCKET AND CATEGORY
+	totals, err := h.reportRepo.TrendByCategory(user.ID, currentLedgerID(c), interval, loc.String(), from, to, uint(categoryID), rollup)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get trend report")
+		return
+	}
+
+	report := models.TrendReport{
+		Interval: interval,
+		From:     from,
+		To:       to,
+		Timezone: loc.String(),
+		Currency: ledgerBaseCurrency(user, currentLedgerMember(c)),
+		Buckets:  buildTrendBuckets(totals, interval, from, to, loc, c.Query("group_by") == "category"),
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Trend report retrieved successfully", report)
+}
+
+// NUMBER OF BUCKETS BETWEEN from AND to, COUNTING STOPS ONCE IT EXCEEDS limit
+func countTrendBuckets(interval string, from, to time.Time, loc *time.Location, limit int) int {
+	count := 0
+	for start := truncateToInterval(from.In(loc), interval); start.Before(to) && count <= limit; start = nextInterval(start, interval) {
+		count++
+	}
+
+	return count
+}
+
+// LOAD AN IANA TIMEZONE, Local IS REJECTED LIKE THE timezone VALIDATOR DOES SINCE POSTGRES DOES NOT KNOW IT
+func loadReportTimezone(tz string) (*time.Location, error) {
+	if strings.EqualFold(tz, "Local") {
+		return nil, errors.New("unknown time zone " + tz)
+	}
+
+	return time.LoadLocation(tz)
+}
+
+// BUILD ONE BUCKET PER INTERVAL BETWEEN from AND to, BUCKETS AND CATEGORIES WITHOUT EXPENSES ARE ZERO
+func buildTrendBuckets(totals []models.TrendCategoryTotal, interval string, from, to time.Time, loc *time.Location, byCategory bool) []models.TrendBucket {
+	// CATEGORIES PRESENT IN THE PERIOD, IN ORDER OF FIRST APPEARANCE
+	var categories []models.TrendCategoryTotal
+	seen := make(map[uint]bool)
+	for _, total := range totals {
+		if !seen[total.CategoryID] {
+			seen[total.CategoryID] = true
+			categories = append(categories, models.TrendCategoryTotal{
+				CategoryID:   total.CategoryID,
+				CategoryName: total.CategoryName,
+				CategoryType: total.CategoryType,
+			})
+		}
+	}
+
+	// INDEX TOTALS BY BUCKET DATE, DATABASE BUCKETS ARE WALL CLOCK TIMES IN loc
+	byBucket := make(map[string][]models.TrendCategoryTotal)
+	for _, total := range totals {
+		key := total.Bucket.Format(utils.DateLayout)
+		byBucket[key] = append(byBucket[key], total)
+	}
+
+	buckets := []models.TrendBucket{}
+	for start := truncateToInterval(from.In(loc), interval); start.Before(to); start = nextInterval(start, interval) {
+		bucket := models.TrendBucket{Start: start}
+
+		amounts := make(map[uint]models.TrendCategoryTotal)
+		for _, total := range byBucket[start.Format(utils.DateLayout)] {
+			if total.CategoryType == "income" {
+				bucket.Income += total.Total
+			} else {
+				bucket.Expense += total.Total
+			}
+			amounts[total.CategoryID] = total
+		}
+		bucket.Net = bucket.Income - bucket.Expense
+
+		if byCategory {
+			bucket.Categories = make([]models.TrendCategoryTotal, 0, len(categories))
+			for _, category := range categories {
+				if total, ok := amounts[category.CategoryID]; ok {
+					category.Count = total.Count
+					category.Total = total.Total
+				}
+				bucket.Categories = append(bucket.Categories, category)
+			}
+		}
+
+		buckets = append(buckets, bucket)
+	}
+
+	return buckets
+}
+
+// TRUNCATE LIKE POSTGRES date_trunc, WEEKS START ON MONDAY
+func truncateToInterval(t time.Time, interval string) time.Time {
+	year, month, day := t.Date()
+
+	switch interval {
+	case models.TrendIntervalWeek:
+		start := time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+		return start.AddDate(0, 0, -((int(start.Weekday()) + 6) % 7))
+	case models.TrendIntervalMonth:
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case models.TrendIntervalYear:
+		return time.Date(year, 1, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func nextInterval(t time.Time, interval string) time.Time {
+	switch interval {
+	case models.TrendIntervalWeek:
+		return t.AddDate(0, 0, 7)
+	case models.TrendIntervalMonth:
+		return t.AddDate(0, 1, 0)
+	case models.TrendIntervalYear:
+		return t.AddDate(1, 0, 0)
+	default:
+		return t.AddDate(0, 0, 1)
+	}
+}
+
+// SET EACH CATEGORY SHARE AS A PERCENTAGE OF total, ROUNDED TO 2 DECIMALS
+func setShares(summaries []models.CategorySummary, total models.Money) {
+	if total == 0 {
+		return
+	}
+	for i := range summaries {
+		summaries[i].Share = math.Round(summaries[i].Total.Float64()/total.Float64()*10000) / 100
+	}
+}
+
+// PARSE from/to QUERY PARAMS INTO A [from, to) RANGE, DEFAULTS TO THE CURRENT MONTH
+// A PLAIN to DATE INCLUDES THE WHOLE DAY
+func parseReportRange(c *gin.Context, loc *time.Location) (time.Time, time.Time, error) {
+	now := time.Now().In(loc)
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, loc)
+	to := from.AddDate(0, 1, 0)
+
+	if value := c.Query("from"); value != "" {
+		parsed, _, err := utils.ParseDateTime(value, loc)
+		if err != nil {
+			return from, to, errors.New("from: " + err.Error())
+		}
+		from = parsed
+	}
+
+	if value := c.Query("to"); value != "" {
+		parsed, dateOnly, err := utils.ParseDateTime(value, loc)
+		if err != nil {
+			return from, to, errors.New("to: " + err.Error())
+		}
+		if dateOnly {
+			parsed = parsed.AddDate(0, 0, 1)
+		}
+		to = parsed
+	}
+
+	if !to.After(from) {
+		return from, to, errors.New("to must be after from")
+	}
+
+	return from, to, nil
+}
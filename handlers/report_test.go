@@ -0,0 +1,46 @@
+package handlers
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+func TestCountTrendBuckets(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name     string
+		interval string
+		from, to time.Time
+		want     int
+	}{
+		{"days of a month", models.TrendIntervalDay, time.Date(2025, 1, 1, 0, 0, 0, 0, jakarta), time.Date(2025, 2, 1, 0, 0, 0, 0, jakarta), 31},
+		{"weeks started before from", models.TrendIntervalWeek, time.Date(2025, 1, 1, 0, 0, 0, 0, jakarta), time.Date(2025, 1, 15, 0, 0, 0, 0, jakarta), 3},
+		{"months of a year", models.TrendIntervalMonth, time.Date(2025, 1, 1, 0, 0, 0, 0, jakarta), time.Date(2026, 1, 1, 0, 0, 0, 0, jakarta), 12},
+		{"counting stops past the limit", models.TrendIntervalDay, time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(9999, 12, 31, 0, 0, 0, 0, time.UTC), maxTrendBuckets + 1},
+		{"years within the limit", models.TrendIntervalYear, time.Date(1, 1, 1, 0, 0, 0, 0, jakarta), time.Date(1000, 1, 1, 0, 0, 0, 0, jakarta), 999},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := countTrendBuckets(tt.interval, tt.from, tt.to, jakarta, maxTrendBuckets); got != tt.want {
+				t.Errorf("countTrendBuckets() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestLoadReportTimezone(t *testing.T) {
+	for _, tz := range []string{"Local", "local", "Not/AZone"} {
+		if _, err := loadReportTimezone(tz); err == nil {
+			t.Errorf("loadReportTimezone(%q) error = nil, want an error", tz)
+		}
+	}
+
+	loc, err := loadReportTimezone("Asia/Jakarta")
+	if err != nil || loc.String() != "Asia/Jakarta" {
+		t.Errorf("loadReportTimezone(Asia/Jakarta) = %v, %v", loc, err)
+	}
+}
@@ -0,0 +1,326 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type SettlementHandler struct {
+	settlementRepo *repositories.SettlementRepository
+	ledgerRepo     *repositories.LedgerRepository
+	userRepo       *repositories.UserRepository
+	ledgerService  *services.LedgerService
+	validator      *validator.Validate
+}
+
+func NewSettlementHandler(settlementRepo *repositories.SettlementRepository, ledgerRepo *repositories.LedgerRepository, userRepo *repositories.UserRepository, ledgerService *services.LedgerService) *SettlementHandler {
+	return &SettlementHandler{
+		settlementRepo: settlementRepo,
+		ledgerRepo:     ledgerRepo,
+		userRepo:       userRepo,
+		ledgerService:  ledgerService,
+		validator:      validator.New(),
+	}
+}
+
+// GET LEDGER BALANCES
+// GetLedgerBalances godoc
+// @Summary Get the balances of a ledger
+// @Description Get what every member paid and owes in the shared expenses of a ledger, in its base currency, with the fewest payments that settle all balances
+// @Tags settlements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Success 200 {object} utils.Response[models.LedgerBalances]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/balances [get]
+func (h *SettlementHandler) GetLedgerBalances(c *gin.Context) {
+	member, _, ok := h.getLedgerMember(c, false)
+	if !ok {
+		return
+	}
+
+	balances, err := h.ledgerRepo.MemberBalances(member.LedgerID)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger balances")
+		return
+	}
+
+	response := models.LedgerBalances{
+		LedgerID: member.LedgerID,
+		Currency: member.Ledger.BaseCurrency,
+		Members:  balances,
+		SettleUp: services.SimplifyDebts(balances),
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Ledger balances retrieved successfully", response)
+}
+
+// GET SETTLEMENTS BY LEDGER ID
+// GetSettlementsByLedgerID godoc
+// @Summary Get the settlements of a ledger
+// @Description Get the payments recorded between members of a ledger
+// @Tags settlements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param user_id query int false "Settlements paid or received by this member"
+// @Param from query string false "Settled on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Settled on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param note query string false "Filter by note"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Settlement]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/settlements [get]
+func (h *SettlementHandler) GetSettlementsByLedgerID(c *gin.Context) {
+	member, _, ok := h.getLedgerMember(c, false)
+	if !ok {
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET SETTLEMENTS BY LEDGER ID
+	settlements, total, totalPages, err := h.settlementRepo.GetByLedgerID(member.LedgerID, queryParams.(middleware.QueryParams))
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get settlements")
+		return
+	}
+
+	response := gin.H{
+		"data":        settlements,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Settlements retrieved successfully", response)
+}
+
+// GET SETTLEMENT BY ID
+// GetSettlementByID godoc
+// @Summary Get settlement by ID
+// @Description Get a settlement of a ledger by ID
+// @Tags settlements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param settlement_id path int true "Settlement ID"
+// @Success 200 {object} utils.Response[models.Settlement]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/settlements/{settlement_id} [get]
+func (h *SettlementHandler) GetSettlementByID(c *gin.Context) {
+	member, _, ok := h.getLedgerMember(c, false)
+	if !ok {
+		return
+	}
+
+	settlement, ok := h.getSettlementParam(c, member.LedgerID)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Settlement retrieved successfully", settlement)
+}
+
+// CREATE SETTLEMENT
+// CreateSettlement godoc
+// @Summary Record a settlement
+// @Description Record a payment from one member of a ledger to another, in the ledger base currency. It moves both balances towards zero
+// @Tags settlements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param request body models.SettlementRequest true "Settlement data"
+// @Success 201 {object} utils.Response[models.Settlement]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/settlements [post]
+func (h *SettlementHandler) CreateSettlement(c *gin.Context) {
+	member, user, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	var req models.SettlementRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// BOTH SIDES MUST BE MEMBERS OF THE LEDGER
+	for _, memberUserID := range []uint{req.FromUserID, req.ToUserID} {
+		_, err := h.ledgerService.Member(member.LedgerID, memberUserID)
+		if ledgerAccessError(c, err, http.StatusBadRequest, "User "+strconv.FormatUint(uint64(memberUserID), 10)+" is not a member of this ledger") {
+			return
+		}
+	}
+
+	// VALIDATE AMOUNT PRECISION AGAINST THE LEDGER BASE CURRENCY
+	currency := member.Ledger.BaseCurrency
+	if !req.Amount.FitsExponent(models.CurrencyExponent(currency)) {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Amount has more decimal places than "+currency+" allows")
+		return
+	}
+
+	// PARSE SETTLED AT, DEFAULTS TO NOW
+	settledAt := time.Now()
+	if req.SettledAt != "" {
+		var err error
+		settledAt, _, err = utils.ParseDateTime(req.SettledAt, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return
+		}
+	}
+
+	settlement := models.Settlement{
+		LedgerID:    member.LedgerID,
+		FromUserID:  req.FromUserID,
+		ToUserID:    req.ToUserID,
+		Amount:      req.Amount,
+		Note:        strings.TrimSpace(req.Note),
+		SettledAt:   settledAt,
+		CreatedByID: user.ID,
+	}
+
+	if err := h.settlementRepo.Create(&settlement); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create settlement")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Settlement created successfully", settlement)
+}
+
+// DELETE SETTLEMENT
+// DeleteSettlement godoc
+// @Summary Delete a settlement
+// @Description Delete a settlement recorded by mistake, its amount becomes owed again
+// @Tags settlements
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Ledger ID"
+// @Param settlement_id path int true "Settlement ID"
+// @Success 200 {object} utils.Response[models.Settlement]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 403 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /ledgers/{id}/settlements/{settlement_id} [delete]
+func (h *SettlementHandler) DeleteSettlement(c *gin.Context) {
+	member, _, ok := h.getLedgerMember(c, true)
+	if !ok {
+		return
+	}
+
+	settlement, ok := h.getSettlementParam(c, member.LedgerID)
+	if !ok {
+		return
+	}
+
+	if err := h.settlementRepo.Delete(settlement); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete settlement")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Settlement deleted successfully", settlement)
+}
+
+// GET THE MEMBERSHIP OF THE USER IN THE LEDGER OF THE id URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR NOT A MEMBER, OR IS A VIEWER AND write IS SET
+func (h *SettlementHandler) getLedgerMember(c *gin.Context, write bool) (*models.LedgerMember, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET LEDGER ID FROM URL PARAM
+	ledgerID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid ledger ID")
+		return nil, nil, false
+	}
+
+	// LEDGERS ARE ONLY VISIBLE TO THEIR MEMBERS
+	member, err := h.ledgerService.Member(uint(ledgerID), user.ID)
+	if ledgerAccessError(c, err, http.StatusNotFound, "Ledger not found") {
+		return nil, nil, false
+	}
+
+	if write && !member.CanWrite() {
+		utils.ErrorResponse(c, http.StatusForbidden, "Viewers cannot change this ledger")
+		return nil, nil, false
+	}
+
+	return member, user, true
+}
+
+// GET THE SETTLEMENT FROM THE settlement_id URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *SettlementHandler) getSettlementParam(c *gin.Context, ledgerID uint) (*models.Settlement, bool) {
+	settlementID, err := strconv.ParseUint(c.Param("settlement_id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid settlement ID")
+		return nil, false
+	}
+
+	settlement, err := h.settlementRepo.GetByID(uint(settlementID))
+	if err != nil || settlement.LedgerID != ledgerID {
+		utils.ErrorResponse(c, http.StatusNotFound, "Settlement not found")
+		return nil, false
+	}
+
+	return settlement, true
+}
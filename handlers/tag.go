@@ -0,0 +1,284 @@
+package handlers
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type TagHandler struct {
+	tagRepo   *repositories.TagRepository
+	userRepo  *repositories.UserRepository
+	validator *validator.Validate
+}
+
+func NewTagHandler(tagRepo *repositories.TagRepository, userRepo *repositories.UserRepository) *TagHandler {
+	return &TagHandler{
+		tagRepo:   tagRepo,
+		userRepo:  userRepo,
+		validator: validator.New(),
+	}
+}
+
+// GET TAGS BY USER ID
+// GetTagsByUserID godoc
+// @Summary Get tags by user ID
+// @Description Get all tags for the authenticated user
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param name query string false "Filter by tag name"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Tag]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /tags [get]
+func (h *TagHandler) GetTagsByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+
+	// GET TAGS BY USER ID
+	tags, total, totalPages, err := h.tagRepo.GetByUserID(user.ID, queryParams.(middleware.QueryParams))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get tags")
+		return
+	}
+
+	response := gin.H{
+		"data":        tags,
+		"total":       total,
+		"page":        queryParams.(middleware.QueryParams).Page,
+		"limit":       queryParams.(middleware.QueryParams).Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tags retrieved successfully", response)
+}
+
+// GET TAG BY ID
+// GetTagByID godoc
+// @Summary Get tag by ID
+// @Description Get a tag by ID for the authenticated user
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} utils.Response[models.Tag]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /tags/{id} [get]
+func (h *TagHandler) GetTagByID(c *gin.Context) {
+	tag, _, ok := h.getOwnedTag(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag retrieved successfully", tag)
+}
+
+// CREATE TAG
+// CreateTag godoc
+// @Summary Create a tag
+// @Description Create a tag for the authenticated user, names are stored lowercase
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param request body models.TagRequest true "Tag data"
+// @Success 201 {object} utils.Response[models.Tag]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /tags [post]
+func (h *TagHandler) CreateTag(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	tag := &models.Tag{
+		UserID: user.ID,
+	}
+
+	if !h.bindTag(c, tag) {
+		return
+	}
+
+	if err := h.tagRepo.Create(tag); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create tag")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Tag created successfully", tag)
+}
+
+// UPDATE TAG
+// UpdateTag godoc
+// @Summary Update a tag
+// @Description Rename a tag, expenses keep it under the new name
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Param request body models.TagRequest true "Tag data"
+// @Success 200 {object} utils.Response[models.Tag]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 409 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /tags/{id} [put]
+func (h *TagHandler) UpdateTag(c *gin.Context) {
+	tag, _, ok := h.getOwnedTag(c)
+	if !ok {
+		return
+	}
+
+	if !h.bindTag(c, tag) {
+		return
+	}
+
+	if err := h.tagRepo.Update(tag); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update tag")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag updated successfully", tag)
+}
+
+// DELETE TAG
+// DeleteTag godoc
+// @Summary Delete a tag
+// @Description Delete a tag and remove it from all expenses, the expenses are kept
+// @Tags tags
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Tag ID"
+// @Success 200 {object} utils.Response[models.Tag]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /tags/{id} [delete]
+func (h *TagHandler) DeleteTag(c *gin.Context) {
+	tag, _, ok := h.getOwnedTag(c)
+	if !ok {
+		return
+	}
+
+	if err := h.tagRepo.Delete(tag); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete tag")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Tag deleted successfully", tag)
+}
+
+// GET THE TAG FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *TagHandler) getOwnedTag(c *gin.Context) (*models.Tag, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET TAG ID FROM URL PARAM
+	tagID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid tag ID")
+		return nil, nil, false
+	}
+
+	// GET TAG BY ID
+	tag, err := h.tagRepo.GetByID(uint(tagID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Tag not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF TAG BELONGS TO USER
+	if tag.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Tag does not belong to this user")
+		return nil, nil, false
+	}
+
+	return tag, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO tag, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *TagHandler) bindTag(c *gin.Context, tag *models.Tag) bool {
+	var req models.TagRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	name := models.NormalizeTagName(req.Name)
+	if name == "" {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Tag name is required")
+		return false
+	}
+
+	// TAG NAMES ARE UNIQUE PER USER
+	if existing, err := h.tagRepo.GetByName(tag.UserID, name); err == nil && existing.ID != tag.ID {
+		utils.ErrorResponse(c, http.StatusConflict, "Tag already exists")
+		return false
+	}
+
+	tag.Name = name
+
+	return true
+}
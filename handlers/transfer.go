@@ -0,0 +1,345 @@
+package handlers
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/utils"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+)
+
+type TransferHandler struct {
+	transferRepo *repositories.TransferRepository
+	accountRepo  *repositories.AccountRepository
+	userRepo     *repositories.UserRepository
+	validator    *validator.Validate
+}
+
+func NewTransferHandler(transferRepo *repositories.TransferRepository, accountRepo *repositories.AccountRepository, userRepo *repositories.UserRepository) *TransferHandler {
+	return &TransferHandler{
+		transferRepo: transferRepo,
+		accountRepo:  accountRepo,
+		userRepo:     userRepo,
+		validator:    validator.New(),
+	}
+}
+
+// GET TRANSFERS BY USER ID
+// GetTransfersByUserID godoc
+// @Summary Get transfers by user ID
+// @Description Get all transfers between accounts of the authenticated user
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Param page query int false "Page number" default(1)
+// @Param limit query int false "Number of items per page" default(10)
+// @Param sortBy query string false "Sort by field" default(id)
+// @Param order query string false "Sort order (asc or desc)" default(asc)
+// @Param account_id query int false "Transfers from or to this account"
+// @Param from query string false "Transferred on or after this date (YYYY-MM-DD or RFC3339)"
+// @Param to query string false "Transferred on or before this date (YYYY-MM-DD or RFC3339)"
+// @Param note query string false "Filter by note"
+// @Success 200 {object} utils.ResponseWithPagination[[]models.Transfer]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /transfers [get]
+func (h *TransferHandler) GetTransfersByUserID(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	// GET QUERY PARAMETERS
+	queryParams, _ := c.Get("queryParams")
+	params := queryParams.(middleware.QueryParams)
+	params.Location = user.Location()
+
+	// GET TRANSFERS BY USER ID
+	transfers, total, totalPages, err := h.transferRepo.GetByUserID(user.ID, params)
+	if errors.Is(err, repositories.ErrInvalidFilter) {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get transfers")
+		return
+	}
+
+	response := gin.H{
+		"data":        transfers,
+		"total":       total,
+		"page":        params.Page,
+		"limit":       params.Limit,
+		"total_pages": totalPages,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Transfers retrieved successfully", response)
+}
+
+// GET TRANSFER BY ID
+// GetTransferByID godoc
+// @Summary Get transfer by ID
+// @Description Get a transfer by ID for the authenticated user
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} utils.Response[models.Transfer]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /transfers/{id} [get]
+func (h *TransferHandler) GetTransferByID(c *gin.Context) {
+	transfer, _, ok := h.getOwnedTransfer(c)
+	if !ok {
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Transfer retrieved successfully", transfer)
+}
+
+// CREATE TRANSFER
+// CreateTransfer godoc
+// @Summary Create a transfer
+// @Description Move money between two accounts of the authenticated user. The amount and fee are in the source account currency and to_amount in the destination currency; it is required when the currencies differ. Transfers change account balances but are not counted in category reports or budgets
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Param request body models.TransferRequest true "Transfer data"
+// @Success 201 {object} utils.Response[models.Transfer]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /transfers [post]
+func (h *TransferHandler) CreateTransfer(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	transfer := &models.Transfer{
+		UserID:        user.ID,
+		TransferredAt: time.Now(),
+	}
+
+	if !h.bindTransfer(c, user, transfer) {
+		return
+	}
+
+	if err := h.transferRepo.Create(transfer); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to create transfer")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusCreated, "Transfer created successfully", transfer)
+}
+
+// UPDATE TRANSFER
+// UpdateTransfer godoc
+// @Summary Update a transfer
+// @Description Update a transfer of the authenticated user, the transfer date is kept when omitted
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Transfer ID"
+// @Param request body models.TransferRequest true "Transfer data"
+// @Success 200 {object} utils.Response[models.Transfer]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /transfers/{id} [put]
+func (h *TransferHandler) UpdateTransfer(c *gin.Context) {
+	transfer, user, ok := h.getOwnedTransfer(c)
+	if !ok {
+		return
+	}
+
+	if !h.bindTransfer(c, user, transfer) {
+		return
+	}
+
+	if err := h.transferRepo.Update(transfer); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update transfer")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Transfer updated successfully", transfer)
+}
+
+// DELETE TRANSFER
+// DeleteTransfer godoc
+// @Summary Delete a transfer
+// @Description Delete a transfer of the authenticated user, the account balances no longer include it
+// @Tags transfers
+// @Accept  json
+// @Produce  json
+// @Param id path int true "Transfer ID"
+// @Success 200 {object} utils.Response[models.Transfer]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 404 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /transfers/{id} [delete]
+func (h *TransferHandler) DeleteTransfer(c *gin.Context) {
+	transfer, _, ok := h.getOwnedTransfer(c)
+	if !ok {
+		return
+	}
+
+	if err := h.transferRepo.Delete(transfer); err != nil {
+		utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to delete transfer")
+		return
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "Transfer deleted successfully", transfer)
+}
+
+// GET THE TRANSFER FROM THE URL PARAM, WRITES AN ERROR RESPONSE AND RETURNS FALSE
+// WHEN THE USER IS NOT AUTHENTICATED OR DOES NOT OWN IT
+func (h *TransferHandler) getOwnedTransfer(c *gin.Context) (*models.Transfer, *models.User, bool) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return nil, nil, false
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return nil, nil, false
+	}
+
+	// GET TRANSFER ID FROM URL PARAM
+	transferID, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid transfer ID")
+		return nil, nil, false
+	}
+
+	// GET TRANSFER BY ID
+	transfer, err := h.transferRepo.GetByID(uint(transferID))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusNotFound, "Transfer not found")
+		return nil, nil, false
+	}
+
+	// CHECK IF TRANSFER BELONGS TO USER
+	if transfer.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Transfer does not belong to this user")
+		return nil, nil, false
+	}
+
+	return transfer, user, true
+}
+
+// BIND AND VALIDATE THE REQUEST BODY INTO transfer, WRITES AN ERROR RESPONSE AND RETURNS FALSE ON FAILURE
+func (h *TransferHandler) bindTransfer(c *gin.Context, user *models.User, transfer *models.Transfer) bool {
+	var req models.TransferRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid request body")
+		return false
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	// VALIDATE ACCOUNTS BELONGING TO USER
+	fromAccount, err := h.accountRepo.GetByID(req.FromAccountID)
+	if err != nil || fromAccount.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid source account ID")
+		return false
+	}
+
+	toAccount, err := h.accountRepo.GetByID(req.ToAccountID)
+	if err != nil || toAccount.UserID != user.ID {
+		utils.ErrorResponse(c, http.StatusBadRequest, "Invalid destination account ID")
+		return false
+	}
+
+	toAmount, err := transferToAmount(&req, fromAccount, toAccount)
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return false
+	}
+
+	// PARSE TRANSFERRED AT, KEEP THE CURRENT VALUE WHEN OMITTED
+	if req.TransferredAt != "" {
+		transfer.TransferredAt, _, err = utils.ParseDateTime(req.TransferredAt, user.Location())
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+			return false
+		}
+	}
+
+	transfer.FromAccountID = fromAccount.ID
+	transfer.FromAccount = *fromAccount
+	transfer.ToAccountID = toAccount.ID
+	transfer.ToAccount = *toAccount
+	transfer.Amount = req.Amount
+	transfer.ToAmount = toAmount
+	transfer.Fee = req.Fee
+	transfer.Note = strings.TrimSpace(req.Note)
+
+	return true
+}
+
+// THE AMOUNT CREDITED TO toAccount, ONLY NEEDED IN THE REQUEST ACROSS CURRENCIES
+// AMOUNTS ARE CHECKED AGAINST THE MINOR UNITS OF THEIR ACCOUNT CURRENCY
+func transferToAmount(req *models.TransferRequest, fromAccount, toAccount *models.Account) (models.Money, error) {
+	toAmount := req.ToAmount
+	if fromAccount.Currency == toAccount.Currency {
+		if toAmount != 0 && toAmount != req.Amount {
+			return 0, errors.New("to_amount must equal amount between accounts in the same currency")
+		}
+		toAmount = req.Amount
+	} else if toAmount == 0 {
+		return 0, errors.New("to_amount is required between " + fromAccount.Currency + " and " + toAccount.Currency + " accounts")
+	}
+
+	fromExponent := models.CurrencyExponent(fromAccount.Currency)
+	if !req.Amount.FitsExponent(fromExponent) || !req.Fee.FitsExponent(fromExponent) {
+		return 0, errors.New("Amount has more decimal places than " + fromAccount.Currency + " allows")
+	}
+	if !toAmount.FitsExponent(models.CurrencyExponent(toAccount.Currency)) {
+		return 0, errors.New("to_amount has more decimal places than " + toAccount.Currency + " allows")
+	}
+
+	return toAmount, nil
+}
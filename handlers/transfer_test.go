@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"testing"
+
+	"go-expense-tracker-api/models"
+)
+
+func TestTransferToAmount(t *testing.T) {
+	money := func(value string) models.Money {
+		m, err := models.ParseMoney(value)
+		if err != nil {
+			t.Fatalf("ParseMoney(%q): %v", value, err)
+		}
+		return m
+	}
+
+	eur := &models.Account{ID: 1, Currency: "EUR"}
+	savings := &models.Account{ID: 2, Currency: "EUR"}
+	idr := &models.Account{ID: 3, Currency: "IDR"}
+	jpy := &models.Account{ID: 4, Currency: "JPY"}
+
+	tests := []struct {
+		name    string
+		req     models.TransferRequest
+		from    *models.Account
+		to      *models.Account
+		want    string
+		wantErr string
+	}{
+		{name: "same currency defaults to amount", req: models.TransferRequest{Amount: money("100")}, from: eur, to: savings, want: "100"},
+		{name: "same currency with equal to_amount", req: models.TransferRequest{Amount: money("100"), ToAmount: money("100")}, from: eur, to: savings, want: "100"},
+		{name: "same currency with another to_amount", req: models.TransferRequest{Amount: money("100"), ToAmount: money("99")}, from: eur, to: savings, wantErr: "to_amount must equal amount between accounts in the same currency"},
+		{name: "cross currency", req: models.TransferRequest{Amount: money("100"), ToAmount: money("1580000")}, from: eur, to: idr, want: "1580000"},
+		{name: "cross currency without to_amount", req: models.TransferRequest{Amount: money("100")}, from: eur, to: idr, wantErr: "to_amount is required between EUR and IDR accounts"},
+		{name: "amount precision", req: models.TransferRequest{Amount: money("100.123")}, from: eur, to: savings, wantErr: "Amount has more decimal places than EUR allows"},
+		{name: "fee precision", req: models.TransferRequest{Amount: money("100"), Fee: money("0.005")}, from: eur, to: savings, wantErr: "Amount has more decimal places than EUR allows"},
+		{name: "to_amount precision", req: models.TransferRequest{Amount: money("10"), ToAmount: money("1500.5")}, from: eur, to: jpy, wantErr: "to_amount has more decimal places than JPY allows"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := transferToAmount(&tt.req, tt.from, tt.to)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Errorf("transferToAmount() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("transferToAmount() error = %v", err)
+			}
+			if got != money(tt.want) {
+				t.Errorf("transferToAmount() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
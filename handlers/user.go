@@ -1,20 +1,30 @@
 package handlers
 
 import (
+	"errors"
+	"go-expense-tracker-api/models"
 	"go-expense-tracker-api/repositories"
+	"go-expense-tracker-api/services"
 	"go-expense-tracker-api/utils"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
 )
 
 type UserHandler struct {
-	userRepo *repositories.UserRepository
+	userRepo            *repositories.UserRepository
+	expenseRepo         *repositories.ExpenseRepository
+	exchangeRateService *services.ExchangeRateService
+	validator           *validator.Validate
 }
 
-func NewUserHandler(userRepo *repositories.UserRepository) *UserHandler {
+func NewUserHandler(userRepo *repositories.UserRepository, expenseRepo *repositories.ExpenseRepository, exchangeRateService *services.ExchangeRateService) *UserHandler {
 	return &UserHandler{
-		userRepo: userRepo,
+		userRepo:            userRepo,
+		expenseRepo:         expenseRepo,
+		exchangeRateService: exchangeRateService,
+		validator:           validator.New(),
 	}
 }
 
@@ -48,3 +58,97 @@ func (h *UserHandler) GetUserProfile(c *gin.Context) {
 
 	utils.SuccessResponse(c, http.StatusOK, "User profile retrieved successfully", user)
 }
+
+// UPDATE USER SETTINGS
+// UpdateUserSettings godoc
+// @Summary Update user settings
+// @Description Update the base currency and timezone of the authenticated user, base amounts of all expenses are recalculated when the base currency changes
+// @Tags users
+// @Accept  json
+// @Produce  json
+// @Param request body models.UserSettingsRequest true "User settings"
+// @Success 200 {object} utils.Response[models.UserResponse]
+// @Failure 400 {object} utils.Response[any]
+// @Failure 401 {object} utils.Response[any]
+// @Failure 500 {object} utils.Response[any]
+// @Security BearerAuth
+// @Router /user/settings [put]
+func (h *UserHandler) UpdateUserSettings(c *gin.Context) {
+	// GET USER ID FROM CONTEXT
+	userID, exists := c.Get("user_id")
+	if !exists {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+		return
+	}
+
+	// VALIDATE USER ID
+	user, err := h.userRepo.GetByID(userID.(uint))
+	if err != nil {
+		utils.ErrorResponse(c, http.StatusUnauthorized, "Invalid user ID")
+		return
+	}
+
+	var req models.UserSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	// INPUT VALIDATION
+	if err := h.validator.Struct(req); err != nil {
+		utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+		return
+	}
+
+	baseCurrency := models.NormalizeCurrency(req.BaseCurrency)
+
+	if baseCurrency != "" && baseCurrency != user.BaseCurrency {
+		// RECALCULATE BASE AMOUNTS IN THE NEW BASE CURRENCY
+		expenses, err := h.expenseRepo.GetAllByUserID(user.ID)
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get expenses")
+			return
+		}
+
+		baseAmounts := make(map[uint]models.Money, len(expenses))
+		for _, expense := range expenses {
+			baseAmount, err := h.exchangeRateService.Convert(user.ID, expense.Amount, expense.Currency, baseCurrency, expense.SpentAt)
+			if errors.Is(err, services.ErrExchangeRateNotFound) {
+				utils.ErrorResponse(c, http.StatusBadRequest, err.Error())
+				return
+			}
+			if err != nil {
+				utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to convert expenses to base currency")
+				return
+			}
+			baseAmounts[expense.ID] = baseAmount
+		}
+
+		// SAVE BASE CURRENCY AND BASE AMOUNTS
+		if err := h.expenseRepo.RebaseAmounts(user.ID, baseCurrency, baseAmounts); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update user settings")
+			return
+		}
+		user.BaseCurrency = baseCurrency
+	}
+
+	// SAVE TIMEZONE
+	if req.Timezone != "" && req.Timezone != user.Timezone {
+		if err := h.userRepo.UpdateSettings(user.ID, map[string]any{"timezone": req.Timezone}); err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to update user settings")
+			return
+		}
+		user.Timezone = req.Timezone
+	}
+
+	response := models.UserResponse{
+		ID:           user.ID,
+		Email:        user.Email,
+		Name:         user.Name,
+		BaseCurrency: user.BaseCurrency,
+		Timezone:     user.Timezone,
+		CreatedAt:    user.CreatedAt,
+	}
+
+	utils.SuccessResponse(c, http.StatusOK, "User settings updated successfully", response)
+}
@@ -1,9 +1,11 @@
 package main
 
 import (
+	"context"
 	"log"
 	"net/http"
 	"time"
+	_ "time/tzdata"
 
 	"go-expense-tracker-api/config"
 	"go-expense-tracker-api/database"
@@ -65,34 +67,124 @@ func setupRouter(cfg *config.Config) *gin.Engine {
 		// AllowOrigins: []string{"http://localhost:3000", "https://your-production-domain.com"},
 		AllowAllOrigins:  true, // for development only
 		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
-		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Authorization", middleware.LedgerHeader},
 		ExposeHeaders:    []string{"Content-Length"},
 		AllowCredentials: true,
 		MaxAge:           12 * time.Hour,
 	}))
 
-	// INIT SERVICES
-	jwtServices := services.NewJWTService(cfg)
-
 	// INIT REPOSITORIES
 	userRepo := repositories.NewUserRepository(database.DB)
 	categoryRepo := repositories.NewCategoryRepository(database.DB)
 	expenseRepo := repositories.NewExpenseRepository(database.DB)
 	refreshTokenRepo := repositories.NewRefreshTokenRepository(database.DB)
+	exchangeRateRepo := repositories.NewExchangeRateRepository(database.DB)
+	recurringExpenseRepo := repositories.NewRecurringExpenseRepository(database.DB)
+	budgetRepo := repositories.NewBudgetRepository(database.DB)
+	budgetAlertRepo := repositories.NewBudgetAlertRepository(database.DB)
+	notificationRepo := repositories.NewNotificationRepository(database.DB)
+	webhookDeliveryRepo := repositories.NewWebhookDeliveryRepository(database.DB)
+	reportRepo := repositories.NewReportRepository(database.DB)
+	tagRepo := repositories.NewTagRepository(database.DB)
+	payeeRepo := repositories.NewPayeeRepository(database.DB)
+	attachmentRepo := repositories.NewAttachmentRepository(database.DB)
+	accountRepo := repositories.NewAccountRepository(database.DB)
+	transferRepo := repositories.NewTransferRepository(database.DB)
+	ledgerRepo := repositories.NewLedgerRepository(database.DB)
+	goalRepo := repositories.NewGoalRepository(database.DB)
+	settlementRepo := repositories.NewSettlementRepository(database.DB)
+	categorizationRuleRepo := repositories.NewCategorizationRuleRepository(database.DB)
+
+	// INIT SERVICES
+	jwtServices := services.NewJWTService(cfg)
+	exchangeRateServices := services.NewExchangeRateService(exchangeRateRepo)
+	payeeServices := services.NewPayeeService(payeeRepo)
+	ledgerServices := services.NewLedgerService(ledgerRepo)
+	budgetServices := services.NewBudgetService(expenseRepo, exchangeRateServices)
+	goalServices := services.NewGoalService(expenseRepo, exchangeRateServices)
+	categorizationServices := services.NewCategorizationService(categorizationRuleRepo, expenseRepo)
+
+	// BUDGET ALERTS ARE ALWAYS STORED IN-APP, AND POSTED TO A WEBHOOK WHEN CONFIGURED
+	notifiers := []services.Notifier{services.NewInAppNotifier(notificationRepo)}
+	var webhookNotifier *services.WebhookNotifier
+	if cfg.Alert.WebhookURL != "" {
+		webhookNotifier = services.NewWebhookNotifier(cfg.Alert.WebhookURL, cfg.Alert.WebhookSecret, webhookDeliveryRepo, time.Duration(cfg.Scheduler.IntervalMinutes)*time.Minute)
+		notifiers = append(notifiers, webhookNotifier)
+	}
+	budgetAlertServices := services.NewBudgetAlertService(budgetRepo, budgetAlertRepo, budgetServices, notifiers...)
+	expenseImportServices := services.NewExpenseImportService(expenseRepo, categoryRepo, exchangeRateServices, payeeServices, categorizationServices)
+
+	// ATTACHMENTS ARE STORED ON THE LOCAL DISK OR IN AN S3 COMPATIBLE BUCKET
+	var storage services.Storage
+	switch cfg.Storage.Driver {
+	case "s3":
+		s3Storage, err := services.NewS3Storage(cfg.Storage.S3Endpoint, cfg.Storage.S3Region, cfg.Storage.S3Bucket, cfg.Storage.S3AccessKey, cfg.Storage.S3SecretKey, cfg.Storage.S3PathStyle)
+		if err != nil {
+			log.Fatal("Failed to init S3 storage:", err)
+		}
+		storage = s3Storage
+	case "local":
+		localStorage, err := services.NewLocalStorage(cfg.Storage.LocalPath)
+		if err != nil {
+			log.Fatal("Failed to init local storage:", err)
+		}
+		storage = localStorage
+	default:
+		log.Fatalf("Unknown storage driver %s", cfg.Storage.Driver)
+	}
+	attachmentServices := services.NewAttachmentService(attachmentRepo, storage, int64(cfg.Storage.MaxUploadMB)<<20)
+	trashServices := services.NewTrashService(expenseRepo, attachmentServices, time.Duration(cfg.Trash.RetentionDays)*24*time.Hour, time.Duration(cfg.Scheduler.IntervalMinutes)*time.Minute)
+
+	// LOAD SHARED EXCHANGE RATES
+	if cfg.Currency.ExchangeRatesFile != "" {
+		imported, err := exchangeRateServices.LoadFile(cfg.Currency.ExchangeRatesFile)
+		if err != nil {
+			log.Println("Warning: failed to load exchange rates:", err)
+		} else {
+			log.Printf("Loaded %d exchange rates from %s", imported, cfg.Currency.ExchangeRatesFile)
+		}
+	}
+
+	// START RECURRING EXPENSE SCHEDULER
+	recurringScheduler := services.NewRecurringScheduler(recurringExpenseRepo, userRepo, exchangeRateServices, time.Duration(cfg.Scheduler.IntervalMinutes)*time.Minute)
+	go recurringScheduler.Start(context.Background())
+
+	// START PURGING EXPIRED TRASH
+	go trashServices.Start(context.Background())
+
+	// START RETRYING FAILED WEBHOOK DELIVERIES
+	if webhookNotifier != nil {
+		go webhookNotifier.Start(context.Background())
+	}
 
 	// INIT HANDLERS
 	authHandler := handlers.NewAuthHandler(userRepo, categoryRepo, refreshTokenRepo, jwtServices)
-	userHandler := handlers.NewUserHandler(userRepo)
-	categoryHandler := handlers.NewCategoryHandler(categoryRepo, userRepo)
-	expenseHandler := handlers.NewExpenseHandler(expenseRepo, userRepo, categoryRepo)
+	userHandler := handlers.NewUserHandler(userRepo, expenseRepo, exchangeRateServices)
+	categoryHandler := handlers.NewCategoryHandler(categoryRepo, userRepo, ledgerServices)
+	expenseHandler := handlers.NewExpenseHandler(expenseRepo, userRepo, categoryRepo, tagRepo, payeeRepo, accountRepo, goalRepo, exchangeRateServices, payeeServices, budgetAlertServices, trashServices, ledgerServices, categorizationServices)
+	expenseImportHandler := handlers.NewExpenseImportHandler(userRepo, expenseImportServices, budgetAlertServices)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(exchangeRateRepo, userRepo, exchangeRateServices)
+	recurringExpenseHandler := handlers.NewRecurringExpenseHandler(recurringExpenseRepo, userRepo, categoryRepo)
+	budgetHandler := handlers.NewBudgetHandler(budgetRepo, userRepo, categoryRepo, budgetServices)
+	notificationHandler := handlers.NewNotificationHandler(notificationRepo, userRepo)
+	reportHandler := handlers.NewReportHandler(reportRepo, userRepo)
+	tagHandler := handlers.NewTagHandler(tagRepo, userRepo)
+	payeeHandler := handlers.NewPayeeHandler(payeeRepo, userRepo)
+	attachmentHandler := handlers.NewAttachmentHandler(attachmentRepo, expenseRepo, userRepo, attachmentServices, ledgerServices)
+	accountHandler := handlers.NewAccountHandler(accountRepo, userRepo)
+	transferHandler := handlers.NewTransferHandler(transferRepo, accountRepo, userRepo)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerRepo, userRepo, ledgerServices)
+	settlementHandler := handlers.NewSettlementHandler(settlementRepo, ledgerRepo, userRepo, ledgerServices)
+	goalHandler := handlers.NewGoalHandler(goalRepo, userRepo, accountRepo, goalServices)
+	categorizationRuleHandler := handlers.NewCategorizationRuleHandler(categorizationRuleRepo, userRepo, categoryRepo, payeeRepo, tagRepo, categorizationServices, budgetAlertServices)
 
 	// SETUP ROUTES
-	setupRoutes(router, authHandler, userHandler, categoryHandler, expenseHandler, jwtServices)
+	setupRoutes(router, authHandler, userHandler, categoryHandler, expenseHandler, expenseImportHandler, exchangeRateHandler, recurringExpenseHandler, budgetHandler, notificationHandler, reportHandler, tagHandler, payeeHandler, attachmentHandler, accountHandler, transferHandler, ledgerHandler, settlementHandler, goalHandler, categorizationRuleHandler, jwtServices, ledgerServices)
 
 	return router
 }
 
-func setupRoutes(router *gin.Engine, authHandler *handlers.AuthHandler, userHandler *handlers.UserHandler, categoryHandler *handlers.CategoryHandler, expenseHandler *handlers.ExpenseHandler, jwtService *services.JWTService) {
+func setupRoutes(router *gin.Engine, authHandler *handlers.AuthHandler, userHandler *handlers.UserHandler, categoryHandler *handlers.CategoryHandler, expenseHandler *handlers.ExpenseHandler, expenseImportHandler *handlers.ExpenseImportHandler, exchangeRateHandler *handlers.ExchangeRateHandler, recurringExpenseHandler *handlers.RecurringExpenseHandler, budgetHandler *handlers.BudgetHandler, notificationHandler *handlers.NotificationHandler, reportHandler *handlers.ReportHandler, tagHandler *handlers.TagHandler, payeeHandler *handlers.PayeeHandler, attachmentHandler *handlers.AttachmentHandler, accountHandler *handlers.AccountHandler, transferHandler *handlers.TransferHandler, ledgerHandler *handlers.LedgerHandler, settlementHandler *handlers.SettlementHandler, goalHandler *handlers.GoalHandler, categorizationRuleHandler *handlers.CategorizationRuleHandler, jwtService *services.JWTService, ledgerService *services.LedgerService) {
 	// HEALTH CHECK
 	router.GET("/health", func(c *gin.Context) {
 		c.JSON(http.StatusOK, gin.H{"status": "OK", "message": "Expense Tracker API is running!"})
@@ -117,10 +209,12 @@ func setupRoutes(router *gin.Engine, authHandler *handlers.AuthHandler, userHand
 	protected := v1.Group("/")
 	protected.Use(middleware.AuthMiddleware((jwtService)))
 	protected.Use(middleware.PaginationAndFilter())
+	protected.Use(middleware.LedgerContext(ledgerService))
 	{
 		// USER ROUTES
 		user := protected.Group("/user")
 		user.GET("/profile", userHandler.GetUserProfile)
+		user.PUT("/settings", userHandler.UpdateUserSettings)
 
 		// CATEGORY ROUTES
 		category := protected.Group("/categories")
@@ -130,14 +224,137 @@ func setupRoutes(router *gin.Engine, authHandler *handlers.AuthHandler, userHand
 		category.POST("/", categoryHandler.CreateCategory)
 		category.POST("/multiple", categoryHandler.CreateMultipleCategories)
 		category.PUT("/:id", categoryHandler.UpdateCategory)
+		category.POST("/:id/merge", categoryHandler.MergeCategory)
+		category.POST("/:id/clone", categoryHandler.CloneCategory)
+		category.PUT("/:id/override", categoryHandler.SetCategoryOverride)
+		category.DELETE("/:id/override", categoryHandler.DeleteCategoryOverride)
 		category.DELETE("/:id", categoryHandler.DeleteCategory)
 
 		// EXPENSE ROUTES
 		expense := protected.Group("/expenses")
 		expense.GET("/", expenseHandler.GetExpensesByUserID)
+		expense.GET("/export.csv", expenseHandler.ExportExpenses)
+		expense.POST("/import", middleware.PersonalOnly(), expenseImportHandler.ImportExpenses)
+		expense.GET("/trash", expenseHandler.GetTrashedExpenses)
+		expense.DELETE("/trash", expenseHandler.EmptyTrash)
+		expense.DELETE("/trash/:id", expenseHandler.PurgeExpense)
 		expense.GET("/:id", expenseHandler.GetExpenseByID)
 		expense.POST("/", expenseHandler.CreateExpense)
 		expense.PUT("/:id", expenseHandler.UpdateExpense)
 		expense.DELETE("/:id", expenseHandler.DeleteExpense)
+		expense.POST("/:id/restore", expenseHandler.RestoreExpense)
+		expense.GET("/:id/attachments", attachmentHandler.GetAttachmentsByExpenseID)
+		expense.POST("/:id/attachments", attachmentHandler.UploadAttachment)
+		expense.GET("/:id/attachments/:attachment_id", attachmentHandler.DownloadAttachment)
+		expense.DELETE("/:id/attachments/:attachment_id", attachmentHandler.DeleteAttachment)
+
+		// EXCHANGE RATE ROUTES
+		exchangeRate := protected.Group("/exchange-rates", middleware.PersonalOnly())
+		exchangeRate.GET("/", exchangeRateHandler.GetExchangeRates)
+		exchangeRate.POST("/import", exchangeRateHandler.ImportExchangeRates)
+
+		// RECURRING EXPENSE ROUTES
+		recurring := protected.Group("/recurring", middleware.PersonalOnly())
+		recurring.GET("/", recurringExpenseHandler.GetRecurringExpensesByUserID)
+		recurring.GET("/:id", recurringExpenseHandler.GetRecurringExpenseByID)
+		recurring.POST("/", recurringExpenseHandler.CreateRecurringExpense)
+		recurring.PUT("/:id", recurringExpenseHandler.UpdateRecurringExpense)
+		recurring.DELETE("/:id", recurringExpenseHandler.DeleteRecurringExpense)
+
+		// BUDGET ROUTES
+		budget := protected.Group("/budgets", middleware.PersonalOnly())
+		budget.GET("/", budgetHandler.GetBudgetsByUserID)
+		budget.GET("/status", budgetHandler.GetBudgetsStatus)
+		budget.GET("/:id", budgetHandler.GetBudgetByID)
+		budget.POST("/", budgetHandler.CreateBudget)
+		budget.PUT("/:id", budgetHandler.UpdateBudget)
+		budget.DELETE("/:id", budgetHandler.DeleteBudget)
+
+		// GOAL ROUTES
+		goal := protected.Group("/goals", middleware.PersonalOnly())
+		goal.GET("/", goalHandler.GetGoalsByUserID)
+		goal.GET("/:id", goalHandler.GetGoalByID)
+		goal.GET("/:id/progress", goalHandler.GetGoalProgress)
+		goal.POST("/", goalHandler.CreateGoal)
+		goal.PUT("/:id", goalHandler.UpdateGoal)
+		goal.DELETE("/:id", goalHandler.DeleteGoal)
+
+		// NOTIFICATION ROUTES
+		notification := protected.Group("/notifications", middleware.PersonalOnly())
+		notification.GET("/", notificationHandler.GetNotificationsByUserID)
+		notification.PUT("/read-all", notificationHandler.MarkAllNotificationsAsRead)
+		notification.PUT("/:id/read", notificationHandler.MarkNotificationAsRead)
+
+		// REPORT ROUTES
+		report := protected.Group("/reports")
+		report.GET("/summary", reportHandler.GetSummaryReport)
+		report.GET("/trend", reportHandler.GetTrendReport)
+		report.GET("/tags", reportHandler.GetTagReport)
+		report.GET("/payees", reportHandler.GetPayeeReport)
+
+		// TAG ROUTES
+		tag := protected.Group("/tags", middleware.PersonalOnly())
+		tag.GET("/", tagHandler.GetTagsByUserID)
+		tag.GET("/:id", tagHandler.GetTagByID)
+		tag.POST("/", tagHandler.CreateTag)
+		tag.PUT("/:id", tagHandler.UpdateTag)
+		tag.DELETE("/:id", tagHandler.DeleteTag)
+
+		// PAYEE ROUTES
+		payee := protected.Group("/payees", middleware.PersonalOnly())
+		payee.GET("/", payeeHandler.GetPayeesByUserID)
+		payee.GET("/:id", payeeHandler.GetPayeeByID)
+		payee.POST("/", payeeHandler.CreatePayee)
+		payee.PUT("/:id", payeeHandler.UpdatePayee)
+		payee.DELETE("/:id", payeeHandler.DeletePayee)
+
+		// CATEGORIZATION RULE ROUTES
+		categorizationRule := protected.Group("/categorization-rules", middleware.PersonalOnly())
+		categorizationRule.GET("/", categorizationRuleHandler.GetCategorizationRulesByUserID)
+		categorizationRule.POST("/preview", categorizationRuleHandler.PreviewCategorizationRule)
+		categorizationRule.POST("/apply", categorizationRuleHandler.ApplyCategorizationRules)
+		categorizationRule.GET("/:id", categorizationRuleHandler.GetCategorizationRuleByID)
+		categorizationRule.POST("/", categorizationRuleHandler.CreateCategorizationRule)
+		categorizationRule.PUT("/:id", categorizationRuleHandler.UpdateCategorizationRule)
+		categorizationRule.DELETE("/:id", categorizationRuleHandler.DeleteCategorizationRule)
+
+		// ACCOUNT ROUTES
+		account := protected.Group("/accounts", middleware.PersonalOnly())
+		account.GET("/", accountHandler.GetAccountsByUserID)
+		account.GET("/balances", accountHandler.GetAccountBalances)
+		account.GET("/:id", accountHandler.GetAccountByID)
+		account.GET("/:id/balance", accountHandler.GetAccountBalance)
+		account.POST("/", accountHandler.CreateAccount)
+		account.PUT("/:id", accountHandler.UpdateAccount)
+		account.DELETE("/:id", accountHandler.DeleteAccount)
+
+		// TRANSFER ROUTES
+		transfer := protected.Group("/transfers", middleware.PersonalOnly())
+		transfer.GET("/", transferHandler.GetTransfersByUserID)
+		transfer.GET("/:id", transferHandler.GetTransferByID)
+		transfer.POST("/", transferHandler.CreateTransfer)
+		transfer.PUT("/:id", transferHandler.UpdateTransfer)
+		transfer.DELETE("/:id", transferHandler.DeleteTransfer)
+
+		// LEDGER ROUTES
+		ledger := protected.Group("/ledgers")
+		ledger.GET("/", ledgerHandler.GetLedgersByUserID)
+		ledger.GET("/invitations", ledgerHandler.GetMyLedgerInvitations)
+		ledger.POST("/invitations/:invitation_id/accept", ledgerHandler.AcceptLedgerInvitation)
+		ledger.POST("/invitations/:invitation_id/decline", ledgerHandler.DeclineLedgerInvitation)
+		ledger.GET("/:id", ledgerHandler.GetLedgerByID)
+		ledger.POST("/", ledgerHandler.CreateLedger)
+		ledger.PUT("/:id", ledgerHandler.UpdateLedger)
+		ledger.DELETE("/:id", ledgerHandler.DeleteLedger)
+		ledger.GET("/:id/invitations", ledgerHandler.GetLedgerInvitations)
+		ledger.POST("/:id/invitations", ledgerHandler.CreateLedgerInvitation)
+		ledger.DELETE("/:id/invitations/:invitation_id", ledgerHandler.DeleteLedgerInvitation)
+		ledger.PUT("/:id/members/:user_id", ledgerHandler.UpdateLedgerMember)
+		ledger.DELETE("/:id/members/:user_id", ledgerHandler.DeleteLedgerMember)
+		ledger.GET("/:id/balances", settlementHandler.GetLedgerBalances)
+		ledger.GET("/:id/settlements", settlementHandler.GetSettlementsByLedgerID)
+		ledger.GET("/:id/settlements/:settlement_id", settlementHandler.GetSettlementByID)
+		ledger.POST("/:id/settlements", settlementHandler.CreateSettlement)
+		ledger.DELETE("/:id/settlements/:settlement_id", settlementHandler.DeleteSettlement)
 	}
 }
@@ -0,0 +1,71 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"go-expense-tracker-api/services"
+	"go-expense-tracker-api/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// HEADER SELECTING THE SHARED LEDGER A REQUEST WORKS ON, WITHOUT IT THE PERSONAL DATA OF THE USER IS USED
+const LedgerHeader = "X-Ledger-ID"
+
+// RESOLVE THE LEDGER SELECTED BY THE X-Ledger-ID HEADER, MUST RUN AFTER AuthMiddleware
+// SETS ledger_member TO THE MEMBERSHIP OF THE USER, REJECTS USERS THAT ARE NOT MEMBERS
+func LedgerContext(ledgerService *services.LedgerService) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		header := c.GetHeader(LedgerHeader)
+		if header == "" {
+			c.Next()
+			return
+		}
+
+		ledgerID, err := strconv.ParseUint(header, 10, 32)
+		if err != nil || ledgerID == 0 {
+			utils.ErrorResponse(c, http.StatusBadRequest, "Invalid "+LedgerHeader+" header")
+			c.Abort()
+			return
+		}
+
+		userID, exists := c.Get("user_id")
+		if !exists {
+			utils.ErrorResponse(c, http.StatusUnauthorized, "User not authenticated")
+			c.Abort()
+			return
+		}
+
+		member, err := ledgerService.Member(uint(ledgerID), userID.(uint))
+		if errors.Is(err, services.ErrLedgerAccessDenied) {
+			utils.ErrorResponse(c, http.StatusForbidden, "You are not a member of this ledger")
+			c.Abort()
+			return
+		}
+		if err != nil {
+			utils.ErrorResponse(c, http.StatusInternalServerError, "Failed to get ledger")
+			c.Abort()
+			return
+		}
+
+		// SET LEDGER MEMBERSHIP TO CONTEXT
+		c.Set("ledger_member", member)
+
+		c.Next()
+	}
+}
+
+// REJECT REQUESTS SELECTING A LEDGER ON ROUTES THAT ONLY WORK ON THE PERSONAL DATA OF THE USER
+func PersonalOnly() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader(LedgerHeader) != "" {
+			utils.ErrorResponse(c, http.StatusBadRequest, LedgerHeader+" header is not supported on this route")
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
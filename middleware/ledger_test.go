@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/services"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+type fakeLedgerStore struct {
+	members map[uint]string
+}
+
+func (s *fakeLedgerStore) GetMember(ledgerID, userID uint) (*models.LedgerMember, error) {
+	role, ok := s.members[userID]
+	if !ok || ledgerID != 1 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &models.LedgerMember{LedgerID: ledgerID, UserID: userID, Role: role}, nil
+}
+
+func TestLedgerContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	service := services.NewLedgerService(&fakeLedgerStore{members: map[uint]string{1: models.LedgerRoleOwner, 2: models.LedgerRoleViewer}})
+
+	tests := []struct {
+		name       string
+		userID     uint
+		header     string
+		wantStatus int
+		wantRole   string
+	}{
+		{name: "personal request", userID: 3, wantStatus: http.StatusOK},
+		{name: "owner", userID: 1, header: "1", wantStatus: http.StatusOK, wantRole: models.LedgerRoleOwner},
+		{name: "viewer", userID: 2, header: "1", wantStatus: http.StatusOK, wantRole: models.LedgerRoleViewer},
+		{name: "non-member", userID: 3, header: "1", wantStatus: http.StatusForbidden},
+		{name: "unknown ledger", userID: 1, header: "2", wantStatus: http.StatusForbidden},
+		{name: "invalid header", userID: 1, header: "abc", wantStatus: http.StatusBadRequest},
+		{name: "ledger zero", userID: 1, header: "0", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var role string
+			router := gin.New()
+			router.GET("/", func(c *gin.Context) { c.Set("user_id", tt.userID) }, LedgerContext(service), func(c *gin.Context) {
+				if member, exists := c.Get("ledger_member"); exists {
+					role = member.(*models.LedgerMember).Role
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tt.header != "" {
+				req.Header.Set(LedgerHeader, tt.header)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			if w.Code != tt.wantStatus || role != tt.wantRole {
+				t.Errorf("status = %d, role = %q, want %d, %q", w.Code, role, tt.wantStatus, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestPersonalOnly(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/", PersonalOnly(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for header, want := range map[string]int{"": http.StatusOK, "1": http.StatusBadRequest} {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if header != "" {
+			req.Header.Set(LedgerHeader, header)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+
+		if w.Code != want {
+			t.Errorf("%s %q: status = %d, want %d", LedgerHeader, header, w.Code, want)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package middleware
 import (
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,6 +14,12 @@ type QueryParams struct {
 	Filters map[string]string
 	SortBy  string
 	Order   string
+
+	// TIMEZONE FOR DATE FILTERS WITHOUT A TIME, SET BY HANDLERS FROM THE USER SETTINGS
+	Location *time.Location
+
+	// SHARED LEDGER SELECTED BY THE X-Ledger-ID HEADER, SET BY HANDLERS. NIL FOR THE PERSONAL DATA OF THE USER
+	LedgerID *uint
 }
 
 func PaginationAndFilter() gin.HandlerFunc {
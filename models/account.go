@@ -0,0 +1,47 @@
+package models
+
+import "time"
+
+const (
+	AccountTypeCash       = "cash"
+	AccountTypeBank       = "bank"
+	AccountTypeCreditCard = "credit_card"
+	AccountTypeSavings    = "savings"
+	AccountTypeOther      = "other"
+)
+
+// A WALLET THE USER PAYS FROM OR RECEIVES INTO, EXPENSES BOOKED ON IT ARE IN ITS CURRENCY
+type Account struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID         uint      `json:"-" gorm:"not null;uniqueIndex:idx_accounts_user_name"`
+	Name           string    `json:"name" gorm:"size:100;not null;uniqueIndex:idx_accounts_user_name"`
+	Type           string    `json:"type" gorm:"size:20;not null"`
+	Currency       string    `json:"currency" gorm:"size:3;not null"`
+	OpeningBalance Money     `json:"opening_balance" swaggertype:"number" example:"1500000"`
+	CreatedAt      time.Time `json:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+type AccountRequest struct {
+	Name           string `json:"name" validate:"required,min=1,max=100" example:"BCA"`
+	Type           string `json:"type" validate:"required,oneof=cash bank credit_card savings other" example:"bank"`
+	Currency       string `json:"currency" validate:"omitempty,len=3,alpha" example:"IDR"` // DEFAULTS TO THE USER BASE CURRENCY
+	OpeningBalance Money  `json:"opening_balance" swaggertype:"number" example:"1500000"`  // NEGATIVE FOR A CREDIT CARD THAT STARTS WITH A DEBT
+}
+
+// BALANCE OF AN ACCOUNT IN ITS CURRENCY, OPENING BALANCE PLUS INCOME AND INCOMING TRANSFERS
+// MINUS EXPENSES AND OUTGOING TRANSFERS WITH THEIR FEES
+type AccountBalance struct {
+	AccountID      uint      `json:"account_id"`
+	Name           string    `json:"name"`
+	Type           string    `json:"type"`
+	Currency       string    `json:"currency"`
+	OpeningBalance Money     `json:"opening_balance" swaggertype:"number"`
+	Income         Money     `json:"income" swaggertype:"number"`
+	Expense        Money     `json:"expense" swaggertype:"number"`
+	TransfersIn    Money     `json:"transfers_in" swaggertype:"number"`
+	TransfersOut   Money     `json:"transfers_out" swaggertype:"number"` // INCLUDING FEES
+	Balance        Money     `json:"balance" swaggertype:"number"`
+	Count          int64     `json:"count"`
+	AsOf           time.Time `json:"as_of"`
+}
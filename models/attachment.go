@@ -0,0 +1,15 @@
+package models
+
+import "time"
+
+// A RECEIPT FILE OF AN EXPENSE, THE CONTENT LIVES IN THE CONFIGURED STORAGE UNDER StorageKey
+type Attachment struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	ExpenseID   uint      `json:"expense_id" gorm:"not null;index"`
+	UserID      uint      `json:"-" gorm:"not null;index"`
+	FileName    string    `json:"file_name" gorm:"size:255;not null"`
+	ContentType string    `json:"content_type" gorm:"size:100;not null"`
+	Size        int64     `json:"size"`
+	StorageKey  string    `json:"-" gorm:"size:255;not null;uniqueIndex"`
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,120 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	BudgetPeriodWeekly  = "weekly"
+	BudgetPeriodMonthly = "monthly"
+	BudgetPeriodCustom  = "custom"
+)
+
+type Budget struct {
+	ID         uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint       `json:"-" gorm:"index"`
+	CategoryID uint       `json:"-" gorm:"index"`
+	Amount     Money      `json:"amount" swaggertype:"number" example:"2000000"`
+	Currency   string     `json:"currency" gorm:"size:3;not null;default:'IDR'"` // USER BASE CURRENCY WHEN CREATED
+	Period     string     `json:"period" gorm:"not null"`
+	StartDate  *time.Time `json:"start_date,omitempty"` // CUSTOM PERIOD ONLY, FROM THE START OF THIS DAY IN THE USER TIMEZONE
+	EndDate    *time.Time `json:"end_date,omitempty"`   // CUSTOM PERIOD ONLY, TO THE END OF THIS DAY IN THE USER TIMEZONE
+
+	// PERCENTAGES OF THE LIMIT THAT RAISE AN ALERT ONCE PER PERIOD
+	AlertThresholds Thresholds `json:"alert_thresholds" gorm:"type:varchar(100);not null;default:'80,100'" swaggertype:"array,integer" example:"80,100"`
+
+	// RELATIONSHIPS
+	Category Category `json:"category" gorm:"foreignKey:CategoryID;references:ID"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type BudgetRequest struct {
+	CategoryID uint   `json:"category_id" validate:"required"`
+	Amount     Money  `json:"amount" validate:"required,gt=0" swaggertype:"number" example:"2000000"`
+	Period     string `json:"period" validate:"required,oneof=weekly monthly custom" example:"monthly"`
+	StartDate  string `json:"start_date" validate:"required_if=Period custom" example:"2025-01-01"`
+	EndDate    string `json:"end_date" validate:"required_if=Period custom" example:"2025-03-31"`
+	// DEFAULTS TO [80, 100]
+	AlertThresholds []int `json:"alert_thresholds" validate:"omitempty,max=10,dive,min=1,max=1000" example:"80,100"`
+}
+
+// BUDGET USAGE FOR THE PERIOD CONTAINING THE CURRENT DATE, AMOUNTS IN THE USER BASE CURRENCY
+type BudgetStatus struct {
+	Budget         Budget    `json:"budget"`
+	PeriodStart    time.Time `json:"period_start"`
+	PeriodEnd      time.Time `json:"period_end"` // EXCLUSIVE
+	Currency       string    `json:"currency"`
+	Limit          Money     `json:"limit" swaggertype:"number"`
+	Spent          Money     `json:"spent" swaggertype:"number"`
+	Remaining      Money     `json:"remaining" swaggertype:"number"`
+	PercentageUsed float64   `json:"percentage_used"`
+}
+
+var DefaultAlertThresholds = Thresholds{80, 100}
+
+// THRESHOLDS ARE PERCENTAGES STORED AS A COMMA SEPARATED LIST, e.g. "80,100"
+type Thresholds []int
+
+// SORT ASCENDING AND DROP DUPLICATES
+func NewThresholds(values []int) Thresholds {
+	thresholds := append(Thresholds{}, values...)
+	sort.Ints(thresholds)
+
+	unique := thresholds[:0]
+	for i, value := range thresholds {
+		if i == 0 || value != thresholds[i-1] {
+			unique = append(unique, value)
+		}
+	}
+
+	return unique
+}
+
+func (t Thresholds) Value() (driver.Value, error) {
+	values := make([]string, len(t))
+	for i, value := range t {
+		values[i] = strconv.Itoa(value)
+	}
+	return strings.Join(values, ","), nil
+}
+
+func (t *Thresholds) Scan(src any) error {
+	var value string
+
+	switch v := src.(type) {
+	case nil:
+		*t = nil
+		return nil
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("cannot scan %T into Thresholds", src)
+	}
+
+	thresholds := Thresholds{}
+	for _, part := range strings.Split(value, ",") {
+		if part = strings.TrimSpace(part); part == "" {
+			continue
+		}
+		threshold, err := strconv.Atoi(part)
+		if err != nil {
+			return fmt.Errorf("invalid threshold %q", part)
+		}
+		thresholds = append(thresholds, threshold)
+	}
+
+	*t = thresholds
+	return nil
+}
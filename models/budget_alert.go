@@ -0,0 +1,54 @@
+package models
+
+import "time"
+
+// A BUDGET THRESHOLD CROSSED IN A PERIOD, UNIQUE PER BUDGET, THRESHOLD AND PERIOD SO IT FIRES ONCE
+type BudgetAlert struct {
+	ID             uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID         uint      `json:"user_id" gorm:"index"`
+	BudgetID       uint      `json:"budget_id" gorm:"not null;uniqueIndex:idx_budget_alerts_once"`
+	CategoryID     uint      `json:"category_id"`
+	CategoryName   string    `json:"category_name"`
+	Threshold      int       `json:"threshold" gorm:"not null;uniqueIndex:idx_budget_alerts_once"`
+	PeriodStart    time.Time `json:"period_start" gorm:"not null;uniqueIndex:idx_budget_alerts_once"`
+	PeriodEnd      time.Time `json:"period_end"`
+	Currency       string    `json:"currency" gorm:"size:3"`
+	LimitAmount    Money     `json:"limit" swaggertype:"number"`
+	Spent          Money     `json:"spent" swaggertype:"number"`
+	PercentageUsed float64   `json:"percentage_used"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+const NotificationTypeBudgetAlert = "budget_alert"
+
+// IN-APP NOTIFICATION SHOWN TO THE USER
+type Notification struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        uint      `json:"-" gorm:"index"`
+	Type          string    `json:"type" gorm:"not null"`
+	Title         string    `json:"title" gorm:"not null"`
+	Message       string    `json:"message"`
+	BudgetAlertID *uint     `json:"budget_alert_id,omitempty"`
+	IsRead        bool      `json:"is_read" gorm:"not null;default:false"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+const (
+	WebhookDeliveryPending   = "pending"
+	WebhookDeliveryDelivered = "delivered"
+	WebhookDeliveryFailed    = "failed" // GAVE UP AFTER THE LAST RETRY
+)
+
+// ONE BUDGET ALERT POSTED TO THE WEBHOOK, RETRIED UNTIL THE WEBHOOK ACCEPTS IT OR THE RETRIES RUN OUT
+type WebhookDelivery struct {
+	ID            uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	BudgetAlertID uint       `json:"budget_alert_id" gorm:"not null;index"`
+	Payload       string     `json:"-" gorm:"type:text;not null"` // THE BODY POSTED ON EVERY ATTEMPT, SO RETRIES CARRY THE SAME SIGNATURE
+	Status        string     `json:"status" gorm:"not null;default:'pending'"`
+	Attempts      int        `json:"attempts" gorm:"not null;default:0"`
+	LastError     string     `json:"last_error,omitempty"`
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty" gorm:"index"` // NIL ONCE DELIVERED OR FAILED
+	DeliveredAt   *time.Time `json:"delivered_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+}
@@ -0,0 +1,84 @@
+package models
+
+import "time"
+
+const (
+	RuleMatchContains = "contains"
+	RuleMatchRegex    = "regex"
+)
+
+// A RULE OF THE USER THAT CATEGORIZES AND TAGS PERSONAL EXPENSES CREATED OR IMPORTED WITHOUT A CATEGORY
+// EVERY CONDITION SET MUST HOLD, ACTIVE RULES ARE TRIED BY PRIORITY (LOWEST FIRST) AND THE FIRST MATCH WINS
+type CategorizationRule struct {
+	ID       uint   `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID   uint   `json:"-" gorm:"not null;index"`
+	Name     string `json:"name" gorm:"size:100;not null"`
+	Priority int    `json:"priority" gorm:"not null"`
+	IsActive bool   `json:"is_active" gorm:"not null"`
+
+	// CONDITIONS
+	MatchType string `json:"match_type,omitempty" gorm:"size:10"` // contains OR regex ON THE EXPENSE NAME, CASE INSENSITIVE
+	Pattern   string `json:"pattern,omitempty" gorm:"size:255"`
+	MinAmount *Money `json:"min_amount,omitempty" swaggertype:"number" example:"10"`  // INCLUSIVE, IN THE CURRENCY OF THE EXPENSE
+	MaxAmount *Money `json:"max_amount,omitempty" swaggertype:"number" example:"100"` // INCLUSIVE, IN THE CURRENCY OF THE EXPENSE
+	PayeeID   *uint  `json:"payee_id,omitempty" gorm:"index"`
+
+	// ACTIONS
+	CategoryID uint  `json:"category_id" gorm:"not null;index"`
+	Tags       []Tag `json:"tags" gorm:"many2many:categorization_rule_tags"` // ADDED TO THE TAGS OF THE EXPENSE
+
+	// RELATIONSHIPS
+	Category Category `json:"category" gorm:"foreignKey:CategoryID;references:ID"`
+	Payee    *Payee   `json:"payee,omitempty" gorm:"foreignKey:PayeeID;references:ID"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// AT LEAST ONE CONDITION IS REQUIRED
+type CategorizationRuleRequest struct {
+	Name       string   `json:"name" validate:"required,min=1,max=100" example:"Fuel"`
+	Priority   int      `json:"priority" example:"10"` // LOWEST FIRST, RULES OF THE SAME PRIORITY BY ID
+	IsActive   *bool    `json:"is_active"`             // DEFAULTS TO TRUE
+	MatchType  string   `json:"match_type" validate:"omitempty,oneof=contains regex" example:"contains"`
+	Pattern    string   `json:"pattern" validate:"required_with=MatchType,max=255" example:"shell"`
+	MinAmount  *Money   `json:"min_amount" validate:"omitempty,gte=0" swaggertype:"number" example:"10"`
+	MaxAmount  *Money   `json:"max_amount" validate:"omitempty,gte=0" swaggertype:"number" example:"100"`
+	PayeeID    *uint    `json:"payee_id"`
+	CategoryID uint     `json:"category_id" validate:"required"`
+	Tags       []string `json:"tags" validate:"omitempty,max=20,dive,min=1,max=50,excludesall=0x2C" example:"car"` // TAG NAMES, MISSING TAGS ARE CREATED
+}
+
+// APPLY THE ACTIVE RULES TO EXISTING PERSONAL EXPENSES SPENT BETWEEN From AND To, SPLIT EXPENSES ARE LEFT ALONE
+// THE FIRST MATCHING RULE OF THE SAME CATEGORY TYPE ADDS ITS TAGS, WITH Overwrite IT ALSO REPLACES THE CATEGORY, INCLUDING ONE CHOSEN BY HAND
+type CategorizationApplyRequest struct {
+	From       string `json:"from" example:"2025-01-01"` // YYYY-MM-DD OR RFC3339, OPTIONAL
+	To         string `json:"to" example:"2025-01-31"`   // YYYY-MM-DD (INCLUSIVE) OR RFC3339 (EXCLUSIVE), OPTIONAL
+	CategoryID uint   `json:"category_id" example:"12"`  // ONLY EXPENSES CURRENTLY IN THIS CATEGORY, e.g. A CATCH-ALL, OPTIONAL
+	Overwrite  bool   `json:"overwrite"`                 // ALSO REPLACE THE CATEGORY, OTHERWISE RULES ONLY ADD THEIR TAGS
+	DryRun     bool   `json:"dry_run"`
+}
+
+// WHAT A RULE DOES TO ONE EXISTING EXPENSE
+type CategorizationChange struct {
+	ExpenseID      uint      `json:"expense_id"`
+	Name           string    `json:"name"`
+	Amount         Money     `json:"amount" swaggertype:"number"`
+	Currency       string    `json:"currency"`
+	SpentAt        time.Time `json:"spent_at"`
+	RuleID         uint      `json:"rule_id,omitempty"` // 0 FOR A RULE PREVIEWED BEFORE IT IS SAVED
+	FromCategoryID uint      `json:"from_category_id"`
+	CategoryID     uint      `json:"category_id"`
+	AddedTags      []string  `json:"added_tags,omitempty"`
+
+	TagIDs []uint `json:"-"` // IDS OF AddedTags
+}
+
+type CategorizationResult struct {
+	DryRun  bool                   `json:"dry_run"`
+	Checked int                    `json:"checked"`           // EXPENSES EVALUATED
+	Matched int                    `json:"matched"`           // EXPENSES A RULE MATCHED
+	Changed int                    `json:"changed"`           // MATCHED EXPENSES WHOSE CATEGORY OR TAGS DIFFER FROM THE RULE
+	HasMore bool                   `json:"has_more"`          // MORE EXPENSES WOULD CHANGE, CALL AGAIN FOR THE NEXT BATCH
+	Changes []CategorizationChange `json:"changes,omitempty"` // THE FIRST CHANGES, UP TO A LIMIT, ONLY ON A DRY RUN OR PREVIEW
+}
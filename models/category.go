@@ -10,11 +10,41 @@ type Category struct {
 	ID        uint           `json:"id" gorm:"primaryKey;autoIncrement"`
 	Name      string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
 	UserID    *uint          `json:"-" gorm:"index"`
+	LedgerID  *uint          `json:"ledger_id,omitempty" gorm:"index"` // SHARED WITH THE MEMBERS OF THE LEDGER, NIL FOR A PERSONAL CATEGORY
+	ParentID  *uint          `json:"parent_id,omitempty" gorm:"index"` // A DEFAULT CATEGORY OR ONE OF THE SAME SCOPE, OF THE SAME TYPE, NIL FOR A TOP LEVEL CATEGORY
 	Type      string         `json:"type" gorm:"not null" validate:"required,oneof=expense income"`
 	IsDefault bool           `json:"is_default" gorm:"index"`
 	CreatedAt time.Time      `json:"created_at"`
 	UpdatedAt time.Time      `json:"updated_at"`
 	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+
+	// SUBCATEGORIES, ONLY FILLED IN THE TREE VIEW
+	Children []Category `json:"children,omitempty" gorm:"-"`
+
+	// A DEFAULT CATEGORY THE USER HID, READ FROM THEIR CategoryOverride WHEN LISTING DEFAULT CATEGORIES
+	Hidden bool `json:"hidden,omitempty" gorm:"->;-:migration"`
+}
+
+// A CHANGE ONE USER MADE TO A DEFAULT CATEGORY, DEFAULT CATEGORIES THEMSELVES ARE SHARED BY EVERYONE
+type CategoryOverride struct {
+	ID         uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID     uint      `json:"-" gorm:"not null;uniqueIndex:idx_category_overrides_user_category"`
+	CategoryID uint      `json:"category_id" gorm:"not null;uniqueIndex:idx_category_overrides_user_category"`
+	Name       string    `json:"name,omitempty" gorm:"size:100"` // EMPTY KEEPS THE DEFAULT NAME
+	Hidden     bool      `json:"hidden" gorm:"not null;default:false"`
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+type CategoryOverrideRequest struct {
+	Name   string `json:"name" validate:"omitempty,min=2,max=100" example:"Groceries"` // EMPTY KEEPS THE DEFAULT NAME
+	Hidden bool   `json:"hidden"`                                                      // LEFT OUT OF THE CATEGORY LIST, EXPENSES MAY STILL USE IT
+}
+
+type CategoryCloneRequest struct {
+	Name        string `json:"name" validate:"omitempty,min=2,max=100" example:"Groceries"` // DEFAULTS TO THE NAME OF THE DEFAULT CATEGORY FOR THE USER
+	Type        string `json:"type" validate:"omitempty,oneof=expense income"`              // DEFAULTS TO THE TYPE OF THE DEFAULT CATEGORY
+	HideDefault bool   `json:"hide_default"`                                                // ALSO HIDE THE DEFAULT CATEGORY
 }
 
 type CategoryReponse struct {
@@ -27,6 +57,26 @@ type CategoryReponse struct {
 type CategoryRequest struct {
 	Name string `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
 	Type string `json:"type" gorm:"not null" validate:"required,oneof=expense income"`
+
+	// PARENT CATEGORY OF THE SAME TYPE, A DEFAULT CATEGORY OR ONE OF THE SAME SCOPE. 0 FOR A TOP LEVEL CATEGORY, OMIT TO KEEP THE CURRENT PARENT ON UPDATE
+	ParentID *uint `json:"parent_id" example:"1"`
+}
+
+type CategoryMergeRequest struct {
+	TargetID uint `json:"target_id" validate:"required" example:"2"` // A CATEGORY OF THE SAME TYPE THE EXPENSES OF THE CATEGORY MAY USE
+}
+
+// ROWS MOVED FROM A DELETED CATEGORY TO ITS TARGET, ALL ZERO WITHOUT A TARGET
+type CategoryMergeResult struct {
+	Category          Category  `json:"category"` // THE DELETED CATEGORY
+	Target            *Category `json:"target,omitempty"`
+	Expenses          int64     `json:"expenses"`           // TRASHED EXPENSES INCLUDED
+	Splits            int64     `json:"splits"`             // SPLIT LINES, A LINE MERGED INTO A LINE OF THE TARGET ON THE SAME EXPENSE COUNTS TOO
+	RecurringExpenses int64     `json:"recurring_expenses"` // RECURRING EXPENSE RULES
+	Budgets           int64     `json:"budgets"`
+	BudgetsDeleted    int64     `json:"budgets_deleted"` // BUDGETS OF THE CATEGORY DELETED BECAUSE THEIR OWNER ALREADY HAD ONE ON THE TARGET
+	Rules             int64     `json:"rules"`           // CATEGORIZATION RULES
+	Subcategories     int64     `json:"subcategories"`   // MOVED UNDER THE TARGET, OR UP TO THE PARENT WHEN THE TARGET IS A DEFAULT CATEGORY
 }
 
 type DeleteCategoryResponse struct {
@@ -39,3 +89,37 @@ type DeleteCategoryResponse struct {
 	UpdatedAt time.Time `json:"updated_at"`
 	DeletedAt time.Time `json:"deleted_at,omitempty" gorm:"index"`
 }
+
+// NEST categories UNDER THEIR PARENTS, CATEGORIES WHOSE PARENT IS NOT IN THE LIST BECOME ROOTS
+// THE ORDER OF categories IS KEPT AMONG SIBLINGS
+func BuildCategoryTree(categories []Category) []Category {
+	present := make(map[uint]bool, len(categories))
+	for _, category := range categories {
+		present[category.ID] = true
+	}
+
+	children := make(map[uint][]Category)
+	var roots []Category
+	for _, category := range categories {
+		if category.ParentID != nil && present[*category.ParentID] {
+			children[*category.ParentID] = append(children[*category.ParentID], category)
+		} else {
+			roots = append(roots, category)
+		}
+	}
+
+	var build func(nodes []Category) []Category
+	build = func(nodes []Category) []Category {
+		for i := range nodes {
+			nodes[i].Children = build(children[nodes[i].ID])
+		}
+		return nodes
+	}
+
+	tree := build(roots)
+	if tree == nil {
+		tree = []Category{}
+	}
+
+	return tree
+}
@@ -0,0 +1,72 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+)
+
+func uintPtr(value uint) *uint {
+	return &value
+}
+
+// NAMES OF THE TREE, DEPTH FIRST, WITH ONE DASH PER LEVEL
+func flattenTree(nodes []Category, depth int) []string {
+	var names []string
+	for _, node := range nodes {
+		prefix := ""
+		for i := 0; i < depth; i++ {
+			prefix += "-"
+		}
+		names = append(names, prefix+node.Name)
+		names = append(names, flattenTree(node.Children, depth+1)...)
+	}
+
+	return names
+}
+
+func TestBuildCategoryTree(t *testing.T) {
+	user := uint(1)
+
+	tests := []struct {
+		name       string
+		categories []Category
+		want       []string
+	}{
+		{
+			name:       "empty",
+			categories: nil,
+			want:       nil,
+		},
+		{
+			name: "personal subcategories under a default category",
+			categories: []Category{
+				{ID: 1, Name: "Transport", IsDefault: true},
+				{ID: 2, Name: "Food", IsDefault: true},
+				{ID: 10, Name: "Fuel", UserID: &user, ParentID: uintPtr(1)},
+				{ID: 11, Name: "Diesel", UserID: &user, ParentID: uintPtr(10)},
+				{ID: 12, Name: "Parking", UserID: &user, ParentID: uintPtr(1)},
+			},
+			want: []string{"Transport", "-Fuel", "--Diesel", "-Parking", "Food"},
+		},
+		{
+			name: "a subcategory of a hidden default category is top level",
+			categories: []Category{
+				{ID: 10, Name: "Fuel", UserID: &user, ParentID: uintPtr(1)},
+				{ID: 2, Name: "Food", IsDefault: true},
+			},
+			want: []string{"Fuel", "Food"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := BuildCategoryTree(tt.categories)
+			if tree == nil {
+				t.Fatal("BuildCategoryTree returned nil")
+			}
+			if got := flattenTree(tree, 0); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("tree = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
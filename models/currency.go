@@ -0,0 +1,41 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+)
+
+const DefaultCurrency = "IDR"
+
+// ISO 4217 MINOR UNIT DIGITS, CURRENCIES NOT LISTED USE 2
+var currencyExponents = map[string]int{
+	"BHD": 3,
+	"CLP": 0,
+	"IDR": 2,
+	"ISK": 0,
+	"JPY": 0,
+	"JOD": 3,
+	"KRW": 0,
+	"KWD": 3,
+	"OMR": 3,
+	"TND": 3,
+	"VND": 0,
+}
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NORMALIZE A CURRENCY CODE TO UPPERCASE, EMPTY STRING IF IT IS NOT A 3-LETTER CODE
+func NormalizeCurrency(code string) string {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !currencyCodePattern.MatchString(code) {
+		return ""
+	}
+	return code
+}
+
+func CurrencyExponent(code string) int {
+	if exponent, ok := currencyExponents[strings.ToUpper(code)]; ok {
+		return exponent
+	}
+	return 2
+}
@@ -0,0 +1,106 @@
+package models
+
+import (
+	"database/sql/driver"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 1 Base = Rate Currency ON Date (ECB STYLE, e.g. 1 EUR = 1.0850 USD)
+// UserID 0 MEANS A SHARED RATE LOADED FROM EXCHANGE_RATES_FILE
+type ExchangeRate struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `json:"-" gorm:"not null;default:0;uniqueIndex:idx_exchange_rates_key"`
+	Date      time.Time `json:"date" gorm:"type:date;not null;uniqueIndex:idx_exchange_rates_key"`
+	Base      string    `json:"base" gorm:"size:3;not null;uniqueIndex:idx_exchange_rates_key"`
+	Currency  string    `json:"currency" gorm:"size:3;not null;uniqueIndex:idx_exchange_rates_key"`
+	Rate      Rate      `json:"rate" swaggertype:"number" example:"1.085"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type ExchangeRateImportResponse struct {
+	Imported int `json:"imported"`
+}
+
+// RATE IS AN EXACT FIXED-POINT EXCHANGE RATE WITH RateScale DECIMAL PLACES
+type Rate int64
+
+const RateScale = 10
+
+var rateFactor = big.NewInt(10000000000)
+
+func ParseRate(value string) (Rate, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok || r.Sign() <= 0 {
+		return 0, fmt.Errorf("invalid rate %q", value)
+	}
+
+	scaled := roundRat(r.Mul(r, new(big.Rat).SetInt(rateFactor)))
+	if !scaled.IsInt64() || scaled.Sign() == 0 {
+		return 0, fmt.Errorf("rate %q is out of range", value)
+	}
+
+	return Rate(scaled.Int64()), nil
+}
+
+func (r Rate) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(int64(r)), rateFactor)
+}
+
+func (r Rate) String() string {
+	return strings.TrimSuffix(strings.TrimRight(r.Rat().FloatString(RateScale), "0"), ".")
+}
+
+func (r Rate) MarshalJSON() ([]byte, error) {
+	return []byte(r.String()), nil
+}
+
+func (r *Rate) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := ParseRate(value)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+func (r Rate) Value() (driver.Value, error) {
+	return r.Rat().FloatString(RateScale), nil
+}
+
+func (r *Rate) Scan(src any) error {
+	var value string
+
+	switch v := src.(type) {
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Errorf("cannot scan %T into Rate", src)
+	}
+
+	parsed, err := ParseRate(value)
+	if err != nil {
+		return err
+	}
+
+	*r = parsed
+	return nil
+}
+
+func (Rate) GormDataType() string {
+	return "numeric(24,10)"
+}
@@ -1,20 +1,49 @@
 package models
 
-import "time"
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
 
 type Expense struct {
-	ID         uint    `json:"id" gorm:"primaryKey, autoIncrement"`
-	Name       string  `json:"name"`
-	Amount     float64 `json:"amount"`
-	UserID     uint    `json:"-" gorm:"foreignKey:UserID;references:ID"`
-	CategoryID uint    `json:"-" gorm:"foreignKey:CategoryID;references:ID"`
+	ID           uint      `json:"id" gorm:"primaryKey, autoIncrement"`
+	Name         string    `json:"name"`
+	Amount       Money     `json:"amount" swaggertype:"number" example:"12.5"`
+	Currency     string    `json:"currency" gorm:"size:3;not null;default:'IDR'"`
+	BaseAmount   Money     `json:"base_amount" swaggertype:"number" example:"195000"` // AMOUNT IN BaseCurrency AT SpentAt
+	BaseCurrency string    `json:"base_currency" gorm:"size:3;not null;default:'IDR'"`
+	SpentAt      time.Time `json:"spent_at" gorm:"index;uniqueIndex:idx_expenses_recurring_occurrence"`
+	UserID       uint      `json:"-" gorm:"foreignKey:UserID;references:ID;uniqueIndex:idx_expenses_user_external_id"`
+	CategoryID   uint      `json:"-" gorm:"foreignKey:CategoryID;references:ID"`
+	PayeeID      *uint     `json:"payee_id,omitempty" gorm:"index"`
+	AccountID    *uint     `json:"account_id,omitempty" gorm:"index"`
+	LedgerID     *uint     `json:"ledger_id,omitempty" gorm:"index"` // SHARED WITH THE MEMBERS OF THE LEDGER, NIL FOR A PERSONAL EXPENSE
+	GoalID       *uint     `json:"goal_id,omitempty" gorm:"index"`   // A CONTRIBUTION TO (OR WITHDRAWAL FROM) A SAVINGS GOAL
+
+	// SET WHEN GENERATED BY A RECURRING EXPENSE, ONE EXPENSE PER OCCURRENCE
+	RecurringExpenseID *uint `json:"recurring_expense_id,omitempty" gorm:"uniqueIndex:idx_expenses_recurring_occurrence"`
+
+	// SET WHEN IMPORTED FROM A BANK STATEMENT (OFX FITID), SO THE SAME TRANSACTION IS NEVER IMPORTED TWICE
+	ExternalID *string `json:"external_id,omitempty" gorm:"size:255;uniqueIndex:idx_expenses_user_external_id"`
 
 	// RELATIONSHIPS
 	Category Category `json:"category" gorm:"foreignKey:CategoryID;references:ID"`
+	Tags     []Tag    `json:"tags" gorm:"many2many:expense_tags"`
+	Payee    *Payee   `json:"payee,omitempty" gorm:"foreignKey:PayeeID;references:ID"`
+	Account  *Account `json:"account,omitempty" gorm:"foreignKey:AccountID;references:ID"`
+
+	// OPTIONAL SPLIT LINES, REPORTS COUNT EACH LINE UNDER ITS OWN CATEGORY INSTEAD OF Category
+	Splits []ExpenseSplit `json:"splits,omitempty" gorm:"foreignKey:ExpenseID"`
 
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
-	DeletedAt time.Time `json:"deleted_at" gorm:"index"`
+	// A LEDGER EXPENSE PAID BY ONE MEMBER AND SHARED AMONG SEVERAL, THE PAYER IS OWED THE SHARES OF THE OTHERS
+	PaidByID    *uint          `json:"paid_by_id,omitempty" gorm:"index"`
+	ShareMethod string         `json:"share_method,omitempty" gorm:"size:10"`
+	Shares      []ExpenseShare `json:"shares,omitempty" gorm:"foreignKey:ExpenseID"`
+
+	CreatedAt time.Time      `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time      `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt gorm.DeletedAt `json:"deleted_at" gorm:"index" swaggertype:"string"` // SET WHILE IN THE TRASH
 }
 
 type ExpenseReponse struct {
@@ -25,7 +54,22 @@ type ExpenseReponse struct {
 }
 
 type ExpenseRequest struct {
-	Name       string  `json:"name" validate:"required"`
-	Amount     float64 `json:"amount" validate:"required,gt=0"`
-	CategoryID uint    `json:"category_id" gorm:"foreignKey:CategoryID;references:ID"`
+	Name       string   `json:"name" validate:"required"`
+	Amount     Money    `json:"amount" validate:"required,gt=0" swaggertype:"number" example:"12.5"`
+	Currency   string   `json:"currency" validate:"omitempty,len=3,alpha" example:"USD"`                         // DEFAULTS TO THE ACCOUNT CURRENCY, OR THE USER BASE CURRENCY WITHOUT AN ACCOUNT
+	CategoryID uint     `json:"category_id" gorm:"foreignKey:CategoryID;references:ID"`                          // SET BY THE FIRST MATCHING CATEGORIZATION RULE WHEN OMITTED ON CREATE OF A PERSONAL EXPENSE
+	PayeeID    *uint    `json:"payee_id"`                                                                        // DETECTED FROM THE NAME BY PAYEE ALIASES WHEN OMITTED
+	AccountID  *uint    `json:"account_id"`                                                                      // THE CURRENCY MUST MATCH THE ACCOUNT, DEFAULTS TO IT. OMIT TO KEEP THE CURRENT ACCOUNT ON UPDATE, 0 REMOVES IT
+	GoalID     *uint    `json:"goal_id"`                                                                         // PERSONAL EXPENSES ONLY, THE ACCOUNT DEFAULTS TO THE GOAL ACCOUNT. OMIT TO KEEP THE CURRENT GOAL ON UPDATE, 0 REMOVES IT
+	SpentAt    string   `json:"spent_at" example:"2025-01-31"`                                                   // YYYY-MM-DD OR RFC3339, DEFAULTS TO NOW
+	Tags       []string `json:"tags" validate:"omitempty,dive,min=1,max=50,excludesall=0x2C" example:"business"` // TAG NAMES, MISSING TAGS ARE CREATED. OMIT TO KEEP THE CURRENT TAGS ON UPDATE
+
+	// SPLIT LINES IN DIFFERENT CATEGORIES OF THE SAME TYPE THAT SUM TO Amount, CategoryID DEFAULTS TO THE FIRST LINE
+	// OMIT TO KEEP THE CURRENT SPLITS ON UPDATE, AN EMPTY LIST REMOVES THEM
+	Splits []ExpenseSplitRequest `json:"splits" validate:"omitempty,min=2,max=20,dive"`
+
+	// SHARE A LEDGER EXPENSE AMONG MEMBERS, PaidByID DEFAULTS TO THE USER
+	// OMIT TO KEEP THE CURRENT SHARES ON UPDATE, AN EMPTY METHOD REMOVES THEM
+	PaidByID *uint                `json:"paid_by_id"`
+	Share    *ExpenseShareRequest `json:"share" validate:"omitempty"`
 }
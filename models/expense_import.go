@@ -0,0 +1,17 @@
+package models
+
+type ExpenseImportError struct {
+	Row     int    `json:"row"` // 1-BASED LINE IN THE FILE, 0 FOR ERRORS ABOUT THE WHOLE FILE
+	Field   string `json:"field,omitempty"`
+	Message string `json:"message"`
+}
+
+type ExpenseImportResult struct {
+	DryRun   bool                 `json:"dry_run"`
+	Total    int                  `json:"total"`    // ROWS READ
+	Valid    int                  `json:"valid"`    // ROWS WITHOUT ERRORS
+	Imported int                  `json:"imported"` // ROWS INSERTED, ALWAYS 0 ON A DRY RUN OR WITH ERRORS
+	Skipped  int                  `json:"skipped"`  // ROWS ALREADY IMPORTED BEFORE, BY EXTERNAL ID
+	Errors   []ExpenseImportError `json:"errors"`
+	Expenses []*Expense           `json:"expenses,omitempty"` // EXPENSES THAT WOULD BE OR WERE CREATED
+}
@@ -0,0 +1,94 @@
+package models
+
+import "math/big"
+
+const (
+	ShareMethodEqual  = "equal"
+	ShareMethodShares = "shares"
+	ShareMethodExact  = "exact"
+)
+
+// THE PART OF A SHARED LEDGER EXPENSE A MEMBER OWES TO ITS PAYER, THE SHARES OF AN EXPENSE SUM TO ITS AMOUNT
+type ExpenseShare struct {
+	ID         uint  `json:"-" gorm:"primaryKey;autoIncrement"`
+	ExpenseID  uint  `json:"-" gorm:"not null;uniqueIndex:idx_expense_shares_expense_user"`
+	UserID     uint  `json:"user_id" gorm:"not null;uniqueIndex:idx_expense_shares_expense_user;index"`
+	Shares     int   `json:"shares,omitempty"` // WEIGHT OF THE MEMBER WITH THE shares METHOD
+	Amount     Money `json:"amount" swaggertype:"number" example:"25"`
+	BaseAmount Money `json:"base_amount" swaggertype:"number" example:"395000"` // IN THE LEDGER BASE CURRENCY
+}
+
+// SPLIT A LEDGER EXPENSE AMONG MEMBERS: equal PARTS, PROPORTIONAL TO shares, OR exact AMOUNTS THAT SUM TO THE EXPENSE AMOUNT
+type ExpenseShareRequest struct {
+	Method       string                      `json:"method" validate:"omitempty,oneof=equal shares exact" example:"equal"` // EMPTY REMOVES THE SHARES ON UPDATE
+	Participants []ExpenseParticipantRequest `json:"participants" validate:"required_with=Method,max=50,dive"`
+}
+
+type ExpenseParticipantRequest struct {
+	UserID uint  `json:"user_id" validate:"required"`
+	Shares int   `json:"shares" validate:"gte=0,lte=1000" example:"1"`              // REQUIRED WITH THE shares METHOD
+	Amount Money `json:"amount" validate:"gte=0" swaggertype:"number" example:"25"` // REQUIRED WITH THE exact METHOD
+}
+
+// DIVIDE amount PROPORTIONALLY TO weights IN WHOLE MINOR UNITS OF exponent
+// EVERY PART IS ROUNDED DOWN, THE LEFTOVER UNITS GO ONE BY ONE TO THE FIRST PARTS SO THEY ADD UP EXACTLY
+func DivideMoney(amount Money, exponent int, weights []int64) []Money {
+	parts := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	step := int64(1)
+	for i := exponent; i < MoneyScale; i++ {
+		step *= 10
+	}
+
+	var total int64
+	for _, weight := range weights {
+		total += weight
+	}
+	if total == 0 {
+		parts[0] = amount
+		return parts
+	}
+
+	units := big.NewInt(int64(amount) / step)
+	remaining := amount
+	for i, weight := range weights {
+		part := new(big.Int).Mul(units, big.NewInt(weight))
+		part.Quo(part, big.NewInt(total))
+		parts[i] = Money(part.Int64() * step)
+		remaining -= parts[i]
+	}
+
+	for i := 0; remaining >= Money(step) || remaining <= -Money(step); i = (i + 1) % len(parts) {
+		if remaining > 0 {
+			parts[i] += Money(step)
+			remaining -= Money(step)
+		} else {
+			parts[i] -= Money(step)
+			remaining += Money(step)
+		}
+	}
+
+	// AN AMOUNT FINER THAN THE MINOR UNIT STAYS WITH THE FIRST PART
+	parts[0] += remaining
+
+	return parts
+}
+
+// DISTRIBUTE THE EXPENSE BASE AMOUNT OVER ITS SHARES PROPORTIONALLY TO THEIR AMOUNTS
+func (e *Expense) AllocateShareBaseAmounts() {
+	if len(e.Shares) == 0 {
+		return
+	}
+
+	weights := make([]int64, len(e.Shares))
+	for i, share := range e.Shares {
+		weights[i] = int64(share.Amount)
+	}
+
+	for i, baseAmount := range DivideMoney(e.BaseAmount, CurrencyExponent(e.BaseCurrency), weights) {
+		e.Shares[i].BaseAmount = baseAmount
+	}
+}
@@ -0,0 +1,46 @@
+package models
+
+import "testing"
+
+func TestDivideMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   string
+		exponent int
+		weights  []int64
+		want     []string
+	}{
+		{name: "remainder cents go to the first parts", amount: "10", exponent: 2, weights: []int64{1, 1, 1}, want: []string{"3.34", "3.33", "3.33"}},
+		{name: "two remainder cents", amount: "0.05", exponent: 2, weights: []int64{1, 1, 1}, want: []string{"0.02", "0.02", "0.01"}},
+		{name: "proportional to weights", amount: "10", exponent: 2, weights: []int64{2, 1}, want: []string{"6.67", "3.33"}},
+		{name: "zero weight gets nothing", amount: "10", exponent: 2, weights: []int64{1, 0, 1}, want: []string{"5", "0", "5"}},
+		{name: "zero decimal currency", amount: "100", exponent: 0, weights: []int64{1, 1, 1}, want: []string{"34", "33", "33"}},
+		{name: "three decimal currency", amount: "1", exponent: 3, weights: []int64{1, 1, 1}, want: []string{"0.334", "0.333", "0.333"}},
+		{name: "negative amount", amount: "-10", exponent: 2, weights: []int64{1, 1, 1}, want: []string{"-3.34", "-3.33", "-3.33"}},
+		{name: "amount finer than the minor unit stays with the first part", amount: "10.0001", exponent: 2, weights: []int64{1, 1, 1}, want: []string{"3.3401", "3.33", "3.33"}},
+		{name: "all weights zero", amount: "10", exponent: 2, weights: []int64{0, 0}, want: []string{"10", "0"}},
+		{name: "no weights", amount: "10", exponent: 2, weights: nil, want: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount := mustMoney(t, tt.amount)
+			parts := DivideMoney(amount, tt.exponent, tt.weights)
+
+			if len(parts) != len(tt.want) {
+				t.Fatalf("got %d parts, want %d", len(parts), len(tt.want))
+			}
+
+			var sum Money
+			for i, part := range parts {
+				if want := mustMoney(t, tt.want[i]); part != want {
+					t.Errorf("part %d = %s, want %s", i, part, want)
+				}
+				sum += part
+			}
+			if len(parts) > 0 && sum != amount {
+				t.Errorf("parts sum to %s, want %s", sum, amount)
+			}
+		})
+	}
+}
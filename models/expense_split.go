@@ -0,0 +1,69 @@
+package models
+
+import "math/big"
+
+// A PART OF AN EXPENSE BOOKED UNDER ITS OWN CATEGORY, THE SPLITS OF AN EXPENSE SUM TO ITS AMOUNT
+type ExpenseSplit struct {
+	ID         uint  `json:"id" gorm:"primaryKey;autoIncrement"`
+	ExpenseID  uint  `json:"-" gorm:"not null;index"`
+	CategoryID uint  `json:"category_id" gorm:"not null;index"`
+	Amount     Money `json:"amount" swaggertype:"number" example:"7.5"`
+	BaseAmount Money `json:"base_amount" swaggertype:"number" example:"117000"` // SHARE OF THE EXPENSE BASE AMOUNT
+
+	// RELATIONSHIPS
+	Category Category `json:"category" gorm:"foreignKey:CategoryID;references:ID"`
+}
+
+type ExpenseSplitRequest struct {
+	CategoryID uint  `json:"category_id" validate:"required"`
+	Amount     Money `json:"amount" validate:"required,gt=0" swaggertype:"number" example:"7.5"`
+}
+
+// DISTRIBUTE THE EXPENSE BASE AMOUNT OVER ITS SPLITS PROPORTIONALLY TO THEIR AMOUNTS
+// SHARES ARE ROUNDED TO THE BASE CURRENCY MINOR UNITS, THE LAST SPLIT TAKES THE REMAINDER SO THEY ADD UP EXACTLY
+func (e *Expense) AllocateSplitBaseAmounts() {
+	if len(e.Splits) == 0 {
+		return
+	}
+
+	exponent := CurrencyExponent(e.BaseCurrency)
+	remaining := e.BaseAmount
+
+	for i := range e.Splits {
+		if i == len(e.Splits)-1 || e.Amount == 0 {
+			e.Splits[i].BaseAmount = remaining
+			remaining = 0
+			continue
+		}
+
+		share := new(big.Rat).Mul(e.BaseAmount.Rat(), big.NewRat(int64(e.Splits[i].Amount), int64(e.Amount)))
+		baseAmount, err := MoneyFromRat(share)
+		if err != nil {
+			baseAmount = 0
+		}
+		baseAmount = baseAmount.Round(exponent)
+
+		e.Splits[i].BaseAmount = baseAmount
+		remaining -= baseAmount
+	}
+}
+
+// MOVE THE EXPENSE TO ANOTHER BASE CURRENCY, THE BASE AMOUNTS OF ITS SPLITS AND SHARES ARE REALLOCATED FROM baseAmount
+func (e *Expense) Rebase(baseCurrency string, baseAmount Money) {
+	e.BaseCurrency = baseCurrency
+	e.BaseAmount = baseAmount
+	e.AllocateSplitBaseAmounts()
+	e.AllocateShareBaseAmounts()
+}
+
+// THE EXPENSE CATEGORY AND THE CATEGORIES OF ITS SPLITS
+func (e *Expense) CategoryIDs() []uint {
+	ids := []uint{e.CategoryID}
+	for _, split := range e.Splits {
+		if split.CategoryID != e.CategoryID {
+			ids = append(ids, split.CategoryID)
+		}
+	}
+
+	return ids
+}
@@ -0,0 +1,83 @@
+package models
+
+import "testing"
+
+func mustMoney(t *testing.T, value string) Money {
+	t.Helper()
+
+	m, err := ParseMoney(value)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q): %v", value, err)
+	}
+
+	return m
+}
+
+func TestExpenseRebaseSplits(t *testing.T) {
+	tests := []struct {
+		name         string
+		amount       string
+		splits       []string
+		baseCurrency string
+		baseAmount   string
+		want         []string
+	}{
+		{
+			name:         "proportional shares in cents",
+			amount:       "10",
+			splits:       []string{"7.5", "2.5"},
+			baseCurrency: "USD",
+			baseAmount:   "10.85",
+			want:         []string{"8.14", "2.71"},
+		},
+		{
+			name:         "zero decimal currency, last line takes the remainder",
+			amount:       "10",
+			splits:       []string{"3.33", "3.33", "3.34"},
+			baseCurrency: "JPY",
+			baseAmount:   "1623",
+			want:         []string{"540", "540", "543"},
+		},
+		{
+			name:         "three decimal currency",
+			amount:       "9",
+			splits:       []string{"3", "3", "3"},
+			baseCurrency: "KWD",
+			baseAmount:   "0.901",
+			want:         []string{"0.3", "0.3", "0.301"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// THE SPLITS START WITH BASE AMOUNTS IN THE OLD BASE CURRENCY
+			expense := &Expense{
+				Amount:       mustMoney(t, tt.amount),
+				Currency:     "EUR",
+				BaseAmount:   mustMoney(t, tt.amount),
+				BaseCurrency: "EUR",
+			}
+			for _, amount := range tt.splits {
+				split := mustMoney(t, amount)
+				expense.Splits = append(expense.Splits, ExpenseSplit{Amount: split, BaseAmount: split})
+			}
+
+			expense.Rebase(tt.baseCurrency, mustMoney(t, tt.baseAmount))
+
+			if expense.BaseCurrency != tt.baseCurrency || expense.BaseAmount != mustMoney(t, tt.baseAmount) {
+				t.Fatalf("expense base = %s %s, want %s %s", expense.BaseAmount, expense.BaseCurrency, tt.baseAmount, tt.baseCurrency)
+			}
+
+			var sum Money
+			for i, split := range expense.Splits {
+				if want := mustMoney(t, tt.want[i]); split.BaseAmount != want {
+					t.Errorf("split %d base amount = %s, want %s", i, split.BaseAmount, want)
+				}
+				sum += split.BaseAmount
+			}
+			if sum != expense.BaseAmount {
+				t.Errorf("split base amounts sum to %s, want %s", sum, expense.BaseAmount)
+			}
+		})
+	}
+}
@@ -0,0 +1,42 @@
+package models
+
+import "time"
+
+// A SAVINGS TARGET, CONTRIBUTIONS ARE PERSONAL EXPENSES LINKED TO IT
+// EXPENSE CATEGORIES SET MONEY ASIDE FOR THE GOAL, INCOME CATEGORIES TAKE IT BACK OUT
+type Goal struct {
+	ID           uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID       uint       `json:"-" gorm:"not null;index"`
+	Name         string     `json:"name" gorm:"size:100;not null"`
+	TargetAmount Money      `json:"target_amount" swaggertype:"number" example:"15000000"`
+	Currency     string     `json:"currency" gorm:"size:3;not null;default:'IDR'"` // USER BASE CURRENCY WHEN CREATED
+	TargetDate   *time.Time `json:"target_date,omitempty"`
+	AccountID    *uint      `json:"account_id,omitempty" gorm:"index"` // NEW CONTRIBUTIONS ARE BOOKED ON IT BY DEFAULT
+
+	// RELATIONSHIPS
+	Account *Account `json:"account,omitempty" gorm:"foreignKey:AccountID;references:ID"`
+
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type GoalRequest struct {
+	Name         string `json:"name" validate:"required,min=1,max=100" example:"Emergency fund"`
+	TargetAmount Money  `json:"target_amount" validate:"required,gt=0" swaggertype:"number" example:"15000000"` // IN THE USER BASE CURRENCY
+	TargetDate   string `json:"target_date" example:"2025-12-31"`                                               // YYYY-MM-DD OR RFC3339, OPTIONAL
+	AccountID    *uint  `json:"account_id"`                                                                     // OPTIONAL, 0 REMOVES IT
+}
+
+// PROGRESS OF A GOAL AT AsOf, AMOUNTS IN THE USER BASE CURRENCY
+type GoalProgress struct {
+	Goal            Goal      `json:"goal"`
+	Currency        string    `json:"currency"`
+	Target          Money     `json:"target" swaggertype:"number"`
+	Saved           Money     `json:"saved" swaggertype:"number"`
+	Remaining       Money     `json:"remaining" swaggertype:"number"` // NEVER BELOW ZERO
+	PercentageSaved float64   `json:"percentage_saved"`
+	Contributions   int64     `json:"contributions"`
+	MonthsLeft      *int      `json:"months_left,omitempty"`                           // MONTHS UNTIL THE TARGET DATE, A PARTIAL MONTH COUNTS AS ONE, 0 ONCE IT HAS PASSED
+	RequiredMonthly *Money    `json:"required_monthly,omitempty" swaggertype:"number"` // TO SAVE EVERY MONTH TO REACH THE TARGET ON TIME, ALL OF Remaining ONCE THE DATE HAS PASSED
+	AsOf            time.Time `json:"as_of"`
+}
@@ -0,0 +1,76 @@
+package models
+
+import "time"
+
+const (
+	LedgerRoleOwner  = "owner"
+	LedgerRoleEditor = "editor"
+	LedgerRoleViewer = "viewer"
+)
+
+// A HOUSEHOLD BOOK SHARED BY ITS MEMBERS, EXPENSES AND CATEGORIES WITH A LedgerID BELONG TO IT
+// INSTEAD OF THE USER THAT CREATED THEM
+type Ledger struct {
+	ID           uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name         string    `json:"name" gorm:"size:100;not null"`
+	OwnerID      uint      `json:"owner_id" gorm:"not null;index"`
+	BaseCurrency string    `json:"base_currency" gorm:"size:3;not null"` // BASE AMOUNTS OF LEDGER EXPENSES ARE IN THIS CURRENCY
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+
+	// ROLE OF THE REQUESTING USER, ONLY SET WHEN LISTING THE LEDGERS OF A USER
+	Role string `json:"role,omitempty" gorm:"->;-:migration"`
+
+	Members []LedgerMember `json:"members,omitempty" gorm:"foreignKey:LedgerID"`
+}
+
+type LedgerMember struct {
+	ID        uint        `json:"-" gorm:"primaryKey;autoIncrement"`
+	LedgerID  uint        `json:"ledger_id" gorm:"not null;uniqueIndex:idx_ledger_members_ledger_user"`
+	UserID    uint        `json:"user_id" gorm:"not null;uniqueIndex:idx_ledger_members_ledger_user;index"`
+	Role      string      `json:"role" gorm:"size:10;not null"`
+	CreatedAt time.Time   `json:"created_at"`
+	User      *LedgerUser `json:"user,omitempty" gorm:"foreignKey:UserID;references:ID"`
+	Ledger    *Ledger     `json:"-" gorm:"foreignKey:LedgerID;references:ID"`
+}
+
+// PUBLIC PROFILE OF A LEDGER MEMBER
+type LedgerUser struct {
+	ID    uint   `json:"id"`
+	Name  string `json:"name"`
+	Email string `json:"email"`
+}
+
+func (LedgerUser) TableName() string {
+	return "users"
+}
+
+// PENDING INVITATION OF AN EMAIL ADDRESS, DELETED ONCE ACCEPTED OR DECLINED
+type LedgerInvitation struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	LedgerID    uint      `json:"ledger_id" gorm:"not null;uniqueIndex:idx_ledger_invitations_ledger_email"`
+	Email       string    `json:"email" gorm:"size:255;not null;uniqueIndex:idx_ledger_invitations_ledger_email;index"`
+	Role        string    `json:"role" gorm:"size:10;not null"`
+	InvitedByID uint      `json:"invited_by_id" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	Ledger      *Ledger   `json:"ledger,omitempty" gorm:"foreignKey:LedgerID;references:ID"`
+}
+
+type LedgerRequest struct {
+	Name         string `json:"name" validate:"required,min=1,max=100" example:"Household"`
+	BaseCurrency string `json:"base_currency" validate:"omitempty,len=3,alpha" example:"IDR"` // DEFAULTS TO THE USER BASE CURRENCY, CANNOT CHANGE ONCE THE LEDGER HAS EXPENSES
+}
+
+type LedgerInvitationRequest struct {
+	Email string `json:"email" validate:"required,email" example:"partner@example.com"`
+	Role  string `json:"role" validate:"required,oneof=editor viewer" example:"editor"`
+}
+
+type LedgerMemberRequest struct {
+	Role string `json:"role" validate:"required,oneof=editor viewer" example:"viewer"`
+}
+
+// OWNERS AND EDITORS MAY CHANGE LEDGER EXPENSES AND CATEGORIES, VIEWERS ONLY READ THEM
+func (m *LedgerMember) CanWrite() bool {
+	return m.Role == LedgerRoleOwner || m.Role == LedgerRoleEditor
+}
@@ -0,0 +1,171 @@
+package models
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// MONEY IS AN EXACT FIXED-POINT AMOUNT STORED AS AN INTEGER NUMBER OF 10^-MoneyScale UNITS
+// IT IS PERSISTED AS numeric(19,4) AND ENCODED IN JSON AS A PLAIN DECIMAL NUMBER (e.g. 12.5)
+type Money int64
+
+const MoneyScale = 4
+
+var moneyFactor = big.NewInt(10000)
+
+// PARSE A DECIMAL STRING ("12.34", "-5", "1e3") WITHOUT LOSING PRECISION
+func ParseMoney(value string) (Money, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(value))
+	if !ok {
+		return 0, fmt.Errorf("invalid amount %q", value)
+	}
+
+	r.Mul(r, new(big.Rat).SetInt(moneyFactor))
+	if !r.IsInt() {
+		return 0, fmt.Errorf("amount %q has more than %d decimal places", value, MoneyScale)
+	}
+
+	return moneyFromInt(r.Num())
+}
+
+// CONVERT A RATIONAL AMOUNT TO MONEY, ROUNDING HALF AWAY FROM ZERO
+func MoneyFromRat(r *big.Rat) (Money, error) {
+	scaled := new(big.Rat).Mul(r, new(big.Rat).SetInt(moneyFactor))
+	return moneyFromInt(roundRat(scaled))
+}
+
+func moneyFromInt(i *big.Int) (Money, error) {
+	if !i.IsInt64() {
+		return 0, errors.New("amount is out of range")
+	}
+	return Money(i.Int64()), nil
+}
+
+// ROUND HALF AWAY FROM ZERO TO THE NEAREST INTEGER
+func roundRat(r *big.Rat) *big.Int {
+	num := new(big.Int).Abs(r.Num())
+	den := r.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Mul(rem, big.NewInt(2)).Cmp(den) >= 0 {
+		quo.Add(quo, big.NewInt(1))
+	}
+	if r.Sign() < 0 {
+		quo.Neg(quo)
+	}
+
+	return quo
+}
+
+func (m Money) Rat() *big.Rat {
+	return new(big.Rat).SetFrac(big.NewInt(int64(m)), moneyFactor)
+}
+
+// FLOAT VALUE, ONLY FOR RATIOS AND PERCENTAGES, NEVER FOR SUMS
+func (m Money) Float64() float64 {
+	f, _ := m.Rat().Float64()
+	return f
+}
+
+// ROUND TO A CURRENCY EXPONENT (NUMBER OF MINOR UNIT DIGITS)
+func (m Money) Round(exponent int) Money {
+	if exponent >= MoneyScale {
+		return m
+	}
+
+	step := int64(1)
+	for i := exponent; i < MoneyScale; i++ {
+		step *= 10
+	}
+
+	return Money(roundRat(big.NewRat(int64(m), step)).Int64() * step)
+}
+
+// REPORT WHETHER THE AMOUNT HAS NO MORE DECIMAL PLACES THAN THE EXPONENT ALLOWS
+func (m Money) FitsExponent(exponent int) bool {
+	return m.Round(exponent) == m
+}
+
+// MINIMAL DECIMAL REPRESENTATION, e.g. 12.3400 -> "12.34", 5.0000 -> "5"
+func (m Money) String() string {
+	return strings.TrimSuffix(strings.TrimRight(m.StringFixed(MoneyScale), "0"), ".")
+}
+
+// DECIMAL REPRESENTATION WITH EXACTLY decimals DIGITS AFTER THE POINT
+func (m Money) StringFixed(decimals int) string {
+	if decimals > MoneyScale {
+		decimals = MoneyScale
+	}
+	return m.Round(decimals).Rat().FloatString(decimals)
+}
+
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// ACCEPT BOTH JSON NUMBERS (12.34) AND STRINGS ("12.34")
+func (m *Money) UnmarshalJSON(data []byte) error {
+	value := string(data)
+	if value == "null" {
+		return nil
+	}
+
+	if unquoted, err := strconv.Unquote(value); err == nil {
+		value = unquoted
+	}
+
+	parsed, err := ParseMoney(value)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func (m Money) Value() (driver.Value, error) {
+	return m.StringFixed(MoneyScale), nil
+}
+
+func (m *Money) Scan(src any) error {
+	var value string
+
+	switch v := src.(type) {
+	case nil:
+		*m = 0
+		return nil
+	case int64:
+		*m = Money(v) * Money(moneyFactor.Int64())
+		return nil
+	case float64:
+		value = strconv.FormatFloat(v, 'f', -1, 64)
+	case []byte:
+		value = string(v)
+	case string:
+		value = v
+	default:
+		return fmt.Errorf("cannot scan %T into Money", src)
+	}
+
+	// AGGREGATES SUCH AS AVG MAY CARRY MORE DIGITS THAN MoneyScale
+	r, ok := new(big.Rat).SetString(value)
+	if !ok {
+		return fmt.Errorf("cannot scan %q into Money", value)
+	}
+
+	parsed, err := MoneyFromRat(r)
+	if err != nil {
+		return err
+	}
+
+	*m = parsed
+	return nil
+}
+
+func (Money) GormDataType() string {
+	return "numeric(19,4)"
+}
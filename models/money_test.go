@@ -0,0 +1,193 @@
+package models
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    Money
+		wantErr bool
+	}{
+		{value: "12.34", want: 123400},
+		{value: " -5 ", want: -50000},
+		{value: "0.0001", want: 1},
+		{value: "1e3", want: 10000000},
+		{value: "922337203685477.5807", want: 9223372036854775807},
+		{value: "0.00001", wantErr: true},
+		{value: "1.23456", wantErr: true},
+		{value: "922337203685477.5808", wantErr: true},
+		{value: "-922337203685477.5809", wantErr: true},
+		{value: "12,34", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := ParseMoney(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMoney(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyFromRat(t *testing.T) {
+	tests := []struct {
+		rat     string
+		want    Money
+		wantErr bool
+	}{
+		{rat: "1/3", want: 3333},
+		{rat: "2/3", want: 6667},
+		{rat: "0.00005", want: 1},
+		{rat: "-0.00005", want: -1},
+		{rat: "-0.00004", want: 0},
+		{rat: "-2/3", want: -6667},
+		{rat: "1e15", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.rat, func(t *testing.T) {
+			r, _ := new(big.Rat).SetString(tt.rat)
+			got, err := MoneyFromRat(r)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("MoneyFromRat(%s) error = %v, wantErr %v", tt.rat, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("MoneyFromRat(%s) = %d, want %d", tt.rat, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyRound(t *testing.T) {
+	tests := []struct {
+		money    Money
+		exponent int
+		want     Money
+	}{
+		{money: 12345, exponent: 2, want: 12300},   // 1.2345 -> 1.23
+		{money: 12350, exponent: 2, want: 12400},   // 1.235 -> 1.24
+		{money: -12350, exponent: 2, want: -12400}, // -1.235 -> -1.24, HALF AWAY FROM ZERO
+		{money: -12349, exponent: 2, want: -12300},
+		{money: 15000, exponent: 0, want: 20000},   // 1.5 -> 2
+		{money: -25000, exponent: 0, want: -30000}, // -2.5 -> -3
+		{money: 12345, exponent: 4, want: 12345},
+		{money: 12345, exponent: 6, want: 12345},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.Round(tt.exponent); got != tt.want {
+			t.Errorf("Money(%d).Round(%d) = %d, want %d", tt.money, tt.exponent, got, tt.want)
+		}
+	}
+
+	if !Money(12300).FitsExponent(2) || Money(12340).FitsExponent(2) || Money(12345).FitsExponent(2) || !Money(10000).FitsExponent(0) {
+		t.Error("FitsExponent does not match Round")
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		money     Money
+		want      string
+		wantFixed string
+	}{
+		{money: 123400, want: "12.34", wantFixed: "12.34"},
+		{money: 50000, want: "5", wantFixed: "5.00"},
+		{money: -1, want: "-0.0001", wantFixed: "0.00"},
+		{money: -12350, want: "-1.235", wantFixed: "-1.24"},
+		{money: 0, want: "0", wantFixed: "0.00"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.money.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.money, got, tt.want)
+		}
+		if got := tt.money.StringFixed(2); got != tt.wantFixed {
+			t.Errorf("Money(%d).StringFixed(2) = %q, want %q", tt.money, got, tt.wantFixed)
+		}
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var body struct {
+		Amount Money  `json:"amount"`
+		Limit  *Money `json:"limit"`
+	}
+
+	for _, tt := range []struct {
+		json    string
+		want    Money
+		wantErr bool
+	}{
+		{json: `{"amount": 12.34}`, want: 123400},
+		{json: `{"amount": "12.34"}`, want: 123400},
+		{json: `{"amount": -0.5}`, want: -5000},
+		{json: `{"amount": "1e2"}`, want: 1000000},
+		{json: `{"amount": null}`, want: 0},
+		{json: `{"amount": 0.12345}`, wantErr: true},
+		{json: `{"amount": "abc"}`, wantErr: true},
+		{json: `{"amount": true}`, wantErr: true},
+	} {
+		body.Amount = 0
+		err := json.Unmarshal([]byte(tt.json), &body)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Unmarshal(%s) error = %v, wantErr %v", tt.json, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && body.Amount != tt.want {
+			t.Errorf("Unmarshal(%s) = %d, want %d", tt.json, body.Amount, tt.want)
+		}
+	}
+
+	data, err := json.Marshal(struct {
+		Amount Money `json:"amount"`
+	}{Amount: 123450})
+	if err != nil || string(data) != `{"amount":12.345}` {
+		t.Errorf("Marshal() = %s, %v, want a plain number", data, err)
+	}
+}
+
+func TestMoneyScan(t *testing.T) {
+	tests := []struct {
+		src     any
+		want    Money
+		wantErr bool
+	}{
+		{src: nil, want: 0},
+		{src: int64(12), want: 120000},
+		{src: []byte("12.3400"), want: 123400},
+		{src: "-7.5", want: -75000},
+		{src: []byte("33.3333333333333333"), want: 333333}, // AVG CARRIES MORE DIGITS
+		{src: []byte("66.66666666666666667"), want: 666667},
+		{src: []byte("-0.00005"), want: -1},
+		{src: 0.1, want: 1000},
+		{src: []byte("not a number"), wantErr: true},
+		{src: true, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		var got Money = 99
+		err := got.Scan(tt.src)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("Scan(%v) error = %v, wantErr %v", tt.src, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && got != tt.want {
+			t.Errorf("Scan(%v) = %d, want %d", tt.src, got, tt.want)
+		}
+	}
+
+	value, err := Money(-12350).Value()
+	if err != nil || value != "-1.2350" {
+		t.Errorf("Value() = %v, %v, want -1.2350", value, err)
+	}
+}
@@ -0,0 +1,57 @@
+package models
+
+import "time"
+
+const (
+	PayeeMatchExact    = "exact"
+	PayeeMatchPrefix   = "prefix"
+	PayeeMatchContains = "contains"
+	PayeeMatchRegex    = "regex"
+)
+
+// A CANONICAL MERCHANT OF THE USER, RAW EXPENSE NAMES ARE MAPPED TO IT BY ITS ALIASES
+type Payee struct {
+	ID        uint         `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint         `json:"-" gorm:"not null;uniqueIndex:idx_payees_user_name"`
+	Name      string       `json:"name" gorm:"size:100;not null;uniqueIndex:idx_payees_user_name"`
+	Aliases   []PayeeAlias `json:"aliases,omitempty" gorm:"foreignKey:PayeeID"`
+	CreatedAt time.Time    `json:"created_at"`
+	UpdatedAt time.Time    `json:"updated_at"`
+}
+
+// A RULE MATCHING RAW EXPENSE NAMES CASE INSENSITIVELY, e.g. prefix "GRAB*"
+type PayeeAlias struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	PayeeID   uint      `json:"-" gorm:"not null;index"`
+	MatchType string    `json:"match_type" gorm:"size:10;not null"`
+	Pattern   string    `json:"pattern" gorm:"size:255;not null"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type PayeeRequest struct {
+	Name    string              `json:"name" validate:"required,min=1,max=100" example:"Grab"`
+	Aliases []PayeeAliasRequest `json:"aliases" validate:"omitempty,max=50,dive"`
+}
+
+type PayeeAliasRequest struct {
+	MatchType string `json:"match_type" validate:"required,oneof=exact prefix contains regex" example:"prefix"`
+	Pattern   string `json:"pattern" validate:"required,max=255" example:"GRAB*"`
+}
+
+// TOTAL SPENT PER PAYEE, EXPENSES WITHOUT A PAYEE ARE GROUPED BY THEIR RAW NAME WITH A NULL PAYEE ID
+type PayeeSummary struct {
+	PayeeID   *uint   `json:"payee_id"`
+	PayeeName string  `json:"payee_name"`
+	Count     int64   `json:"count"`
+	Total     Money   `json:"total" swaggertype:"number"`
+	Share     float64 `json:"share"` // PERCENTAGE OF THE TOTAL OF THE SAME CATEGORY TYPE IN THE PERIOD
+}
+
+type PayeeReport struct {
+	From     time.Time      `json:"from"`
+	To       time.Time      `json:"to"` // EXCLUSIVE
+	Type     string         `json:"type"`
+	Currency string         `json:"currency"`
+	Total    Money          `json:"total" swaggertype:"number"`
+	Payees   []PayeeSummary `json:"payees"`
+}
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const (
+	FrequencyDaily   = "daily"
+	FrequencyWeekly  = "weekly"
+	FrequencyMonthly = "monthly"
+	FrequencyYearly  = "yearly"
+)
+
+type RecurringExpense struct {
+	ID              uint       `json:"id" gorm:"primaryKey;autoIncrement"`
+	Name            string     `json:"name" gorm:"not null"`
+	Amount          Money      `json:"amount" swaggertype:"number" example:"1500000"`
+	Currency        string     `json:"currency" gorm:"size:3;not null;default:'IDR'"`
+	UserID          uint       `json:"-" gorm:"index"`
+	CategoryID      uint       `json:"-"`
+	Frequency       string     `json:"frequency" gorm:"not null"`
+	Interval        int        `json:"interval" gorm:"not null;default:1"` // EVERY N DAYS/WEEKS/MONTHS/YEARS
+	StartDate       time.Time  `json:"start_date" gorm:"not null"`
+	EndDate         *time.Time `json:"end_date"`
+	NextRunAt       *time.Time `json:"next_run_at" gorm:"index"` // NULL ONCE THE END DATE HAS PASSED
+	OccurrenceCount int        `json:"occurrence_count" gorm:"not null;default:0"`
+	IsActive        bool       `json:"is_active" gorm:"not null;default:true"`
+
+	// RELATIONSHIPS
+	Category Category `json:"category" gorm:"foreignKey:CategoryID;references:ID"`
+
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+}
+
+type RecurringExpenseRequest struct {
+	Name       string `json:"name" validate:"required"`
+	Amount     Money  `json:"amount" validate:"required,gt=0" swaggertype:"number" example:"1500000"`
+	Currency   string `json:"currency" validate:"omitempty,len=3,alpha" example:"IDR"` // DEFAULTS TO THE USER BASE CURRENCY
+	CategoryID uint   `json:"category_id" validate:"required"`
+	Frequency  string `json:"frequency" validate:"required,oneof=daily weekly monthly yearly" example:"monthly"`
+	Interval   int    `json:"interval" validate:"omitempty,min=1,max=365" example:"1"` // DEFAULTS TO 1
+	StartDate  string `json:"start_date" validate:"required" example:"2025-01-01"`
+	EndDate    string `json:"end_date" example:"2025-12-31"`
+	IsActive   *bool  `json:"is_active"` // DEFAULTS TO TRUE, RESUMING SKIPS THE OCCURRENCES MISSED WHILE PAUSED
+}
@@ -0,0 +1,61 @@
+package models
+
+import "time"
+
+// TOTALS OF ONE CATEGORY IN A REPORT PERIOD, AMOUNTS IN THE USER BASE CURRENCY
+type CategorySummary struct {
+	CategoryID   uint    `json:"category_id"`
+	CategoryName string  `json:"category_name"`
+	CategoryType string  `json:"category_type"`
+	Count        int64   `json:"count"`
+	Total        Money   `json:"total" swaggertype:"number"`
+	Average      Money   `json:"average" swaggertype:"number"`
+	Min          Money   `json:"min" swaggertype:"number"`
+	Max          Money   `json:"max" swaggertype:"number"`
+	Share        float64 `json:"share"` // PERCENTAGE OF THE INCOME OR EXPENSE TOTAL
+}
+
+type SummaryReport struct {
+	From         time.Time         `json:"from"`
+	To           time.Time         `json:"to"` // EXCLUSIVE
+	Currency     string            `json:"currency"`
+	TotalIncome  Money             `json:"total_income" swaggertype:"number"`
+	TotalExpense Money             `json:"total_expense" swaggertype:"number"`
+	NetCashFlow  Money             `json:"net_cash_flow" swaggertype:"number"`
+	Income       []CategorySummary `json:"income"`
+	Expense      []CategorySummary `json:"expense"`
+}
+
+const (
+	TrendIntervalDay   = "day"
+	TrendIntervalWeek  = "week"
+	TrendIntervalMonth = "month"
+	TrendIntervalYear  = "year"
+)
+
+// TOTAL OF ONE CATEGORY IN ONE TREND BUCKET
+type TrendCategoryTotal struct {
+	Bucket       time.Time `json:"-"`
+	CategoryID   uint      `json:"category_id"`
+	CategoryName string    `json:"category_name"`
+	CategoryType string    `json:"category_type"`
+	Count        int64     `json:"count"`
+	Total        Money     `json:"total" swaggertype:"number"`
+}
+
+type TrendBucket struct {
+	Start      time.Time            `json:"start"`
+	Income     Money                `json:"income" swaggertype:"number"`
+	Expense    Money                `json:"expense" swaggertype:"number"`
+	Net        Money                `json:"net" swaggertype:"number"`
+	Categories []TrendCategoryTotal `json:"categories,omitempty"` // ONLY WITH group_by=category
+}
+
+type TrendReport struct {
+	Interval string        `json:"interval"`
+	From     time.Time     `json:"from"`
+	To       time.Time     `json:"to"` // EXCLUSIVE
+	Timezone string        `json:"timezone"`
+	Currency string        `json:"currency"`
+	Buckets  []TrendBucket `json:"buckets"`
+}
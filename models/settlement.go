@@ -0,0 +1,52 @@
+package models
+
+import "time"
+
+// A PAYMENT BETWEEN TWO LEDGER MEMBERS THAT SETTLES SHARED EXPENSES, IN THE LEDGER BASE CURRENCY
+type Settlement struct {
+	ID          uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	LedgerID    uint      `json:"ledger_id" gorm:"not null;index"`
+	FromUserID  uint      `json:"from_user_id" gorm:"not null;index"`
+	ToUserID    uint      `json:"to_user_id" gorm:"not null;index"`
+	Amount      Money     `json:"amount" swaggertype:"number" example:"25"`
+	Note        string    `json:"note" gorm:"size:255"`
+	SettledAt   time.Time `json:"settled_at" gorm:"index"`
+	CreatedByID uint      `json:"created_by_id" gorm:"not null"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+type SettlementRequest struct {
+	FromUserID uint   `json:"from_user_id" validate:"required"`
+	ToUserID   uint   `json:"to_user_id" validate:"required,nefield=FromUserID"`
+	Amount     Money  `json:"amount" validate:"required,gt=0" swaggertype:"number" example:"25"` // IN THE LEDGER BASE CURRENCY
+	Note       string `json:"note" validate:"max=255"`
+	SettledAt  string `json:"settled_at" example:"2025-01-31"` // YYYY-MM-DD OR RFC3339, DEFAULTS TO NOW
+}
+
+// POSITION OF A MEMBER IN THE SHARED EXPENSES OF A LEDGER, IN THE LEDGER BASE CURRENCY
+// Balance IS Paid - Owed + SettlementsPaid - SettlementsReceived, POSITIVE WHEN THE OTHER MEMBERS OWE THEM
+type LedgerMemberBalance struct {
+	UserID              uint   `json:"user_id"`
+	Name                string `json:"name"`
+	Email               string `json:"email"`
+	Paid                Money  `json:"paid" swaggertype:"number"`
+	Owed                Money  `json:"owed" swaggertype:"number"`
+	SettlementsPaid     Money  `json:"settlements_paid" swaggertype:"number"`
+	SettlementsReceived Money  `json:"settlements_received" swaggertype:"number"`
+	Balance             Money  `json:"balance" swaggertype:"number"`
+}
+
+// A PAYMENT THAT SETTLES OPEN BALANCES, RECORD IT AS A SETTLEMENT ONCE PAID
+type SettleUpPayment struct {
+	FromUserID uint  `json:"from_user_id"`
+	ToUserID   uint  `json:"to_user_id"`
+	Amount     Money `json:"amount" swaggertype:"number"`
+}
+
+type LedgerBalances struct {
+	LedgerID uint                  `json:"ledger_id"`
+	Currency string                `json:"currency"`
+	Members  []LedgerMemberBalance `json:"members"`
+	SettleUp []SettleUpPayment     `json:"settle_up"` // FEWEST PAYMENTS THAT BRING EVERY BALANCE TO ZERO
+}
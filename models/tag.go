@@ -0,0 +1,41 @@
+package models
+
+import (
+	"strings"
+	"time"
+)
+
+// A FREE LABEL OF THE USER, EXPENSES CAN CARRY ANY NUMBER OF TAGS
+type Tag struct {
+	ID        uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID    uint      `json:"-" gorm:"not null;uniqueIndex:idx_tags_user_name"`
+	Name      string    `json:"name" gorm:"size:50;not null;uniqueIndex:idx_tags_user_name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+type TagRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=50,excludesall=0x2C" example:"trip-bali"`
+}
+
+// PER TAG TOTALS, AN EXPENSE WITH SEVERAL TAGS COUNTS UNDER EACH OF THEM
+type TagSummary struct {
+	TagID   uint   `json:"tag_id"`
+	TagName string `json:"tag_name"`
+	Count   int64  `json:"count"`
+	Income  Money  `json:"income" swaggertype:"number"`
+	Expense Money  `json:"expense" swaggertype:"number"`
+	Net     Money  `json:"net" swaggertype:"number"`
+}
+
+type TagReport struct {
+	From     time.Time    `json:"from"`
+	To       time.Time    `json:"to"` // EXCLUSIVE
+	Currency string       `json:"currency"`
+	Tags     []TagSummary `json:"tags"`
+}
+
+// TAG NAMES ARE TRIMMED AND LOWERCASE, SO "Business" AND "business " ARE THE SAME TAG
+func NormalizeTagName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
@@ -0,0 +1,32 @@
+package models
+
+import "time"
+
+// MONEY MOVED BETWEEN TWO ACCOUNTS OF THE USER, IT CHANGES THEIR BALANCES BUT IS NEITHER SPENDING NOR INCOME
+type Transfer struct {
+	ID            uint      `json:"id" gorm:"primaryKey;autoIncrement"`
+	UserID        uint      `json:"-" gorm:"not null;index"`
+	FromAccountID uint      `json:"from_account_id" gorm:"not null;index"`
+	ToAccountID   uint      `json:"to_account_id" gorm:"not null;index"`
+	Amount        Money     `json:"amount" swaggertype:"number" example:"100"`        // IN THE SOURCE ACCOUNT CURRENCY
+	ToAmount      Money     `json:"to_amount" swaggertype:"number" example:"1580000"` // IN THE DESTINATION ACCOUNT CURRENCY
+	Fee           Money     `json:"fee" swaggertype:"number" example:"2.5"`           // IN THE SOURCE ACCOUNT CURRENCY, CHARGED ON TOP OF Amount
+	Note          string    `json:"note" gorm:"size:255"`
+	TransferredAt time.Time `json:"transferred_at" gorm:"index"`
+	CreatedAt     time.Time `json:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at"`
+
+	// RELATIONSHIPS
+	FromAccount Account `json:"from_account" gorm:"foreignKey:FromAccountID;references:ID"`
+	ToAccount   Account `json:"to_account" gorm:"foreignKey:ToAccountID;references:ID"`
+}
+
+type TransferRequest struct {
+	FromAccountID uint   `json:"from_account_id" validate:"required"`
+	ToAccountID   uint   `json:"to_account_id" validate:"required,nefield=FromAccountID"`
+	Amount        Money  `json:"amount" validate:"required,gt=0" swaggertype:"number" example:"100"`
+	ToAmount      Money  `json:"to_amount" validate:"gte=0" swaggertype:"number" example:"1580000"` // REQUIRED WHEN THE ACCOUNT CURRENCIES DIFFER, OTHERWISE EQUAL TO amount
+	Fee           Money  `json:"fee" validate:"gte=0" swaggertype:"number" example:"2.5"`
+	Note          string `json:"note" validate:"max=255"`
+	TransferredAt string `json:"transferred_at" example:"2025-01-31"` // YYYY-MM-DD OR RFC3339, DEFAULTS TO NOW
+}
@@ -7,13 +7,15 @@ import (
 )
 
 type User struct {
-	ID        uint           `json:"id" gorm:"primaryKey;autoIncrement"`
-	Email     string         `json:"email" gorm:"uniqueIndex;not null" validate:"required,email"`
-	Name      string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
-	Password  string         `json:"-" gorm:"not null" validate:"required,min=6"`
-	CreatedAt time.Time      `json:"created_at"`
-	UpdatedAt time.Time      `json:"updated_at"`
-	DeletedAt gorm.DeletedAt `json:"-" gorm:"index"`
+	ID           uint           `json:"id" gorm:"primaryKey;autoIncrement"`
+	Email        string         `json:"email" gorm:"uniqueIndex;not null" validate:"required,email"`
+	Name         string         `json:"name" gorm:"not null" validate:"required,min=2,max=100"`
+	Password     string         `json:"-" gorm:"not null" validate:"required,min=6"`
+	BaseCurrency string         `json:"base_currency" gorm:"size:3;not null;default:'IDR'"`
+	Timezone     string         `json:"timezone" gorm:"size:64;not null;default:'UTC'"`
+	CreatedAt    time.Time      `json:"created_at"`
+	UpdatedAt    time.Time      `json:"updated_at"`
+	DeletedAt    gorm.DeletedAt `json:"-" gorm:"index"`
 
 	// RELATIONSHIPS
 	Categories []Category `json:"categories" gorm:"foreignKey:UserID"`
@@ -36,6 +38,10 @@ type RegisterRequest struct {
 	Name     string `json:"name" validate:"required,min=2,max=100"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
+	// OPTIONAL, DEFAULTS TO IDR
+	BaseCurrency string `json:"base_currency" validate:"omitempty,len=3,alpha" example:"IDR"`
+	// OPTIONAL IANA TIMEZONE, DEFAULTS TO UTC
+	Timezone string `json:"timezone" validate:"omitempty,timezone" example:"Asia/Jakarta"`
 }
 
 // REGISTER RESPONSE
@@ -58,11 +64,33 @@ type RefreshTokenResponse struct {
 
 // USER RESPONSE
 type UserResponse struct {
-	ID        uint      `json:"id"`
-	Email     string    `json:"email"`
-	Name      string    `json:"name"`
-	CreatedAt time.Time `json:"created_at"`
+	ID           uint      `json:"id"`
+	Email        string    `json:"email"`
+	Name         string    `json:"name"`
+	BaseCurrency string    `json:"base_currency"`
+	Timezone     string    `json:"timezone"`
+	CreatedAt    time.Time `json:"created_at"`
 
 	// RELATIONSHIPS
 	Categories []*Category `json:"categories,omitempty"`
 }
+
+// USER SETTINGS REQUEST PAYLOAD
+type UserSettingsRequest struct {
+	BaseCurrency string `json:"base_currency" validate:"omitempty,len=3,alpha" example:"IDR"`
+	Timezone     string `json:"timezone" validate:"omitempty,timezone" example:"Asia/Jakarta"`
+}
+
+// TIMEZONE USED FOR DATES WITHOUT A TIME AND FOR REPORT BUCKETS, UTC IF UNSET OR UNKNOWN
+func (u *User) Location() *time.Location {
+	if u.Timezone == "" {
+		return time.UTC
+	}
+
+	loc, err := time.LoadLocation(u.Timezone)
+	if err != nil {
+		return time.UTC
+	}
+
+	return loc
+}
@@ -0,0 +1,170 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AccountRepository struct {
+	db *gorm.DB
+}
+
+func NewAccountRepository(db *gorm.DB) *AccountRepository {
+	return &AccountRepository{db: db}
+}
+
+func (r *AccountRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Account, int64, int64, error) {
+	var accounts []models.Account
+	var total int64
+
+	query := r.db.Model(&models.Account{}).Where("user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "name":
+			query = query.Where("name ILIKE ?", "%"+value+"%")
+		case "type":
+			query = query.Where("type = ?", value)
+		case "currency":
+			query = query.Where("currency = ?", models.NormalizeCurrency(value))
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Limit(queryParams.Limit).Offset(offset).Find(&accounts).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &accounts, total, totalPages, nil
+}
+
+func (r *AccountRepository) GetByID(id uint) (*models.Account, error) {
+	var account models.Account
+
+	err := r.db.Where("id = ?", id).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *AccountRepository) GetByName(userID uint, name string) (*models.Account, error) {
+	var account models.Account
+
+	err := r.db.Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).First(&account).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &account, nil
+}
+
+func (r *AccountRepository) Create(account *models.Account) error {
+	return r.db.Create(account).Error
+}
+
+func (r *AccountRepository) Update(account *models.Account) error {
+	return r.db.Save(account).Error
+}
+
+// DELETE AN ACCOUNT, ITS EXPENSES AND GOALS ARE KEPT WITHOUT AN ACCOUNT
+func (r *AccountRepository) Delete(account *models.Account) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Expense{}).Where("account_id = ?", account.ID).Update("account_id", nil).Error; err != nil {
+			return err
+		}
+		if err := tx.Model(&models.Goal{}).Where("account_id = ?", account.ID).Update("account_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(account).Error
+	})
+}
+
+// NUMBER OF EXPENSES BOOKED ON THE ACCOUNT, DELETED ONES INCLUDED AS THEY CAN BE RESTORED
+func (r *AccountRepository) CountExpenses(accountID uint) (int64, error) {
+	var count int64
+
+	err := r.db.Unscoped().Model(&models.Expense{}).Where("account_id = ?", accountID).Count(&count).Error
+
+	return count, err
+}
+
+// NUMBER OF TRANSFERS FROM OR TO THE ACCOUNT
+func (r *AccountRepository) CountTransfers(accountID uint) (int64, error) {
+	var count int64
+
+	err := r.db.Model(&models.Transfer{}).Where("from_account_id = ? OR to_account_id = ?", accountID, accountID).Count(&count).Error
+
+	return count, err
+}
+
+// BALANCES OF THE USER ACCOUNTS FROM THEIR EXPENSES AND TRANSFERS BEFORE asOf, accountID 0 MEANS ALL ACCOUNTS
+// AMOUNTS ARE SUMMED IN THE ACCOUNT CURRENCY, INCOME CATEGORIES ADD TO THE BALANCE AND EXPENSE CATEGORIES SUBTRACT
+func (r *AccountRepository) Balances(userID, accountID uint, asOf time.Time) ([]models.AccountBalance, error) {
+	var balances []models.AccountBalance
+
+	query := r.db.Table("accounts").
+		Select(`accounts.id AS account_id,
+			accounts.name,
+			accounts.type,
+			accounts.currency,
+			accounts.opening_balance,
+			COUNT(expenses.id) AS count,
+			COALESCE(SUM(expenses.amount) FILTER (WHERE categories.type = 'income'), 0) AS income,
+			COALESCE(SUM(expenses.amount) FILTER (WHERE categories.type <> 'income'), 0) AS expense,
+			(SELECT COALESCE(SUM(transfers.to_amount), 0) FROM transfers WHERE transfers.to_account_id = accounts.id AND transfers.transferred_at < ?) AS transfers_in,
+			(SELECT COALESCE(SUM(transfers.amount + transfers.fee), 0) FROM transfers WHERE transfers.from_account_id = accounts.id AND transfers.transferred_at < ?) AS transfers_out`, asOf, asOf).
+		Joins("LEFT JOIN expenses ON expenses.account_id = accounts.id AND expenses.spent_at < ? AND expenses.deleted_at IS NULL", asOf).
+		Joins("LEFT JOIN categories ON categories.id = expenses.category_id").
+		Where("accounts.user_id = ?", userID)
+
+	if accountID != 0 {
+		query = query.Where("accounts.id = ?", accountID)
+	}
+
+	err := query.
+		Group("accounts.id, accounts.name, accounts.type, accounts.currency, accounts.opening_balance").
+		Order("accounts.id").
+		Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range balances {
+		balances[i].Balance = balances[i].OpeningBalance + balances[i].Income - balances[i].Expense + balances[i].TransfersIn - balances[i].TransfersOut
+		balances[i].AsOf = asOf
+	}
+
+	return balances, nil
+}
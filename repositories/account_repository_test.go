@@ -0,0 +1,107 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+// CREATE AN ACCOUNT OF THE USER
+func createTestAccount(t *testing.T, repo *AccountRepository, userID uint, name, currency, openingBalance string) *models.Account {
+	t.Helper()
+
+	account := &models.Account{UserID: userID, Name: name, Type: models.AccountTypeBank, Currency: currency, OpeningBalance: mustMoney(t, openingBalance)}
+	if err := repo.Create(account); err != nil {
+		t.Fatalf("failed to create account: %v", err)
+	}
+
+	return account
+}
+
+func TestBalances(t *testing.T) {
+	db := testDB(t)
+	repo := NewAccountRepository(db)
+	expenseRepo := NewExpenseRepository(db)
+	user, groceries := createTestUser(t, expenseRepo, "EUR")
+
+	salary := &models.Category{Name: "Salary", Type: "income", UserID: &user.ID}
+	if err := db.Create(salary).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	bank := createTestAccount(t, repo, user.ID, "Bank", "EUR", "1000")
+	wallet := createTestAccount(t, repo, user.ID, "Wallet", "EUR", "50")
+
+	asOf := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	expenses := []struct {
+		name     string
+		amount   string
+		account  *models.Account
+		category *models.Category
+		spentAt  time.Time
+		deleted  bool
+	}{
+		{name: "Salary", amount: "2000", account: bank, category: salary, spentAt: asOf.AddDate(0, 0, -10)},
+		{name: "Market", amount: "120.5", account: bank, category: groceries, spentAt: asOf.AddDate(0, 0, -5)},
+		{name: "Bakery", amount: "4.5", account: wallet, category: groceries, spentAt: asOf.AddDate(0, 0, -5)},
+		// AT asOf IS ALREADY AFTER THE BALANCE
+		{name: "Rent", amount: "800", account: bank, category: groceries, spentAt: asOf},
+		// IN THE TRASH
+		{name: "Deleted", amount: "30", account: bank, category: groceries, spentAt: asOf.AddDate(0, 0, -1), deleted: true},
+	}
+	for _, e := range expenses {
+		expense := &models.Expense{
+			Name:         e.name,
+			Amount:       mustMoney(t, e.amount),
+			Currency:     "EUR",
+			BaseAmount:   mustMoney(t, e.amount),
+			BaseCurrency: "EUR",
+			SpentAt:      e.spentAt,
+			UserID:       user.ID,
+			CategoryID:   e.category.ID,
+			AccountID:    &e.account.ID,
+		}
+		if err := db.Omit("Category").Create(expense).Error; err != nil {
+			t.Fatalf("failed to create expense: %v", err)
+		}
+		if e.deleted {
+			if err := db.Delete(expense).Error; err != nil {
+				t.Fatalf("failed to delete expense: %v", err)
+			}
+		}
+	}
+
+	balances, err := repo.Balances(user.ID, 0, asOf)
+	if err != nil {
+		t.Fatalf("Balances: %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("got %d balances, want 2: %+v", len(balances), balances)
+	}
+
+	want := []struct {
+		accountID uint
+		income    string
+		expense   string
+		balance   string
+		count     int64
+	}{
+		{accountID: bank.ID, income: "2000", expense: "120.5", balance: "2879.5", count: 2},
+		{accountID: wallet.ID, income: "0", expense: "4.5", balance: "45.5", count: 1},
+	}
+	for i, w := range want {
+		got := balances[i]
+		if got.AccountID != w.accountID || got.Income != mustMoney(t, w.income) || got.Expense != mustMoney(t, w.expense) || got.Balance != mustMoney(t, w.balance) || got.Count != w.count {
+			t.Errorf("balance %d = %+v, want %+v", i, got, w)
+		}
+	}
+
+	one, err := repo.Balances(user.ID, wallet.ID, asOf)
+	if err != nil {
+		t.Fatalf("Balances: %v", err)
+	}
+	if len(one) != 1 || one[0].AccountID != wallet.ID {
+		t.Errorf("balances of the wallet = %+v, want only the wallet", one)
+	}
+}
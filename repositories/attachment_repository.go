@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/models"
+
+	"gorm.io/gorm"
+)
+
+type AttachmentRepository struct {
+	db *gorm.DB
+}
+
+func NewAttachmentRepository(db *gorm.DB) *AttachmentRepository {
+	return &AttachmentRepository{db: db}
+}
+
+func (r *AttachmentRepository) Create(attachment *models.Attachment) error {
+	return r.db.Create(attachment).Error
+}
+
+func (r *AttachmentRepository) GetByExpenseID(expenseID uint) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+
+	err := r.db.Where("expense_id = ?", expenseID).Order("id").Find(&attachments).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return attachments, nil
+}
+
+func (r *AttachmentRepository) GetByID(id uint) (*models.Attachment, error) {
+	var attachment models.Attachment
+
+	err := r.db.Where("id = ?", id).First(&attachment).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &attachment, nil
+}
+
+func (r *AttachmentRepository) Delete(attachment *models.Attachment) error {
+	return r.db.Delete(attachment).Error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type BudgetAlertRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetAlertRepository(db *gorm.DB) *BudgetAlertRepository {
+	return &BudgetAlertRepository{db: db}
+}
+
+// INSERT THE ALERT UNLESS THE SAME BUDGET, THRESHOLD AND PERIOD ALREADY FIRED, REPORTS WHETHER IT WAS INSERTED
+func (r *BudgetAlertRepository) CreateIfAbsent(alert *models.BudgetAlert) (bool, error) {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(alert)
+	if result.Error != nil {
+		return false, result.Error
+	}
+	return result.RowsAffected > 0, nil
+}
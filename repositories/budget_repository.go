@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type BudgetRepository struct {
+	db *gorm.DB
+}
+
+func NewBudgetRepository(db *gorm.DB) *BudgetRepository {
+	return &BudgetRepository{db: db}
+}
+
+func (r *BudgetRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Budget, int64, int64, error) {
+	var budgets []models.Budget
+	var total int64
+
+	query := r.db.Model(&models.Budget{}).Where("budgets.user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "period":
+			query = query.Where("budgets.period = ?", value)
+		case "category_name":
+			query = query.Where("budgets.category_id IN (?)", categoryIDsNamed(r.db, userID, value))
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order("budgets." + queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Preload("Category", preloadEffectiveCategory(r.db, userID)).Limit(queryParams.Limit).Offset(offset).Find(&budgets).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &budgets, total, totalPages, nil
+}
+
+func (r *BudgetRepository) GetAllByUserID(userID uint) ([]models.Budget, error) {
+	var budgets []models.Budget
+
+	err := r.db.Preload("Category", preloadEffectiveCategory(r.db, userID)).Where("user_id = ?", userID).Order("id").Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
+
+// A BUDGET BY ID, ITS CATEGORY NAMED AS userID SEES IT
+func (r *BudgetRepository) GetByID(id, userID uint) (*models.Budget, error) {
+	var budget models.Budget
+
+	err := r.db.Preload("Category", preloadEffectiveCategory(r.db, userID)).Where("id = ?", id).First(&budget).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &budget, nil
+}
+
+func (r *BudgetRepository) Create(budget *models.Budget) error {
+	return r.db.Omit("Category").Create(budget).Error
+}
+
+func (r *BudgetRepository) Update(budget *models.Budget) error {
+	return r.db.Omit("Category").Save(budget).Error
+}
+
+func (r *BudgetRepository) Delete(budget *models.Budget) error {
+	return r.db.Delete(budget).Error
+}
+
+// BUDGETS OF THE USER ON THE GIVEN CATEGORIES OR ON ANY OF THEIR PARENTS, WHICH COVER THEIR SUBCATEGORIES
+func (r *BudgetRepository) GetByCategoryIDs(userID uint, categoryIDs []uint) ([]models.Budget, error) {
+	var budgets []models.Budget
+
+	err := r.db.Preload("Category", preloadEffectiveCategory(r.db, userID)).Where("user_id = ? AND category_id IN ("+categoryAncestors+")", userID, categoryIDs).Find(&budgets).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return budgets, nil
+}
@@ -0,0 +1,129 @@
+package repositories
+
+import (
+	"fmt"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strconv"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type CategorizationRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewCategorizationRuleRepository(db *gorm.DB) *CategorizationRuleRepository {
+	return &CategorizationRuleRepository{db: db}
+}
+
+func (r *CategorizationRuleRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.CategorizationRule, int64, int64, error) {
+	var rules []models.CategorizationRule
+	var total int64
+
+	query := r.db.Model(&models.CategorizationRule{}).Where("user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "name":
+			query = query.Where("name ILIKE ?", "%"+value+"%")
+		case "category_id":
+			categoryID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: category_id must be a number", ErrInvalidFilter)
+			}
+			query = query.Where("category_id = ?", categoryID)
+		case "is_active":
+			isActive, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: is_active must be true or false", ErrInvalidFilter)
+			}
+			query = query.Where("is_active = ?", isActive)
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Preload("Category", preloadEffectiveCategory(r.db, userID)).Preload("Payee").Preload("Tags").Limit(queryParams.Limit).Offset(offset).Find(&rules).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &rules, total, totalPages, nil
+}
+
+// ACTIVE RULES OF THE USER IN THE ORDER THEY ARE TRIED, LOWEST PRIORITY FIRST
+func (r *CategorizationRuleRepository) GetActiveByUserID(userID uint) ([]models.CategorizationRule, error) {
+	var rules []models.CategorizationRule
+
+	err := r.db.Preload("Category", preloadEffectiveCategory(r.db, userID)).Preload("Tags").
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Order("priority, id").
+		Find(&rules).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// A RULE BY ID, ITS CATEGORY NAMED AS userID SEES IT
+func (r *CategorizationRuleRepository) GetByID(id, userID uint) (*models.CategorizationRule, error) {
+	var rule models.CategorizationRule
+
+	err := r.db.Preload("Category", preloadEffectiveCategory(r.db, userID)).Preload("Payee").Preload("Tags").Where("id = ?", id).First(&rule).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &rule, nil
+}
+
+func (r *CategorizationRuleRepository) Create(rule *models.CategorizationRule) error {
+	return r.db.Omit("Category", "Payee").Create(rule).Error
+}
+
+// SAVE THE RULE AND REPLACE ITS TAGS WITH rule.Tags
+func (r *CategorizationRuleRepository) Update(rule *models.CategorizationRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category", "Payee", "Tags").Save(rule).Error; err != nil {
+			return err
+		}
+
+		return tx.Model(rule).Association("Tags").Replace(rule.Tags)
+	})
+}
+
+func (r *CategorizationRuleRepository) Delete(rule *models.CategorizationRule) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM categorization_rule_tags WHERE categorization_rule_id = ?", rule.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(rule).Error
+	})
+}
@@ -1,13 +1,40 @@
 package repositories
 
 import (
+	"database/sql"
+	"fmt"
 	"go-expense-tracker-api/middleware"
 	"go-expense-tracker-api/models"
+	"strconv"
 	"strings"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
+// IDS OF A CATEGORY AND ALL OF ITS SUBCATEGORIES, TO BE USED IN AN IN (...) WITH THE CATEGORY ID AS ARGUMENT
+// A DEFAULT CATEGORY HAS THE SUBCATEGORIES OF EVERY USER AND LEDGER, QUERIES USING IT ARE SCOPED BY THEIR EXPENSES OR BUDGETS
+const categoryDescendants = `WITH RECURSIVE descendants AS (
+		SELECT id FROM categories WHERE id = ?
+		UNION
+		SELECT categories.id FROM categories JOIN descendants ON categories.parent_id = descendants.id
+	) SELECT id FROM descendants`
+
+// IDS OF THE CATEGORIES IN A LIST AND ALL OF THEIR PARENTS, TO BE USED IN AN IN (...) WITH THE ID LIST AS ARGUMENT
+const categoryAncestors = `WITH RECURSIVE ancestors AS (
+		SELECT id, parent_id FROM categories WHERE id IN ?
+		UNION
+		SELECT categories.id, categories.parent_id FROM categories JOIN ancestors ON categories.id = ancestors.parent_id
+	) SELECT id FROM ancestors`
+
+// EVERY CATEGORY ID WITH THE ID OF ITS TOP LEVEL PARENT, ITS OWN ID FOR A TOP LEVEL CATEGORY
+// DELETED CATEGORIES ARE INCLUDED SO EXPENSES STILL ON THEM ROLL UP TOO
+const categoryRoots = `(WITH RECURSIVE roots AS (
+		SELECT id, id AS root_id FROM categories WHERE parent_id IS NULL
+		UNION
+		SELECT categories.id, roots.root_id FROM categories JOIN roots ON categories.parent_id = roots.id
+	) SELECT id, root_id FROM roots) AS category_roots`
+
 type CategoryRepository struct {
 	db *gorm.DB
 }
@@ -16,10 +43,11 @@ func NewCategoryRepository(db *gorm.DB) *CategoryRepository {
 	return &CategoryRepository{db: db}
 }
 
-func (r *CategoryRepository) GetDefaultCategories() (*[]models.Category, error) {
+// DEFAULT CATEGORIES WITH THE OVERRIDES OF THE USER, HIDDEN ONES INCLUDED
+func (r *CategoryRepository) GetDefaultCategories(userID uint) (*[]models.Category, error) {
 	var defaultCategories []models.Category
 
-	err := r.db.Where("is_default = ? AND user_id IS NULL", true).Find(&defaultCategories).Error
+	err := r.effective(userID).Where("is_default = ? AND user_id IS NULL", true).Order("id").Find(&defaultCategories).Error
 	if err != nil {
 		return nil, err
 	}
@@ -31,16 +59,33 @@ func (r *CategoryRepository) CreateMany(categories []*models.Category) error {
 	return r.db.Create(categories).Error
 }
 
+// CATEGORIES OF THE LEDGER IN queryParams, OR THE PERSONAL CATEGORIES OF THE USER, WITH THE DEFAULT CATEGORIES THE USER DID NOT HIDE
 func (r *CategoryRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Category, int64, int64, error) {
 	var categories []models.Category
 	var total int64
 
-	query := r.db.Model(&models.Category{}).Where("user_id = ?", userID)
+	query := scopeCategories(r.effective(userID), userID, queryParams.LedgerID).Where("NOT hidden")
 
 	// APPLY FILTERS
 	for key, value := range queryParams.Filters {
 		if value != "" {
-			query = query.Where(key+" ILIKE ?", "%"+value+"%")
+			switch key {
+			// 0 KEEPS THE TOP LEVEL CATEGORIES
+			case "parent_id":
+				parentID, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, 0, 0, fmt.Errorf("%w: parent_id must be a number", ErrInvalidFilter)
+				}
+				if parentID == 0 {
+					query = query.Where("parent_id IS NULL")
+				} else {
+					query = query.Where("parent_id = ?", parentID)
+				}
+			case "view":
+				// HANDLED BY THE HANDLER
+			default:
+				query = query.Where(key+" ILIKE ?", "%"+value+"%")
+			}
 		}
 	}
 
@@ -88,6 +133,216 @@ func (r *CategoryRepository) Update(category *models.Category) error {
 	return r.db.Save(category).Error
 }
 
-func (r *CategoryRepository) Delete(categoryID uint) error {
-	return r.db.Delete(&models.Category{}, categoryID).Error
+// EVERY CATEGORY OF THE LEDGER, OR EVERY PERSONAL CATEGORY OF THE USER WHEN ledgerID IS NIL, BY NAME
+// WITH THE DEFAULT CATEGORIES THE USER DID NOT HIDE
+func (r *CategoryRepository) GetAllByUserID(userID uint, ledgerID *uint) ([]models.Category, error) {
+	var categories []models.Category
+
+	query := scopeCategories(r.effective(userID), userID, ledgerID).Where("NOT hidden")
+
+	if err := query.Order("name, id").Find(&categories).Error; err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// IDS OF THE CATEGORY AND EVERY SUBCATEGORY BELOW IT, AT ANY DEPTH
+func (r *CategoryRepository) GetDescendantIDs(categoryID uint) ([]uint, error) {
+	var ids []uint
+
+	err := r.db.Raw(categoryDescendants, categoryID).Scan(&ids).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return ids, nil
+}
+
+// DELETE A CATEGORY, ITS SUBCATEGORIES MOVE UP TO ITS PARENT
+func (r *CategoryRepository) Delete(category *models.Category) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Category{}).Where("parent_id = ?", category.ID).Update("parent_id", category.ParentID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(category).Error
+	})
+}
+
+// NUMBER OF EXPENSES (TRASHED ONES INCLUDED), SPLIT LINES, RECURRING EXPENSES, BUDGETS AND CATEGORIZATION RULES ON A CATEGORY
+func (r *CategoryRepository) CountUsage(categoryID uint) (int64, error) {
+	var count int64
+
+	err := r.db.Raw(`SELECT
+			(SELECT COUNT(*) FROM expenses WHERE category_id = @id) +
+			(SELECT COUNT(*) FROM expense_splits WHERE category_id = @id) +
+			(SELECT COUNT(*) FROM recurring_expenses WHERE category_id = @id AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM budgets WHERE category_id = @id AND deleted_at IS NULL) +
+			(SELECT COUNT(*) FROM categorization_rules WHERE category_id = @id)`, sql.Named("id", categoryID)).
+		Row().Scan(&count)
+
+	return count, err
+}
+
+// MOVE EVERYTHING ON source TO target AND DELETE source IN ONE TRANSACTION
+// SPLIT LINES OF source ON AN EXPENSE THAT ALREADY HAS A LINE OF target ARE ADDED TO THAT LINE
+// BUDGETS ON source OF USERS WHO ALREADY HAVE ONE ON target ARE DELETED RATHER THAN MOVED
+// THE SUBCATEGORIES OF source MOVE UNDER childParentID
+func (r *CategoryRepository) Merge(source, target *models.Category, childParentID *uint) (*models.CategoryMergeResult, error) {
+	result := &models.CategoryMergeResult{
+		Category: *source,
+		Target:   target,
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		// COMBINE LINES OF BOTH CATEGORIES ON THE SAME EXPENSE
+		combined := tx.Exec(`UPDATE expense_splits AS target
+			SET amount = target.amount + source.amount, base_amount = target.base_amount + source.base_amount
+			FROM expense_splits AS source
+			WHERE source.expense_id = target.expense_id AND source.category_id = ? AND target.category_id = ?`, source.ID, target.ID)
+		if combined.Error != nil {
+			return combined.Error
+		}
+		if err := tx.Exec(`DELETE FROM expense_splits AS source
+			USING expense_splits AS target
+			WHERE source.expense_id = target.expense_id AND source.category_id = ? AND target.category_id = ?`, source.ID, target.ID).Error; err != nil {
+			return err
+		}
+
+		splits := tx.Model(&models.ExpenseSplit{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if splits.Error != nil {
+			return splits.Error
+		}
+		result.Splits = combined.RowsAffected + splits.RowsAffected
+
+		expenses := tx.Unscoped().Model(&models.Expense{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if expenses.Error != nil {
+			return expenses.Error
+		}
+		result.Expenses = expenses.RowsAffected
+
+		recurring := tx.Unscoped().Model(&models.RecurringExpense{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if recurring.Error != nil {
+			return recurring.Error
+		}
+		result.RecurringExpenses = recurring.RowsAffected
+
+		// A USER WITH A BUDGET ON target KEEPS IT, THEIR BUDGET ON source IS DELETED
+		dropped := tx.Where("category_id = ? AND user_id IN (?)", source.ID, tx.Model(&models.Budget{}).Select("user_id").Where("category_id = ?", target.ID)).Delete(&models.Budget{})
+		if dropped.Error != nil {
+			return dropped.Error
+		}
+		result.BudgetsDeleted = dropped.RowsAffected
+
+		budgets := tx.Unscoped().Model(&models.Budget{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if budgets.Error != nil {
+			return budgets.Error
+		}
+		// THE DELETED BUDGETS MOVE TOO BUT ARE COUNTED APART
+		result.Budgets = budgets.RowsAffected - result.BudgetsDeleted
+
+		rules := tx.Model(&models.CategorizationRule{}).Where("category_id = ?", source.ID).Update("category_id", target.ID)
+		if rules.Error != nil {
+			return rules.Error
+		}
+		result.Rules = rules.RowsAffected
+
+		children := tx.Unscoped().Model(&models.Category{}).Where("parent_id = ?", source.ID).Update("parent_id", childParentID)
+		if children.Error != nil {
+			return children.Error
+		}
+		result.Subcategories = children.RowsAffected
+
+		return tx.Delete(source).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// PERSONAL USER CATEGORIES AND DEFAULT CATEGORIES, ANY OF WHICH A PERSONAL EXPENSE OF THE USER MAY USE
+// DEFAULT CATEGORIES GO BY THE NAME THE USER GAVE THEM, HIDDEN ONES INCLUDED
+func (r *CategoryRepository) GetAvailableByUserID(userID uint) ([]models.Category, error) {
+	var categories []models.Category
+
+	err := scopeCategories(r.effective(userID), userID, nil).Order("id").Find(&categories).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return categories, nil
+}
+
+// A CATEGORY AS THE USER SEES IT, WITH THEIR OVERRIDE APPLIED TO A DEFAULT CATEGORY
+func (r *CategoryRepository) GetEffectiveByID(userID, categoryID uint) (*models.Category, error) {
+	var category models.Category
+
+	err := r.effective(userID).Where("id = ?", categoryID).First(&category).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &category, nil
+}
+
+// SET THE NAME AND hidden FLAG OF THE OVERRIDE OF THE USER ON A DEFAULT CATEGORY, CREATING IT WHEN MISSING
+func (r *CategoryRepository) SaveOverride(override *models.CategoryOverride) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "category_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "hidden", "updated_at"}),
+	}).Create(override).Error
+}
+
+// RESET A DEFAULT CATEGORY TO HOW EVERYONE SEES IT
+func (r *CategoryRepository) DeleteOverride(userID, categoryID uint) error {
+	return r.db.Where("user_id = ? AND category_id = ?", userID, categoryID).Delete(&models.CategoryOverride{}).Error
+}
+
+// CATEGORIES AS userID SEES THEM, DEFAULT CATEGORIES TAKE THE NAME AND hidden FLAG OF THEIR OVERRIDE OF THE USER
+// THE RESULT IS ALIASED AS categories SO QUERIES READ THE SAME AS ON THE TABLE ITSELF
+func (r *CategoryRepository) effective(userID uint) *gorm.DB {
+	return r.db.Model(&models.Category{}).Table("(?) AS categories", effectiveCategories(r.db, userID))
+}
+
+// SELECT EVERY CATEGORY, DELETED ONES INCLUDED, UNDER THE NAME userID GAVE IT
+// JOIN IT AS categories IN PLACE OF THE TABLE WHEREVER A CATEGORY NAME IS SHOWN TO THE USER
+func effectiveCategories(db *gorm.DB, userID uint) *gorm.DB {
+	return db.Table("categories").
+		Select(`categories.id,
+			COALESCE(NULLIF(category_overrides.name, ''), categories.name) AS name,
+			categories.user_id,
+			categories.ledger_id,
+			categories.parent_id,
+			categories.type,
+			categories.is_default,
+			categories.created_at,
+			categories.updated_at,
+			categories.deleted_at,
+			COALESCE(category_overrides.hidden, FALSE) AS hidden`).
+		Joins("LEFT JOIN category_overrides ON category_overrides.category_id = categories.id AND category_overrides.user_id = ?", userID)
+}
+
+// PRELOAD CONDITION LOADING A Category ASSOCIATION UNDER THE NAME userID GAVE IT
+func preloadEffectiveCategory(db *gorm.DB, userID uint) func(*gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		return tx.Table("(?) AS categories", effectiveCategories(db, userID))
+	}
+}
+
+// IDS OF THE CATEGORIES userID SEES UNDER A NAME CONTAINING name
+func categoryIDsNamed(db *gorm.DB, userID uint, name string) *gorm.DB {
+	return db.Table("(?) AS categories", effectiveCategories(db, userID)).Select("categories.id").Where("categories.name ILIKE ?", "%"+name+"%")
+}
+
+// RESTRICT A QUERY ON categories TO THE LEDGER, OR TO THE PERSONAL CATEGORIES OF THE USER WHEN ledgerID IS NIL
+// DEFAULT CATEGORIES ARE IN EITHER SCOPE
+func scopeCategories(query *gorm.DB, userID uint, ledgerID *uint) *gorm.DB {
+	if ledgerID != nil {
+		return query.Where("(ledger_id = ? OR (is_default = ? AND user_id IS NULL))", *ledgerID, true)
+	}
+
+	return query.Where("((user_id = ? AND ledger_id IS NULL) OR (is_default = ? AND user_id IS NULL))", userID, true)
 }
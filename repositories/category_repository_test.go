@@ -0,0 +1,60 @@
+package repositories
+
+import (
+	"testing"
+
+	"go-expense-tracker-api/models"
+)
+
+func TestMergeKeepsTheBudgetOfTheTarget(t *testing.T) {
+	db := testDB(t)
+	user, source := createTestUser(t, NewExpenseRepository(db), "EUR")
+
+	target := &models.Category{Name: "Food", Type: "expense", UserID: &user.ID}
+	other := &models.Category{Name: "Dining", Type: "expense", UserID: &user.ID}
+	empty := &models.Category{Name: "Eating out", Type: "expense", UserID: &user.ID}
+	for _, category := range []*models.Category{target, other, empty} {
+		if err := db.Create(category).Error; err != nil {
+			t.Fatalf("failed to create category: %v", err)
+		}
+	}
+
+	budgets := map[string]*models.Budget{
+		"source": {UserID: user.ID, CategoryID: source.ID, Amount: mustMoney(t, "100"), Period: "monthly"},
+		"target": {UserID: user.ID, CategoryID: target.ID, Amount: mustMoney(t, "300"), Period: "monthly"},
+		"other":  {UserID: user.ID, CategoryID: other.ID, Amount: mustMoney(t, "50"), Period: "monthly"},
+	}
+	for _, budget := range budgets {
+		if err := db.Omit("Category").Create(budget).Error; err != nil {
+			t.Fatalf("failed to create budget: %v", err)
+		}
+	}
+
+	repo := NewCategoryRepository(db)
+
+	// THE TARGET ALREADY HAS A BUDGET, THE ONE ON THE SOURCE IS DELETED
+	result, err := repo.Merge(source, target, nil)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if result.Budgets != 0 || result.BudgetsDeleted != 1 {
+		t.Errorf("moved %d budgets and deleted %d, want 0 and 1", result.Budgets, result.BudgetsDeleted)
+	}
+
+	// WITHOUT A BUDGET ON THE TARGET IT MOVES
+	result, err = repo.Merge(other, empty, nil)
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+	if result.Budgets != 1 || result.BudgetsDeleted != 0 {
+		t.Errorf("moved %d budgets and deleted %d, want 1 and 0", result.Budgets, result.BudgetsDeleted)
+	}
+
+	var onTarget []models.Budget
+	if err := db.Where("category_id = ?", target.ID).Find(&onTarget).Error; err != nil {
+		t.Fatalf("failed to load budgets: %v", err)
+	}
+	if len(onTarget) != 1 || onTarget[0].ID != budgets["target"].ID {
+		t.Errorf("budgets on the target = %+v, want only its own", onTarget)
+	}
+}
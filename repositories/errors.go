@@ -0,0 +1,6 @@
+package repositories
+
+import "errors"
+
+// RETURNED WHEN A QUERY FILTER HAS AN INVALID VALUE (CLIENT ERROR)
+var ErrInvalidFilter = errors.New("invalid filter")
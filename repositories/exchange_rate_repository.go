@@ -0,0 +1,112 @@
+package repositories
+
+import (
+	"fmt"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/utils"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type ExchangeRateRepository struct {
+	db *gorm.DB
+}
+
+func NewExchangeRateRepository(db *gorm.DB) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// INSERT RATES, OVERWRITING EXISTING RATES FOR THE SAME USER, DATE AND PAIR
+func (r *ExchangeRateRepository) Upsert(rates []*models.ExchangeRate) error {
+	if len(rates) == 0 {
+		return nil
+	}
+
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "date"}, {Name: "base"}, {Name: "currency"}},
+		DoUpdates: clause.AssignmentColumns([]string{"rate", "updated_at"}),
+	}).CreateInBatches(rates, 500).Error
+}
+
+// GET THE MOST RECENT RATE ON OR BEFORE A DATE, USER RATES WIN OVER SHARED RATES OF THE SAME DAY
+func (r *ExchangeRateRepository) GetLatest(userID uint, base, currency string, at time.Time) (*models.ExchangeRate, error) {
+	var rate models.ExchangeRate
+
+	err := r.db.
+		Where("user_id IN (?, 0) AND base = ? AND currency = ? AND date <= ?", userID, base, currency, at).
+		Order("date DESC, user_id DESC").
+		First(&rate).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &rate, nil
+}
+
+// GET ALL BASE CURRENCIES QUOTING THE GIVEN CURRENCY
+func (r *ExchangeRateRepository) GetBasesFor(userID uint, currency string) ([]string, error) {
+	var bases []string
+
+	err := r.db.Model(&models.ExchangeRate{}).
+		Where("user_id IN (?, 0) AND currency = ?", userID, currency).
+		Distinct().
+		Pluck("base", &bases).Error
+
+	return bases, err
+}
+
+func (r *ExchangeRateRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.ExchangeRate, int64, int64, error) {
+	var rates []models.ExchangeRate
+	var total int64
+
+	query := r.db.Model(&models.ExchangeRate{}).Where("user_id IN (?, 0)", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "base", "currency":
+			query = query.Where(key+" = ?", strings.ToUpper(value))
+		case "date":
+			date, _, err := utils.ParseDateTime(value, time.UTC)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: date: %v", ErrInvalidFilter, err)
+			}
+			query = query.Where("date = ?", date)
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Limit(queryParams.Limit).Offset(offset).Find(&rates).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &rates, total, totalPages, nil
+}
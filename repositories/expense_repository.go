@@ -1,11 +1,16 @@
 package repositories
 
 import (
+	"fmt"
 	"go-expense-tracker-api/middleware"
 	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/utils"
+	"strconv"
 	"strings"
+	"time"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type ExpenseRepository struct {
@@ -17,66 +22,260 @@ func NewExpenseRepository(db *gorm.DB) *ExpenseRepository {
 }
 
 func (r *ExpenseRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Expense, int64, int64, error) {
+	query, err := r.filteredByUserID(userID, queryParams)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return findExpensePage(query, userID, queryParams)
+}
+
+// DELETED EXPENSES OF THE USER, OR OF THE LEDGER IN queryParams, WITH THE SAME FILTERS AS GetByUserID
+func (r *ExpenseRepository) GetTrashByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Expense, int64, int64, error) {
+	query, err := r.filteredByUserID(userID, queryParams)
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	return findExpensePage(query.Unscoped().Where("expenses.deleted_at IS NOT NULL"), userID, queryParams)
+}
+
+// COUNT, SORT AND PAGINATE AN EXPENSES QUERY, LOADING THE RELATIONSHIPS OF EVERY EXPENSE ON THE PAGE
+func findExpensePage(query *gorm.DB, userID uint, queryParams middleware.QueryParams) (*[]models.Expense, int64, int64, error) {
 	var expenses []models.Expense
 	var total int64
 
-	query := r.db.Model(&models.Expense{}).Where("expenses.user_id = ?", userID)
-	query = query.Joins("Category")
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	query = applyExpenseSorting(query, queryParams)
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	query = query.Offset(offset).Limit(queryParams.Limit)
+
+	if err := preloadExpense(query, userID).Find(&expenses).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &expenses, total, totalPages, nil
+}
+
+// CALL fn FOR EVERY EXPENSE MATCHING THE FILTERS AND SORTING OF GetByUserID, WITHOUT PAGINATION
+// ROWS ARE STREAMED FROM THE DATABASE ONE AT A TIME, fn RETURNING AN ERROR STOPS THE ITERATION
+func (r *ExpenseRepository) EachByUserID(userID uint, queryParams middleware.QueryParams, fn func(expense *models.Expense) error) error {
+	query, err := r.filteredByUserID(userID, queryParams)
+	if err != nil {
+		return err
+	}
+
+	// THE CATEGORIES OF THE MATCHING EXPENSES, NAMED AS THE USER SEES THEM
+	used, err := r.filteredByUserID(userID, queryParams)
+	if err != nil {
+		return err
+	}
+	var categories []models.Category
+	err = r.db.Unscoped().Table("(?) AS categories", effectiveCategories(r.db, userID)).
+		Where("categories.id IN (?)", used.Select("expenses.category_id")).
+		Find(&categories).Error
+	if err != nil {
+		return err
+	}
+	categoriesByID := make(map[uint]models.Category, len(categories))
+	for _, category := range categories {
+		categoriesByID[category.ID] = category
+	}
+
+	// APPLY SORTING
+	query = applyExpenseSorting(query, queryParams)
+
+	rows, err := query.Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var expense models.Expense
+		if err := query.ScanRows(rows, &expense); err != nil {
+			return err
+		}
+		expense.Category = categoriesByID[expense.CategoryID]
+		if err := fn(&expense); err != nil {
+			return err
+		}
+	}
+
+	return rows.Err()
+}
+
+// IDS OF SPLIT EXPENSES WITH THEIR LINE CATEGORIES JOINED, TO BE COMPLETED WITH A WHERE ON categories
+const splitCategories = "SELECT expense_splits.expense_id FROM expense_splits JOIN categories ON categories.id = expense_splits.category_id"
+
+// BUILD THE EXPENSES QUERY OF THE USER, OR OF THE LEDGER IN queryParams, WITH THE QUERY FILTERS APPLIED
+// CATEGORY NAMES ARE MATCHED AS THE USER SEES THEM
+func (r *ExpenseRepository) filteredByUserID(userID uint, queryParams middleware.QueryParams) (*gorm.DB, error) {
+	query := scopeExpenses(r.db.Model(&models.Expense{}), userID, queryParams.LedgerID)
+
+	loc := queryParams.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// VALIDATE TAG FILTER MODE
+	tagsMode := queryParams.Filters["tags_mode"]
+	if tagsMode != "" && tagsMode != "any" && tagsMode != "all" {
+		return nil, fmt.Errorf("%w: tags_mode must be any or all", ErrInvalidFilter)
+	}
 
 	// APPLY FILTERS
 	for key, value := range queryParams.Filters {
 		if value != "" {
 			switch key {
+			// A SPLIT EXPENSE ALSO MATCHES BY THE CATEGORIES OF ITS LINES
 			case "category_name":
-				query = query.Where(`"Category"."name" ILIKE ?`, "%"+value+"%")
+				named := categoryIDsNamed(r.db, userID, value)
+				query = query.Where("(expenses.category_id IN (?) OR expenses.id IN (SELECT expense_splits.expense_id FROM expense_splits WHERE expense_splits.category_id IN (?)))", named, named)
+			// FILTERING BY A PARENT CATEGORY ALSO KEEPS THE EXPENSES OF ITS SUBCATEGORIES
+			case "category_id":
+				categoryID, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("%w: category_id must be a number", ErrInvalidFilter)
+				}
+				query = query.Where(`(expenses.category_id IN (`+categoryDescendants+`) OR expenses.id IN (`+splitCategories+` WHERE categories.id IN (`+categoryDescendants+`)))`, categoryID, categoryID)
 			case "category_type":
-				query = query.Where(`"Category"."type" = ?`, value)
+				query = query.Where(`(expenses.category_id IN (SELECT categories.id FROM categories WHERE categories.type = ?) OR expenses.id IN (`+splitCategories+` WHERE categories.type = ?))`, value, value)
+			case "from":
+				from, _, err := utils.ParseDateTime(value, loc)
+				if err != nil {
+					return nil, fmt.Errorf("%w: from: %v", ErrInvalidFilter, err)
+				}
+				query = query.Where("expenses.spent_at >= ?", from)
+			case "to":
+				to, dateOnly, err := utils.ParseDateTime(value, loc)
+				if err != nil {
+					return nil, fmt.Errorf("%w: to: %v", ErrInvalidFilter, err)
+				}
+				// A PLAIN DATE INCLUDES THE WHOLE DAY
+				if dateOnly {
+					query = query.Where("expenses.spent_at < ?", to.AddDate(0, 0, 1))
+				} else {
+					query = query.Where("expenses.spent_at <= ?", to)
+				}
+			case "tags":
+				query = filterByTags(query, value, tagsMode)
+			case "tags_mode":
+				// APPLIED WITH tags
+			case "payee_id":
+				payeeID, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("%w: payee_id must be a number", ErrInvalidFilter)
+				}
+				query = query.Where("expenses.payee_id = ?", payeeID)
+			case "account_id":
+				accountID, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("%w: account_id must be a number", ErrInvalidFilter)
+				}
+				query = query.Where("expenses.account_id = ?", accountID)
+			case "account_type":
+				query = query.Where("expenses.account_id IN (SELECT id FROM accounts WHERE type = ?)", value)
+			case "goal_id":
+				goalID, err := strconv.ParseUint(value, 10, 32)
+				if err != nil {
+					return nil, fmt.Errorf("%w: goal_id must be a number", ErrInvalidFilter)
+				}
+				query = query.Where("expenses.goal_id = ?", goalID)
 			default:
 				query = query.Where("expenses."+key+" ILIKE ?", "%"+value+"%")
 			}
 		}
 	}
 
-	// COUNT TOTAL RECORDS
-	if err := query.Count(&total).Error; err != nil {
-		return nil, 0, 0, err
+	return query, nil
+}
+
+// RESTRICT A QUERY ON expenses TO A LEDGER, OR TO THE PERSONAL EXPENSES OF THE USER WHEN ledgerID IS NIL
+func scopeExpenses(query *gorm.DB, userID uint, ledgerID *uint) *gorm.DB {
+	if ledgerID != nil {
+		return query.Where("expenses.ledger_id = ?", *ledgerID)
 	}
 
-	// CALCULATE TOTAL PAGES
-	totalPages := int64(total) / int64(queryParams.Limit)
-	if int64(total)%int64(queryParams.Limit) != 0 {
-		totalPages++
+	return query.Where("expenses.user_id = ? AND expenses.ledger_id IS NULL", userID)
+}
+
+// KEEP EXPENSES WITH ANY (DEFAULT) OR ALL OF THE COMMA SEPARATED TAG NAMES
+// TAGS ARE MATCHED BY NAME SO THE TAGS OF EVERY MEMBER COUNT ON LEDGER EXPENSES
+func filterByTags(query *gorm.DB, value, mode string) *gorm.DB {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = models.NormalizeTagName(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	if len(names) == 0 {
+		return query
 	}
 
-	// APPLY SORTING
-	if queryParams.SortBy != "" {
-		order := "asc"
-		if strings.ToLower(queryParams.Order) == "desc" {
-			order = "desc"
+	tagged := "SELECT expense_tags.expense_id FROM expense_tags JOIN tags ON tags.id = expense_tags.tag_id WHERE tags.name IN ?"
+	if mode == "all" {
+		return query.Where("expenses.id IN ("+tagged+" GROUP BY expense_tags.expense_id HAVING COUNT(DISTINCT tags.name) = ?)", names, len(uniqueStrings(names)))
+	}
+
+	return query.Where("expenses.id IN ("+tagged+")", names)
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
 		}
-		query = query.Order(queryParams.SortBy + " " + order)
 	}
 
-	// APPLY PAGINATION
-	offset := (queryParams.Page - 1) * queryParams.Limit
-	orderBy := "expenses." + queryParams.SortBy + " " + queryParams.Order
-	query = query.Offset(offset).Limit(queryParams.Limit).Order(orderBy)
+	return unique
+}
 
-	if err := query.Preload("Category").Find(&expenses).Error; err != nil {
-		return nil, 0, 0, err
+// PRELOAD THE RELATIONSHIPS OF AN EXPENSE, ITS CATEGORIES UNDER THE NAMES userID GAVE THEM
+func preloadExpense(query *gorm.DB, userID uint) *gorm.DB {
+	categories := preloadEffectiveCategory(query.Session(&gorm.Session{NewDB: true}), userID)
+
+	return query.Preload("Category", categories).Preload("Tags").Preload("Payee").Preload("Account").Preload("Splits.Category", categories).Preload("Shares")
+}
+
+func applyExpenseSorting(query *gorm.DB, queryParams middleware.QueryParams) *gorm.DB {
+	if queryParams.SortBy == "" {
+		return query
 	}
 
-	return &expenses, total, totalPages, nil
+	order := "asc"
+	if strings.ToLower(queryParams.Order) == "desc" {
+		order = "desc"
+	}
+
+	return query.Order("expenses." + queryParams.SortBy + " " + order)
 }
 
 func (r *ExpenseRepository) Create(expense *models.Expense) error {
 	return r.db.Create(expense).Error
 }
 
-func (r *ExpenseRepository) GetByID(id uint) (*models.Expense, error) {
+// AN EXPENSE BY ID WITH ITS RELATIONSHIPS, CATEGORIES NAMED AS userID SEES THEM
+func (r *ExpenseRepository) GetByID(id, userID uint) (*models.Expense, error) {
 	var expense models.Expense
 
-	err := r.db.Preload("Category").Where("id = ?", id).First(&expense).Error
+	err := preloadExpense(r.db, userID).Where("id = ?", id).First(&expense).Error
 	if err != nil {
 		return nil, err
 	}
@@ -84,10 +283,289 @@ func (r *ExpenseRepository) GetByID(id uint) (*models.Expense, error) {
 	return &expense, nil
 }
 
+// SAVE THE EXPENSE AND REPLACE ITS TAGS, SPLITS AND SHARES WITH expense.Tags, expense.Splits AND expense.Shares
 func (r *ExpenseRepository) Update(expense *models.Expense) error {
-	return r.db.Save(expense).Error
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Category", "Tags", "Payee", "Account", "Splits", "Shares").Save(expense).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(expense).Association("Tags").Replace(expense.Tags); err != nil {
+			return err
+		}
+
+		if err := tx.Where("expense_id = ?", expense.ID).Delete(&models.ExpenseSplit{}).Error; err != nil {
+			return err
+		}
+		if len(expense.Splits) > 0 {
+			for i := range expense.Splits {
+				expense.Splits[i].ID = 0
+				expense.Splits[i].ExpenseID = expense.ID
+			}
+			if err := tx.Omit("Category").Create(&expense.Splits).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("expense_id = ?", expense.ID).Delete(&models.ExpenseShare{}).Error; err != nil {
+			return err
+		}
+		if len(expense.Shares) == 0 {
+			return nil
+		}
+
+		for i := range expense.Shares {
+			expense.Shares[i].ID = 0
+			expense.Shares[i].ExpenseID = expense.ID
+		}
+
+		return tx.Create(&expense.Shares).Error
+	})
 }
 
+// MOVE THE EXPENSE TO THE TRASH, IT KEEPS ITS SPLITS, SHARES AND TAG LINKS UNTIL PURGED
 func (r *ExpenseRepository) Delete(expense *models.Expense) error {
-	return r.db.Delete(&expense).Error
+	return r.db.Delete(expense).Error
+}
+
+// A DELETED EXPENSE BY ID, WITH ITS RELATIONSHIPS, CATEGORIES NAMED AS userID SEES THEM
+func (r *ExpenseRepository) GetTrashedByID(id, userID uint) (*models.Expense, error) {
+	var expense models.Expense
+
+	err := preloadExpense(r.db.Unscoped(), userID).Where("id = ? AND deleted_at IS NOT NULL", id).First(&expense).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &expense, nil
+}
+
+// DELETED EXPENSES OF THE USER, OR OF THE LEDGER WHEN ledgerID IS SET
+func (r *ExpenseRepository) GetAllTrashByUserID(userID uint, ledgerID *uint) ([]models.Expense, error) {
+	var expenses []models.Expense
+
+	err := scopeExpenses(r.db.Unscoped(), userID, ledgerID).Where("expenses.deleted_at IS NOT NULL").Order("id").Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+// UP TO limit EXPENSES DELETED BEFORE before, OLDEST FIRST
+func (r *ExpenseRepository) GetTrashedBefore(before time.Time, limit int) ([]models.Expense, error) {
+	var expenses []models.Expense
+
+	err := r.db.Unscoped().Where("deleted_at < ?", before).Order("deleted_at").Limit(limit).Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+func (r *ExpenseRepository) Restore(expense *models.Expense) error {
+	if err := r.db.Unscoped().Model(expense).Update("deleted_at", nil).Error; err != nil {
+		return err
+	}
+	expense.DeletedAt = gorm.DeletedAt{}
+
+	return nil
+}
+
+// DELETE AN EXPENSE PERMANENTLY WITH ITS TAGS, SPLITS AND SHARES
+func (r *ExpenseRepository) Purge(expense *models.Expense) error {
+	return r.db.Unscoped().Select("Tags", "Splits", "Shares").Delete(expense).Error
+}
+
+// PERSONAL EXPENSES OF THE USER INCLUDING THE TRASH, LEDGER EXPENSES ARE IN THE LEDGER BASE CURRENCY
+func (r *ExpenseRepository) GetAllByUserID(userID uint) ([]models.Expense, error) {
+	var expenses []models.Expense
+
+	err := r.db.Unscoped().Where("user_id = ? AND ledger_id IS NULL", userID).Find(&expenses).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return expenses, nil
+}
+
+// SWITCH A USER BASE CURRENCY AND STORE THE RECALCULATED BASE AMOUNTS ATOMICALLY
+// SPLIT LINES GET THEIR SHARE OF THE NEW BASE AMOUNT, ALLOCATED AS ON CREATE
+func (r *ExpenseRepository) RebaseAmounts(userID uint, baseCurrency string, baseAmounts map[uint]models.Money) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&models.User{}).Where("id = ?", userID).Update("base_currency", baseCurrency).Error; err != nil {
+			return err
+		}
+
+		for expenseID, baseAmount := range baseAmounts {
+			err := tx.Unscoped().Model(&models.Expense{}).
+				Where("id = ? AND user_id = ? AND ledger_id IS NULL", expenseID, userID).
+				Updates(map[string]any{"base_amount": baseAmount, "base_currency": baseCurrency}).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		// RELOAD THE SPLIT EXPENSES WITH THEIR LINES IN CREATION ORDER, THE LAST LINE TAKES THE ROUNDING REMAINDER
+		var expenses []models.Expense
+		err := tx.Unscoped().
+			Preload("Splits", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+			Where("user_id = ? AND ledger_id IS NULL", userID).
+			Where("EXISTS (SELECT 1 FROM expense_splits WHERE expense_splits.expense_id = expenses.id)").
+			Find(&expenses).Error
+		if err != nil {
+			return err
+		}
+
+		for i := range expenses {
+			expense := &expenses[i]
+			baseAmount, ok := baseAmounts[expense.ID]
+			if !ok {
+				continue
+			}
+
+			expense.Rebase(baseCurrency, baseAmount)
+			for _, split := range expense.Splits {
+				if err := tx.Model(&models.ExpenseSplit{}).Where("id = ?", split.ID).Update("base_amount", split.BaseAmount).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		return nil
+	})
+}
+
+// CALL fn WITH ITS CATEGORY AND TAGS LOADED FOR EVERY PERSONAL EXPENSE OF THE USER THAT IS NOT SPLIT OR IN THE TRASH, SPENT IN [from, to)
+// NIL BOUNDS ARE OPEN, categoryID 0 IS ANY CATEGORY. EXPENSES ARE LOADED IN BATCHES BY ID, fn RETURNING AN ERROR STOPS THE ITERATION
+func (r *ExpenseRepository) EachCategorizable(userID uint, from, to *time.Time, categoryID uint, fn func(expense *models.Expense) error) error {
+	query := scopeExpenses(r.db.Model(&models.Expense{}), userID, nil).
+		Where("NOT EXISTS (SELECT 1 FROM expense_splits WHERE expense_splits.expense_id = expenses.id)")
+	if categoryID != 0 {
+		query = query.Where("expenses.category_id = ?", categoryID)
+	}
+	if from != nil {
+		query = query.Where("expenses.spent_at >= ?", *from)
+	}
+	if to != nil {
+		query = query.Where("expenses.spent_at < ?", *to)
+	}
+
+	var expenses []models.Expense
+	return query.Preload("Category", func(db *gorm.DB) *gorm.DB { return db.Unscoped() }).Preload("Tags").FindInBatches(&expenses, 500, func(tx *gorm.DB, batch int) error {
+		for i := range expenses {
+			if err := fn(&expenses[i]); err != nil {
+				return err
+			}
+		}
+		return nil
+	}).Error
+}
+
+// SET THE CATEGORY AND ADD THE TAGS OF EVERY CHANGE, ALL IN ONE TRANSACTION
+func (r *ExpenseRepository) ApplyCategorization(changes []models.CategorizationChange) error {
+	byCategory := make(map[uint][]uint)
+	var expenseTags []map[string]any
+	for _, change := range changes {
+		if change.CategoryID != change.FromCategoryID {
+			byCategory[change.CategoryID] = append(byCategory[change.CategoryID], change.ExpenseID)
+		}
+		for _, tagID := range change.TagIDs {
+			expenseTags = append(expenseTags, map[string]any{"expense_id": change.ExpenseID, "tag_id": tagID})
+		}
+	}
+
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for categoryID, expenseIDs := range byCategory {
+			if err := tx.Model(&models.Expense{}).Where("id IN ?", expenseIDs).Update("category_id", categoryID).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(expenseTags) == 0 {
+			return nil
+		}
+
+		return tx.Table("expense_tags").Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(expenseTags, 500).Error
+	})
+}
+
+// SUM BASE AMOUNTS OF A CATEGORY AND ITS SUBCATEGORIES IN THE PERSONAL EXPENSES OF THE USER SPENT IN [from, to)
+// INCLUDING SPLIT LINES IN THOSE CATEGORIES
+func (r *ExpenseRepository) SumByCategory(userID, categoryID uint, from, to time.Time) (models.Money, error) {
+	var total models.Money
+
+	err := scopeExpenses(spendingLines(r.db), userID, nil).
+		Select("COALESCE(SUM(expenses.base_amount), 0)").
+		Where("expenses.category_id IN ("+categoryDescendants+") AND expenses.spent_at >= ? AND expenses.spent_at < ?", categoryID, from, to).
+		Row().Scan(&total)
+
+	return total, err
+}
+
+// NET AMOUNT SAVED FOR A GOAL IN THE USER BASE CURRENCY AND THE NUMBER OF ITS CONTRIBUTIONS
+// EXPENSE CATEGORIES ADD TO THE GOAL, INCOME CATEGORIES WITHDRAW FROM IT, A SPLIT CONTRIBUTION BY THE CATEGORY OF EACH LINE
+func (r *ExpenseRepository) SumByGoal(userID, goalID uint) (models.Money, int64, error) {
+	var saved models.Money
+	var count int64
+
+	err := scopeExpenses(expenseLines(r.db), userID, nil).
+		Select(`COALESCE(SUM(CASE WHEN categories.type = 'income' THEN -expenses.base_amount ELSE expenses.base_amount END), 0), COUNT(DISTINCT expenses.id)`).
+		Joins("JOIN categories ON categories.id = expenses.category_id").
+		Where("expenses.goal_id = ?", goalID).
+		Row().Scan(&saved, &count)
+
+	return saved, count, err
+}
+
+// ONE ROW PER EXPENSE NOT IN THE TRASH, OR PER LINE OF A SPLIT EXPENSE, WITH THE CATEGORY AND BASE AMOUNT OF THE LINE
+// THE RESULT IS ALIASED AS expenses SO AGGREGATES READ THE SAME AS ON THE TABLE ITSELF
+func expenseLines(db *gorm.DB) *gorm.DB {
+	lines := db.Table("expenses").
+		Select(`expenses.id,
+			expenses.user_id,
+			expenses.ledger_id,
+			expenses.name,
+			expenses.spent_at,
+			expenses.payee_id,
+			expenses.goal_id,
+			COALESCE(expense_splits.category_id, expenses.category_id) AS category_id,
+			COALESCE(expense_splits.base_amount, expenses.base_amount) AS base_amount`).
+		Joins("LEFT JOIN expense_splits ON expense_splits.expense_id = expenses.id").
+		Where("expenses.deleted_at IS NULL")
+
+	return db.Table("(?) AS expenses", lines)
+}
+
+// THE LINES OF expenseLines THAT COUNT AS SPENDING OR INCOME
+// CONTRIBUTIONS TO A SAVINGS GOAL ONLY SET MONEY ASIDE, THEY ARE LEFT OUT OF REPORTS AND BUDGETS
+func spendingLines(db *gorm.DB) *gorm.DB {
+	return expenseLines(db).Where("expenses.goal_id IS NULL")
+}
+
+// INSERT ALL EXPENSES IN ONE TRANSACTION, NOTHING IS SAVED IF ANY INSERT FAILS
+func (r *ExpenseRepository) CreateMany(expenses []*models.Expense) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Omit("Category", "Payee", "Account").CreateInBatches(expenses, 100).Error
+	})
+}
+
+// THE SUBSET OF externalIDs ALREADY USED BY EXPENSES OF THE USER, DELETED ONES INCLUDED SO THEY ARE NOT IMPORTED AGAIN
+func (r *ExpenseRepository) GetExistingExternalIDs(userID uint, externalIDs []string) (map[string]bool, error) {
+	var found []string
+
+	err := r.db.Unscoped().Model(&models.Expense{}).
+		Where("user_id = ? AND external_id IN ?", userID, externalIDs).
+		Pluck("external_id", &found).Error
+	if err != nil {
+		return nil, err
+	}
+
+	existing := make(map[string]bool, len(found))
+	for _, externalID := range found {
+		existing[externalID] = true
+	}
+
+	return existing, nil
 }
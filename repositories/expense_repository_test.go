@@ -0,0 +1,218 @@
+package repositories
+
+import (
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+
+	"gorm.io/gorm"
+)
+
+func mustMoney(t *testing.T, value string) models.Money {
+	t.Helper()
+
+	m, err := models.ParseMoney(value)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q): %v", value, err)
+	}
+
+	return m
+}
+
+var testUsers atomic.Int64
+
+// CREATE A USER WITH AN EXPENSE CATEGORY OF THEIR OWN
+func createTestUser(t *testing.T, repo *ExpenseRepository, baseCurrency string) (*models.User, *models.Category) {
+	t.Helper()
+
+	user := &models.User{
+		Email:        fmt.Sprintf("user-%d-%d@example.com", time.Now().UnixNano(), testUsers.Add(1)),
+		Name:         "Test User",
+		Password:     "secret",
+		BaseCurrency: baseCurrency,
+	}
+	if err := repo.db.Create(user).Error; err != nil {
+		t.Fatalf("failed to create user: %v", err)
+	}
+
+	category := &models.Category{Name: "Groceries", Type: "expense", UserID: &user.ID}
+	if err := repo.db.Create(category).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	return user, category
+}
+
+func TestRebaseAmountsReallocatesSplits(t *testing.T) {
+	repo := NewExpenseRepository(testDB(t))
+	user, category := createTestUser(t, repo, "EUR")
+
+	expense := &models.Expense{
+		Name:         "Supermarket",
+		Amount:       mustMoney(t, "10"),
+		Currency:     "EUR",
+		BaseAmount:   mustMoney(t, "10"),
+		BaseCurrency: "EUR",
+		SpentAt:      time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+		UserID:       user.ID,
+		CategoryID:   category.ID,
+		Splits: []models.ExpenseSplit{
+			{CategoryID: category.ID, Amount: mustMoney(t, "7.5"), BaseAmount: mustMoney(t, "7.5")},
+			{CategoryID: category.ID, Amount: mustMoney(t, "2.5"), BaseAmount: mustMoney(t, "2.5")},
+		},
+	}
+	if err := repo.db.Omit("Category").Create(expense).Error; err != nil {
+		t.Fatalf("failed to create expense: %v", err)
+	}
+
+	err := repo.RebaseAmounts(user.ID, "USD", map[uint]models.Money{expense.ID: mustMoney(t, "10.85")})
+	if err != nil {
+		t.Fatalf("RebaseAmounts: %v", err)
+	}
+
+	var rebased models.Expense
+	if err := repo.db.Preload("Splits", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(&rebased, expense.ID).Error; err != nil {
+		t.Fatalf("failed to reload expense: %v", err)
+	}
+
+	if rebased.BaseCurrency != "USD" || rebased.BaseAmount != mustMoney(t, "10.85") {
+		t.Fatalf("expense base = %s %s, want 10.85 USD", rebased.BaseAmount, rebased.BaseCurrency)
+	}
+
+	want := []string{"8.14", "2.71"}
+	if len(rebased.Splits) != len(want) {
+		t.Fatalf("got %d splits, want %d", len(rebased.Splits), len(want))
+	}
+	for i, split := range rebased.Splits {
+		if split.BaseAmount != mustMoney(t, want[i]) {
+			t.Errorf("split %d base amount = %s, want %s USD", i, split.BaseAmount, want[i])
+		}
+	}
+}
+
+func TestApplyCategorization(t *testing.T) {
+	repo := NewExpenseRepository(testDB(t))
+	user, other := createTestUser(t, repo, "EUR")
+
+	fuel := &models.Category{Name: "Fuel", Type: "expense", UserID: &user.ID}
+	if err := repo.db.Create(fuel).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	car := &models.Tag{UserID: user.ID, Name: "car"}
+	work := &models.Tag{UserID: user.ID, Name: "work"}
+	if err := repo.db.Create([]*models.Tag{car, work}).Error; err != nil {
+		t.Fatalf("failed to create tags: %v", err)
+	}
+
+	// ONE EXPENSE TO MOVE AND TAG, ONE ALREADY IN THE CATEGORY THAT ONLY GETS A TAG
+	moved := &models.Expense{Name: "Shell 42", Amount: mustMoney(t, "40"), Currency: "EUR", BaseAmount: mustMoney(t, "40"), BaseCurrency: "EUR",
+		SpentAt: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), UserID: user.ID, CategoryID: other.ID, Tags: []models.Tag{*work}}
+	tagged := &models.Expense{Name: "Shell 43", Amount: mustMoney(t, "45"), Currency: "EUR", BaseAmount: mustMoney(t, "45"), BaseCurrency: "EUR",
+		SpentAt: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), UserID: user.ID, CategoryID: fuel.ID}
+	for _, expense := range []*models.Expense{moved, tagged} {
+		if err := repo.db.Omit("Category", "Tags.*").Create(expense).Error; err != nil {
+			t.Fatalf("failed to create expense: %v", err)
+		}
+	}
+
+	changes := []models.CategorizationChange{
+		{ExpenseID: moved.ID, FromCategoryID: other.ID, CategoryID: fuel.ID, TagIDs: []uint{car.ID}},
+		{ExpenseID: tagged.ID, FromCategoryID: fuel.ID, CategoryID: fuel.ID, TagIDs: []uint{car.ID, work.ID}},
+	}
+	if err := repo.ApplyCategorization(changes); err != nil {
+		t.Fatalf("ApplyCategorization: %v", err)
+	}
+	// APPLYING AGAIN DOES NOT DUPLICATE TAGS
+	if err := repo.ApplyCategorization(changes); err != nil {
+		t.Fatalf("ApplyCategorization again: %v", err)
+	}
+
+	for _, want := range []struct {
+		expense  *models.Expense
+		category uint
+		tags     []string
+	}{
+		{moved, fuel.ID, []string{"car", "work"}},
+		{tagged, fuel.ID, []string{"car", "work"}},
+	} {
+		var expense models.Expense
+		err := repo.db.Preload("Tags", func(db *gorm.DB) *gorm.DB { return db.Order("name") }).First(&expense, want.expense.ID).Error
+		if err != nil {
+			t.Fatalf("failed to reload expense: %v", err)
+		}
+
+		if expense.CategoryID != want.category {
+			t.Errorf("expense %s category = %d, want %d", expense.Name, expense.CategoryID, want.category)
+		}
+
+		var names []string
+		for _, tag := range expense.Tags {
+			names = append(names, tag.Name)
+		}
+		if strings.Join(names, ",") != strings.Join(want.tags, ",") {
+			t.Errorf("expense %s tags = %v, want %v", expense.Name, names, want.tags)
+		}
+	}
+}
+
+func TestGoalContributionsAreNotSpending(t *testing.T) {
+	repo := NewExpenseRepository(testDB(t))
+	user, groceries := createTestUser(t, repo, "EUR")
+
+	goal := &models.Goal{UserID: user.ID, Name: "Holiday", TargetAmount: mustMoney(t, "1000"), Currency: "EUR"}
+	if err := repo.db.Create(goal).Error; err != nil {
+		t.Fatalf("failed to create goal: %v", err)
+	}
+	savings := &models.Category{Name: "Savings", Type: "expense", UserID: &user.ID}
+	if err := repo.db.Create(savings).Error; err != nil {
+		t.Fatalf("failed to create category: %v", err)
+	}
+
+	spentAt := time.Date(2025, 3, 10, 0, 0, 0, 0, time.UTC)
+	expenses := []*models.Expense{
+		{Name: "Market", Amount: mustMoney(t, "30"), CategoryID: groceries.ID},
+		// A SPLIT CONTRIBUTION, ITS LINES ARE COUNTED ONCE FOR THE GOAL
+		{Name: "Transfer to savings", Amount: mustMoney(t, "100"), CategoryID: groceries.ID, GoalID: &goal.ID, Splits: []models.ExpenseSplit{
+			{CategoryID: groceries.ID, Amount: mustMoney(t, "40"), BaseAmount: mustMoney(t, "40")},
+			{CategoryID: savings.ID, Amount: mustMoney(t, "60"), BaseAmount: mustMoney(t, "60")},
+		}},
+	}
+	for _, expense := range expenses {
+		expense.Currency, expense.BaseAmount, expense.BaseCurrency = "EUR", expense.Amount, "EUR"
+		expense.SpentAt, expense.UserID = spentAt, user.ID
+		if err := repo.db.Omit("Category").Create(expense).Error; err != nil {
+			t.Fatalf("failed to create expense: %v", err)
+		}
+	}
+
+	from, to := spentAt.AddDate(0, 0, -1), spentAt.AddDate(0, 0, 1)
+
+	spent, err := repo.SumByCategory(user.ID, groceries.ID, from, to)
+	if err != nil {
+		t.Fatalf("SumByCategory: %v", err)
+	}
+	if spent != mustMoney(t, "30") {
+		t.Errorf("spent on groceries = %s, want 30 without the contribution", spent)
+	}
+
+	summaries, err := NewReportRepository(repo.db).SummaryByCategory(user.ID, nil, from, to, false)
+	if err != nil {
+		t.Fatalf("SummaryByCategory: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].CategoryID != groceries.ID || summaries[0].Total != mustMoney(t, "30") {
+		t.Errorf("summaries = %+v, want only the 30 on groceries", summaries)
+	}
+
+	saved, count, err := repo.SumByGoal(user.ID, goal.ID)
+	if err != nil {
+		t.Fatalf("SumByGoal: %v", err)
+	}
+	if saved != mustMoney(t, "100") || count != 1 {
+		t.Errorf("saved %s in %d contributions, want 100 in 1", saved, count)
+	}
+}
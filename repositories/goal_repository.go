@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type GoalRepository struct {
+	db *gorm.DB
+}
+
+func NewGoalRepository(db *gorm.DB) *GoalRepository {
+	return &GoalRepository{db: db}
+}
+
+func (r *GoalRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Goal, int64, int64, error) {
+	var goals []models.Goal
+	var total int64
+
+	query := r.db.Model(&models.Goal{}).Where("user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "name":
+			query = query.Where("name ILIKE ?", "%"+value+"%")
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Preload("Account").Limit(queryParams.Limit).Offset(offset).Find(&goals).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &goals, total, totalPages, nil
+}
+
+func (r *GoalRepository) GetByID(id uint) (*models.Goal, error) {
+	var goal models.Goal
+
+	err := r.db.Preload("Account").Where("id = ?", id).First(&goal).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &goal, nil
+}
+
+func (r *GoalRepository) Create(goal *models.Goal) error {
+	return r.db.Omit("Account").Create(goal).Error
+}
+
+func (r *GoalRepository) Update(goal *models.Goal) error {
+	return r.db.Omit("Account").Save(goal).Error
+}
+
+// DELETE A GOAL, ITS CONTRIBUTIONS ARE KEPT AS ORDINARY EXPENSES
+func (r *GoalRepository) Delete(goal *models.Goal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Expense{}).Where("goal_id = ?", goal.ID).Update("goal_id", nil).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(goal).Error
+	})
+}
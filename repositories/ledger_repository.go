@@ -0,0 +1,231 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type LedgerRepository struct {
+	db *gorm.DB
+}
+
+func NewLedgerRepository(db *gorm.DB) *LedgerRepository {
+	return &LedgerRepository{db: db}
+}
+
+// LEDGERS THE USER IS A MEMBER OF, WITH THE ROLE OF THE USER IN EACH
+func (r *LedgerRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Ledger, int64, int64, error) {
+	var ledgers []models.Ledger
+	var total int64
+
+	query := r.db.Model(&models.Ledger{}).
+		Joins("JOIN ledger_members ON ledger_members.ledger_id = ledgers.id").
+		Where("ledger_members.user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "name":
+			query = query.Where("ledgers.name ILIKE ?", "%"+value+"%")
+		case "role":
+			query = query.Where("ledger_members.role = ?", value)
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order("ledgers." + queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Select("ledgers.*, ledger_members.role AS role").Limit(queryParams.Limit).Offset(offset).Find(&ledgers).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &ledgers, total, totalPages, nil
+}
+
+func (r *LedgerRepository) GetByID(id uint) (*models.Ledger, error) {
+	var ledger models.Ledger
+
+	err := r.db.Preload("Members", func(db *gorm.DB) *gorm.DB {
+		return db.Order("ledger_members.id")
+	}).Preload("Members.User").Where("id = ?", id).First(&ledger).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &ledger, nil
+}
+
+// CREATE THE LEDGER WITH ITS MEMBERS, THE CREATOR IS ADDED AS OWNER BY THE CALLER
+func (r *LedgerRepository) Create(ledger *models.Ledger) error {
+	return r.db.Omit("Members.User", "Members.Ledger").Create(ledger).Error
+}
+
+func (r *LedgerRepository) Update(ledger *models.Ledger) error {
+	return r.db.Omit("Members").Save(ledger).Error
+}
+
+// DELETE THE LEDGER WITH ITS MEMBERS, INVITATIONS, SETTLEMENTS AND CATEGORIES
+func (r *LedgerRepository) Delete(ledger *models.Ledger) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("ledger_id = ?", ledger.ID).Delete(&models.Settlement{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ledger_id = ?", ledger.ID).Delete(&models.Category{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ledger_id = ?", ledger.ID).Delete(&models.LedgerInvitation{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("ledger_id = ?", ledger.ID).Delete(&models.LedgerMember{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(ledger).Error
+	})
+}
+
+// NUMBER OF EXPENSES IN THE LEDGER, DELETED ONES INCLUDED AS THEY CAN BE RESTORED
+func (r *LedgerRepository) CountExpenses(ledgerID uint) (int64, error) {
+	var count int64
+
+	err := r.db.Unscoped().Model(&models.Expense{}).Where("ledger_id = ?", ledgerID).Count(&count).Error
+
+	return count, err
+}
+
+// POSITION OF EVERY MEMBER IN THE SHARED EXPENSES AND SETTLEMENTS OF THE LEDGER, IN ITS BASE CURRENCY
+func (r *LedgerRepository) MemberBalances(ledgerID uint) ([]models.LedgerMemberBalance, error) {
+	var balances []models.LedgerMemberBalance
+
+	err := r.db.Table("ledger_members").
+		Select(`ledger_members.user_id,
+			users.name,
+			users.email,
+			(SELECT COALESCE(SUM(expenses.base_amount), 0) FROM expenses WHERE expenses.ledger_id = ledger_members.ledger_id AND expenses.paid_by_id = ledger_members.user_id AND expenses.deleted_at IS NULL) AS paid,
+			(SELECT COALESCE(SUM(expense_shares.base_amount), 0) FROM expense_shares JOIN expenses ON expenses.id = expense_shares.expense_id WHERE expenses.ledger_id = ledger_members.ledger_id AND expense_shares.user_id = ledger_members.user_id AND expenses.deleted_at IS NULL) AS owed,
+			(SELECT COALESCE(SUM(settlements.amount), 0) FROM settlements WHERE settlements.ledger_id = ledger_members.ledger_id AND settlements.from_user_id = ledger_members.user_id) AS settlements_paid,
+			(SELECT COALESCE(SUM(settlements.amount), 0) FROM settlements WHERE settlements.ledger_id = ledger_members.ledger_id AND settlements.to_user_id = ledger_members.user_id) AS settlements_received`).
+		Joins("JOIN users ON users.id = ledger_members.user_id").
+		Where("ledger_members.ledger_id = ?", ledgerID).
+		Order("ledger_members.id").
+		Scan(&balances).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range balances {
+		balances[i].Balance = balances[i].Paid - balances[i].Owed + balances[i].SettlementsPaid - balances[i].SettlementsReceived
+	}
+
+	return balances, nil
+}
+
+// MEMBERSHIP OF A USER IN A LEDGER, WITH THE LEDGER
+func (r *LedgerRepository) GetMember(ledgerID, userID uint) (*models.LedgerMember, error) {
+	var member models.LedgerMember
+
+	err := r.db.Preload("Ledger").Where("ledger_id = ? AND user_id = ?", ledgerID, userID).First(&member).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &member, nil
+}
+
+func (r *LedgerRepository) UpdateMemberRole(member *models.LedgerMember) error {
+	return r.db.Model(member).Update("role", member.Role).Error
+}
+
+func (r *LedgerRepository) DeleteMember(member *models.LedgerMember) error {
+	return r.db.Delete(member).Error
+}
+
+func (r *LedgerRepository) CreateInvitation(invitation *models.LedgerInvitation) error {
+	return r.db.Omit("Ledger").Create(invitation).Error
+}
+
+func (r *LedgerRepository) GetInvitationByID(id uint) (*models.LedgerInvitation, error) {
+	var invitation models.LedgerInvitation
+
+	err := r.db.Preload("Ledger").Where("id = ?", id).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+// PENDING INVITATION OF AN EMAIL ADDRESS TO A LEDGER, EMAILS ARE COMPARED CASE INSENSITIVELY
+func (r *LedgerRepository) GetInvitationByEmail(ledgerID uint, email string) (*models.LedgerInvitation, error) {
+	var invitation models.LedgerInvitation
+
+	err := r.db.Where("ledger_id = ? AND LOWER(email) = LOWER(?)", ledgerID, email).First(&invitation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &invitation, nil
+}
+
+func (r *LedgerRepository) GetInvitationsByLedgerID(ledgerID uint) ([]models.LedgerInvitation, error) {
+	var invitations []models.LedgerInvitation
+
+	err := r.db.Where("ledger_id = ?", ledgerID).Order("id").Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+// PENDING INVITATIONS SENT TO AN EMAIL ADDRESS, WITH THEIR LEDGERS
+func (r *LedgerRepository) GetInvitationsByEmail(email string) ([]models.LedgerInvitation, error) {
+	var invitations []models.LedgerInvitation
+
+	err := r.db.Preload("Ledger").Where("LOWER(email) = LOWER(?)", email).Order("id").Find(&invitations).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return invitations, nil
+}
+
+func (r *LedgerRepository) DeleteInvitation(invitation *models.LedgerInvitation) error {
+	return r.db.Delete(invitation).Error
+}
+
+// ADD THE MEMBER AND CONSUME THE INVITATION ATOMICALLY
+func (r *LedgerRepository) AcceptInvitation(invitation *models.LedgerInvitation, member *models.LedgerMember) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("User", "Ledger").Create(member).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(invitation).Error
+	})
+}
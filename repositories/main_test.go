@@ -0,0 +1,49 @@
+package repositories
+
+import (
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"go-expense-tracker-api/database"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+var migrateTestDB sync.Once
+
+// OPEN THE POSTGRES DATABASE NAMED BY TEST_DATABASE_DSN, MIGRATED ONCE PER RUN
+// EVERY TEST RUNS IN A TRANSACTION ROLLED BACK WHEN IT ENDS, TESTS ARE SKIPPED WITHOUT A DATABASE
+func testDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_DSN")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_DSN is not set")
+	}
+
+	db, err := gorm.Open(postgres.Open(dsn), &gorm.Config{Logger: logger.Default.LogMode(logger.Silent)})
+	if err != nil {
+		t.Fatalf("failed to connect to the test database: %v", err)
+	}
+
+	migrateTestDB.Do(func() {
+		database.DB = db
+		database.AutoMigrate()
+	})
+
+	tx := db.Begin()
+	if tx.Error != nil {
+		t.Fatalf("failed to begin a test transaction: %v", tx.Error)
+	}
+	t.Cleanup(func() { tx.Rollback() })
+
+	return tx
+}
+
+func uintString(value uint) string {
+	return strconv.FormatUint(uint64(value), 10)
+}
@@ -0,0 +1,93 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+func (r *NotificationRepository) Create(notification *models.Notification) error {
+	return r.db.Create(notification).Error
+}
+
+func (r *NotificationRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Notification, int64, int64, error) {
+	var notifications []models.Notification
+	var total int64
+
+	query := r.db.Model(&models.Notification{}).Where("user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "type":
+			query = query.Where("type = ?", value)
+		case "is_read":
+			query = query.Where("is_read = ?", value == "true")
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Limit(queryParams.Limit).Offset(offset).Find(&notifications).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &notifications, total, totalPages, nil
+}
+
+func (r *NotificationRepository) GetByID(id uint) (*models.Notification, error) {
+	var notification models.Notification
+
+	err := r.db.Where("id = ?", id).First(&notification).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &notification, nil
+}
+
+func (r *NotificationRepository) MarkAsRead(notification *models.Notification) error {
+	notification.IsRead = true
+	return r.db.Model(notification).Update("is_read", true).Error
+}
+
+func (r *NotificationRepository) MarkAllAsRead(userID uint) (int64, error) {
+	result := r.db.Model(&models.Notification{}).Where("user_id = ? AND is_read = ?", userID, false).Update("is_read", true)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+	return result.RowsAffected, nil
+}
@@ -0,0 +1,143 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+type PayeeRepository struct {
+	db *gorm.DB
+}
+
+func NewPayeeRepository(db *gorm.DB) *PayeeRepository {
+	return &PayeeRepository{db: db}
+}
+
+func (r *PayeeRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Payee, int64, int64, error) {
+	var payees []models.Payee
+	var total int64
+
+	query := r.db.Model(&models.Payee{}).Where("user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "name":
+			query = query.Where("name ILIKE ?", "%"+value+"%")
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Preload("Aliases").Limit(queryParams.Limit).Offset(offset).Find(&payees).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &payees, total, totalPages, nil
+}
+
+func (r *PayeeRepository) GetAllWithAliasesByUserID(userID uint) ([]models.Payee, error) {
+	var payees []models.Payee
+
+	err := r.db.Preload("Aliases").Where("user_id = ?", userID).Order("id").Find(&payees).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return payees, nil
+}
+
+func (r *PayeeRepository) GetByID(id uint) (*models.Payee, error) {
+	var payee models.Payee
+
+	err := r.db.Preload("Aliases").Where("id = ?", id).First(&payee).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &payee, nil
+}
+
+func (r *PayeeRepository) GetByName(userID uint, name string) (*models.Payee, error) {
+	var payee models.Payee
+
+	err := r.db.Where("user_id = ? AND LOWER(name) = LOWER(?)", userID, name).First(&payee).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &payee, nil
+}
+
+func (r *PayeeRepository) Create(payee *models.Payee) error {
+	return r.db.Create(payee).Error
+}
+
+// SAVE THE PAYEE AND REPLACE ITS ALIASES WITH payee.Aliases
+func (r *PayeeRepository) Update(payee *models.Payee) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit("Aliases").Save(payee).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("payee_id = ?", payee.ID).Delete(&models.PayeeAlias{}).Error; err != nil {
+			return err
+		}
+
+		for i := range payee.Aliases {
+			payee.Aliases[i].ID = 0
+			payee.Aliases[i].PayeeID = payee.ID
+		}
+		if len(payee.Aliases) == 0 {
+			return nil
+		}
+
+		return tx.Create(&payee.Aliases).Error
+	})
+}
+
+// DELETE A PAYEE AND ITS ALIASES, ITS EXPENSES ARE KEPT WITHOUT A PAYEE AND ITS CATEGORIZATION RULES ARE TURNED OFF
+func (r *PayeeRepository) Delete(payee *models.Payee) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Unscoped().Model(&models.Expense{}).Where("payee_id = ?", payee.ID).Update("payee_id", nil).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("payee_id = ?", payee.ID).Delete(&models.PayeeAlias{}).Error; err != nil {
+			return err
+		}
+
+		// WITHOUT ITS PAYEE CONDITION A RULE WOULD MATCH MORE THAN INTENDED
+		if err := tx.Model(&models.CategorizationRule{}).Where("payee_id = ?", payee.ID).Updates(map[string]any{"payee_id": nil, "is_active": false}).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(payee).Error
+	})
+}
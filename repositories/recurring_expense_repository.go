@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"errors"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type RecurringExpenseRepository struct {
+	db *gorm.DB
+}
+
+func NewRecurringExpenseRepository(db *gorm.DB) *RecurringExpenseRepository {
+	return &RecurringExpenseRepository{db: db}
+}
+
+func (r *RecurringExpenseRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.RecurringExpense, int64, int64, error) {
+	var recurringExpenses []models.RecurringExpense
+	var total int64
+
+	query := r.db.Model(&models.RecurringExpense{}).Where("user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "name":
+			query = query.Where("name ILIKE ?", "%"+value+"%")
+		case "frequency":
+			query = query.Where("frequency = ?", value)
+		case "is_active":
+			query = query.Where("is_active = ?", value == "true")
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Preload("Category", preloadEffectiveCategory(r.db, userID)).Limit(queryParams.Limit).Offset(offset).Find(&recurringExpenses).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &recurringExpenses, total, totalPages, nil
+}
+
+// A RECURRING EXPENSE BY ID, ITS CATEGORY NAMED AS userID SEES IT
+func (r *RecurringExpenseRepository) GetByID(id, userID uint) (*models.RecurringExpense, error) {
+	var recurringExpense models.RecurringExpense
+
+	err := r.db.Preload("Category", preloadEffectiveCategory(r.db, userID)).Where("id = ?", id).First(&recurringExpense).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &recurringExpense, nil
+}
+
+func (r *RecurringExpenseRepository) Create(recurringExpense *models.RecurringExpense) error {
+	return r.db.Omit("Category").Create(recurringExpense).Error
+}
+
+func (r *RecurringExpenseRepository) Update(recurringExpense *models.RecurringExpense) error {
+	return r.db.Omit("Category").Save(recurringExpense).Error
+}
+
+func (r *RecurringExpenseRepository) Delete(recurringExpense *models.RecurringExpense) error {
+	return r.db.Delete(recurringExpense).Error
+}
+
+// GET IDS OF ACTIVE RECURRING EXPENSES WITH AN OCCURRENCE DUE AT OR BEFORE now
+func (r *RecurringExpenseRepository) GetDueIDs(now time.Time) ([]uint, error) {
+	var ids []uint
+
+	err := r.db.Model(&models.RecurringExpense{}).
+		Where("is_active = ? AND next_run_at IS NOT NULL AND next_run_at <= ?", true, now).
+		Order("next_run_at").
+		Pluck("id", &ids).Error
+
+	return ids, err
+}
+
+// LOCK A RECURRING EXPENSE, INSERT THE EXPENSES RETURNED BY generate AND SAVE ITS SCHEDULE IN ONE TRANSACTION
+// OCCURRENCES THAT ALREADY EXIST ARE SKIPPED, ROWS LOCKED BY ANOTHER WORKER ARE LEFT ALONE
+func (r *RecurringExpenseRepository) Materialize(id uint, generate func(recurringExpense *models.RecurringExpense) ([]*models.Expense, error)) (int64, error) {
+	var created int64
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var recurringExpense models.RecurringExpense
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("id = ?", id).
+			First(&recurringExpense).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		expenses, err := generate(&recurringExpense)
+		if err != nil {
+			return err
+		}
+
+		if len(expenses) > 0 {
+			result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&expenses)
+			if result.Error != nil {
+				return result.Error
+			}
+			created = result.RowsAffected
+		}
+
+		return tx.Model(&recurringExpense).
+			Select("next_run_at", "occurrence_count").
+			Updates(&recurringExpense).Error
+	})
+
+	return created, err
+}
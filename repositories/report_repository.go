@@ -0,0 +1,144 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// REPORTS COVER THE EXPENSES OF ledgerID, OR THE PERSONAL EXPENSES OF userID WHEN IT IS NIL
+// CONTRIBUTIONS TO SAVINGS GOALS ARE NOT SPENDING AND ARE LEFT OUT
+type ReportRepository struct {
+	db *gorm.DB
+}
+
+func NewReportRepository(db *gorm.DB) *ReportRepository {
+	return &ReportRepository{db: db}
+}
+
+// AGGREGATE BASE AMOUNTS PER CATEGORY SPENT IN [from, to), LARGEST TOTAL FIRST
+// SPLIT EXPENSES COUNT EACH LINE UNDER ITS OWN CATEGORY, OR UNDER ITS TOP LEVEL CATEGORY WITH rollup
+func (r *ReportRepository) SummaryByCategory(userID uint, ledgerID *uint, from, to time.Time, rollup bool) ([]models.CategorySummary, error) {
+	var summaries []models.CategorySummary
+
+	err := r.joinCategories(scopeExpenses(spendingLines(r.db), userID, ledgerID), userID, rollup).
+		Select(`categories.id AS category_id,
+			categories.name AS category_name,
+			categories.type AS category_type,
+			COUNT(*) AS count,
+			SUM(expenses.base_amount) AS total,
+			AVG(expenses.base_amount) AS average,
+			MIN(expenses.base_amount) AS min,
+			MAX(expenses.base_amount) AS max`).
+		Where("expenses.spent_at >= ? AND expenses.spent_at < ?", from, to).
+		Group("categories.id, categories.name, categories.type").
+		Order("total DESC").
+		Scan(&summaries).Error
+
+	return summaries, err
+}
+
+// SUM BASE AMOUNTS PER date_trunc(interval) BUCKET IN timezone AND CATEGORY, SPENT IN [from, to)
+// BUCKETS ARE RETURNED AS WALL CLOCK TIMES OF timezone, categoryID 0 MEANS ALL CATEGORIES, OTHERWISE IT INCLUDES ITS SUBCATEGORIES
+// WITH rollup SUBCATEGORIES ARE COUNTED UNDER THEIR TOP LEVEL CATEGORY
+func (r *ReportRepository) TrendByCategory(userID uint, ledgerID *uint, interval, timezone string, from, to time.Time, categoryID uint, rollup bool) ([]models.TrendCategoryTotal, error) {
+	var totals []models.TrendCategoryTotal
+
+	query := r.joinCategories(scopeExpenses(spendingLines(r.db), userID, ledgerID), userID, rollup).
+		Select(`date_trunc(?, expenses.spent_at AT TIME ZONE ?) AS bucket,
+			categories.id AS category_id,
+			categories.name AS category_name,
+			categories.type AS category_type,
+			COUNT(*) AS count,
+			SUM(expenses.base_amount) AS total`, interval, timezone).
+		Where("expenses.spent_at >= ? AND expenses.spent_at < ?", from, to)
+
+	if categoryID != 0 {
+		query = query.Where("expenses.category_id IN ("+categoryDescendants+")", categoryID)
+	}
+
+	err := query.
+		Group("bucket, categories.id, categories.name, categories.type").
+		Order("bucket, categories.id").
+		Scan(&totals).Error
+
+	return totals, err
+}
+
+// SUM BASE AMOUNTS PER TAG SPENT IN [from, to), SPLIT BY CATEGORY TYPE, LARGEST EXPENSE FIRST
+func (r *ReportRepository) SummaryByTag(userID uint, ledgerID *uint, from, to time.Time) ([]models.TagSummary, error) {
+	var summaries []models.TagSummary
+
+	err := scopeExpenses(spendingLines(r.db), userID, ledgerID).
+		Select(`tags.id AS tag_id,
+			tags.name AS tag_name,
+			COUNT(DISTINCT expenses.id) AS count,
+			COALESCE(SUM(expenses.base_amount) FILTER (WHERE categories.type = 'income'), 0) AS income,
+			COALESCE(SUM(expenses.base_amount) FILTER (WHERE categories.type <> 'income'), 0) AS expense`).
+		Joins("JOIN expense_tags ON expense_tags.expense_id = expenses.id").
+		Joins("JOIN tags ON tags.id = expense_tags.tag_id").
+		Joins("JOIN categories ON categories.id = expenses.category_id").
+		Where("expenses.spent_at >= ? AND expenses.spent_at < ?", from, to).
+		Group("tags.id, tags.name").
+		Order("expense DESC, tags.name").
+		Scan(&summaries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range summaries {
+		summaries[i].Net = summaries[i].Income - summaries[i].Expense
+	}
+
+	return summaries, nil
+}
+
+// SUM BASE AMOUNTS PER PAYEE OF ONE CATEGORY TYPE SPENT IN [from, to), LARGEST TOTAL FIRST
+// EXPENSES WITHOUT A PAYEE ARE GROUPED BY THEIR RAW NAME
+func (r *ReportRepository) SummaryByPayee(userID uint, ledgerID *uint, categoryType string, from, to time.Time, limit int) ([]models.PayeeSummary, error) {
+	var summaries []models.PayeeSummary
+
+	err := scopeExpenses(spendingLines(r.db), userID, ledgerID).
+		Select(`payees.id AS payee_id,
+			COALESCE(payees.name, expenses.name) AS payee_name,
+			COUNT(DISTINCT expenses.id) AS count,
+			SUM(expenses.base_amount) AS total`).
+		Joins("JOIN categories ON categories.id = expenses.category_id").
+		Joins("LEFT JOIN payees ON payees.id = expenses.payee_id").
+		Where("categories.type = ? AND expenses.spent_at >= ? AND expenses.spent_at < ?", categoryType, from, to).
+		Group("payees.id, COALESCE(payees.name, expenses.name)").
+		Order("total DESC, payee_name").
+		Limit(limit).
+		Scan(&summaries).Error
+
+	return summaries, err
+}
+
+// SUM BASE AMOUNTS OF ONE CATEGORY TYPE SPENT IN [from, to)
+func (r *ReportRepository) TotalByCategoryType(userID uint, ledgerID *uint, categoryType string, from, to time.Time) (models.Money, error) {
+	var total models.Money
+
+	err := scopeExpenses(spendingLines(r.db), userID, ledgerID).
+		Select("COALESCE(SUM(expenses.base_amount), 0)").
+		Joins("JOIN categories ON categories.id = expenses.category_id").
+		Where("categories.type = ? AND expenses.spent_at >= ? AND expenses.spent_at < ?", categoryType, from, to).
+		Row().Scan(&total)
+
+	return total, err
+}
+
+// JOIN THE CATEGORY OF EVERY EXPENSE LINE AS categories, OR ITS TOP LEVEL CATEGORY WITH rollup
+// CATEGORIES ARE NAMED AS userID SEES THEM
+func (r *ReportRepository) joinCategories(query *gorm.DB, userID uint, rollup bool) *gorm.DB {
+	// NO deleted_at CONDITION ON PURPOSE, AN EXPENSE STILL ON A DELETED CATEGORY KEEPS COUNTING UNDER IT
+	// SO THE TOTALS MATCH THE EXPENSE LIST, WHICH SHOWS THE DELETED CATEGORY TOO
+	categories := effectiveCategories(r.db, userID)
+	if rollup {
+		return query.
+			Joins("JOIN "+categoryRoots+" ON category_roots.id = expenses.category_id").
+			Joins("JOIN (?) AS categories ON categories.id = category_roots.root_id", categories)
+	}
+
+	return query.Joins("JOIN (?) AS categories ON categories.id = expenses.category_id", categories)
+}
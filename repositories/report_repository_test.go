@@ -0,0 +1,175 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+)
+
+// A DEFAULT PARENT WITH A PERSONAL SUBCATEGORY OF THE USER AND ONE OF ANOTHER USER, ONE EXPENSE OF EACH USER IN THEIR SUBCATEGORY
+func createDefaultCategoryTree(t *testing.T, repo *ExpenseRepository) (*models.User, *models.Category, *models.Category) {
+	t.Helper()
+
+	user, _ := createTestUser(t, repo, "EUR")
+	otherUser, _ := createTestUser(t, repo, "EUR")
+
+	transport := &models.Category{Name: "Transport", Type: "expense", IsDefault: true}
+	if err := repo.db.Create(transport).Error; err != nil {
+		t.Fatalf("failed to create default category: %v", err)
+	}
+
+	for _, owner := range []*models.User{user, otherUser} {
+		fuel := &models.Category{Name: "Fuel", Type: "expense", UserID: &owner.ID, ParentID: &transport.ID}
+		if err := repo.db.Create(fuel).Error; err != nil {
+			t.Fatalf("failed to create subcategory: %v", err)
+		}
+
+		expense := &models.Expense{Name: "Shell", Amount: mustMoney(t, "40"), Currency: "EUR", BaseAmount: mustMoney(t, "40"), BaseCurrency: "EUR",
+			SpentAt: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), UserID: owner.ID, CategoryID: fuel.ID}
+		if err := repo.db.Omit("Category").Create(expense).Error; err != nil {
+			t.Fatalf("failed to create expense: %v", err)
+		}
+	}
+
+	var fuel models.Category
+	if err := repo.db.Where("user_id = ? AND parent_id = ?", user.ID, transport.ID).First(&fuel).Error; err != nil {
+		t.Fatalf("failed to reload subcategory: %v", err)
+	}
+
+	return user, transport, &fuel
+}
+
+func TestCategoryFilterIncludesSubcategoriesOfDefaultCategory(t *testing.T) {
+	repo := NewExpenseRepository(testDB(t))
+	user, transport, fuel := createDefaultCategoryTree(t, repo)
+
+	expenses, total, _, err := repo.GetByUserID(user.ID, middleware.QueryParams{
+		Page:    1,
+		Limit:   10,
+		Filters: map[string]string{"category_id": uintString(transport.ID)},
+	})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+
+	// ONLY THE EXPENSE OF THE USER, NOT THE ONE OF THE OTHER USER IN THEIR OWN SUBCATEGORY
+	if total != 1 || len(*expenses) != 1 || (*expenses)[0].CategoryID != fuel.ID {
+		t.Errorf("got %d expenses (total %d), want the one expense in %d", len(*expenses), total, fuel.ID)
+	}
+}
+
+func TestSummaryRollsUpToDefaultCategory(t *testing.T) {
+	db := testDB(t)
+	user, transport, fuel := createDefaultCategoryTree(t, NewExpenseRepository(db))
+	reports := NewReportRepository(db)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	for _, tt := range []struct {
+		rollup bool
+		want   uint
+	}{
+		{rollup: false, want: fuel.ID},
+		{rollup: true, want: transport.ID},
+	} {
+		summaries, err := reports.SummaryByCategory(user.ID, nil, from, to, tt.rollup)
+		if err != nil {
+			t.Fatalf("SummaryByCategory: %v", err)
+		}
+
+		if len(summaries) != 1 || summaries[0].CategoryID != tt.want || summaries[0].Total != mustMoney(t, "40") {
+			t.Errorf("rollup %v: summaries = %+v, want 40 under category %d", tt.rollup, summaries, tt.want)
+		}
+	}
+}
+
+func TestRenamedDefaultCategoryKeepsTheNameOfTheUser(t *testing.T) {
+	db := testDB(t)
+	expenses := NewExpenseRepository(db)
+	user, transport, _ := createDefaultCategoryTree(t, expenses)
+
+	if err := db.Create(&models.CategoryOverride{UserID: user.ID, CategoryID: transport.ID, Name: "Commute"}).Error; err != nil {
+		t.Fatalf("failed to create override: %v", err)
+	}
+
+	summaries, err := NewReportRepository(db).SummaryByCategory(user.ID, nil, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), true)
+	if err != nil {
+		t.Fatalf("SummaryByCategory: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].CategoryName != "Commute" {
+		t.Errorf("summaries = %+v, want one under Commute", summaries)
+	}
+
+	expense := &models.Expense{Name: "Bus", Amount: mustMoney(t, "2"), Currency: "EUR", BaseAmount: mustMoney(t, "2"), BaseCurrency: "EUR",
+		SpentAt: time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), UserID: user.ID, CategoryID: transport.ID}
+	if err := db.Omit("Category").Create(expense).Error; err != nil {
+		t.Fatalf("failed to create expense: %v", err)
+	}
+
+	loaded, err := expenses.GetByID(expense.ID, user.ID)
+	if err != nil {
+		t.Fatalf("GetByID: %v", err)
+	}
+	if loaded.Category.Name != "Commute" {
+		t.Errorf("expense category = %q, want Commute", loaded.Category.Name)
+	}
+
+	for _, name := range []string{"commute", "Transport"} {
+		found, total, _, err := expenses.GetByUserID(user.ID, middleware.QueryParams{Page: 1, Limit: 10, Filters: map[string]string{"category_name": name}})
+		if err != nil {
+			t.Fatalf("GetByUserID: %v", err)
+		}
+		want := int64(0)
+		if name == "commute" {
+			want = 1
+		}
+		if total != want || (want == 1 && (*found)[0].Category.Name != "Commute") {
+			t.Errorf("category_name %q: got %d expenses, want %d named Commute", name, total, want)
+		}
+	}
+
+	budget := &models.Budget{UserID: user.ID, CategoryID: transport.ID, Amount: mustMoney(t, "100"), Period: "monthly"}
+	if err := db.Omit("Category").Create(budget).Error; err != nil {
+		t.Fatalf("failed to create budget: %v", err)
+	}
+
+	budgets, total, _, err := NewBudgetRepository(db).GetByUserID(user.ID, middleware.QueryParams{Page: 1, Limit: 10, Filters: map[string]string{"category_name": "commute"}})
+	if err != nil {
+		t.Fatalf("GetByUserID: %v", err)
+	}
+	if total != 1 || (*budgets)[0].Category.Name != "Commute" {
+		t.Errorf("got %d budgets, want the one on Commute", total)
+	}
+}
+
+func TestSummaryKeepsExpensesOfDeletedCategories(t *testing.T) {
+	db := testDB(t)
+	user, transport, fuel := createDefaultCategoryTree(t, NewExpenseRepository(db))
+
+	if err := db.Delete(fuel).Error; err != nil {
+		t.Fatalf("failed to delete category: %v", err)
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+
+	for _, tt := range []struct {
+		rollup bool
+		want   uint
+	}{
+		{rollup: false, want: fuel.ID},
+		{rollup: true, want: transport.ID},
+	} {
+		summaries, err := NewReportRepository(db).SummaryByCategory(user.ID, nil, from, to, tt.rollup)
+		if err != nil {
+			t.Fatalf("SummaryByCategory: %v", err)
+		}
+
+		if len(summaries) != 1 || summaries[0].CategoryID != tt.want || summaries[0].Total != mustMoney(t, "40") {
+			t.Errorf("rollup %v: summaries = %+v, want 40 under category %d", tt.rollup, summaries, tt.want)
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package repositories
+
+import (
+	"fmt"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type SettlementRepository struct {
+	db *gorm.DB
+}
+
+func NewSettlementRepository(db *gorm.DB) *SettlementRepository {
+	return &SettlementRepository{db: db}
+}
+
+func (r *SettlementRepository) GetByLedgerID(ledgerID uint, queryParams middleware.QueryParams) (*[]models.Settlement, int64, int64, error) {
+	var settlements []models.Settlement
+	var total int64
+
+	query := r.db.Model(&models.Settlement{}).Where("ledger_id = ?", ledgerID)
+
+	loc := queryParams.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "user_id":
+			userID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: user_id must be a number", ErrInvalidFilter)
+			}
+			query = query.Where("from_user_id = ? OR to_user_id = ?", userID, userID)
+		case "from":
+			from, _, err := utils.ParseDateTime(value, loc)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: from: %v", ErrInvalidFilter, err)
+			}
+			query = query.Where("settled_at >= ?", from)
+		case "to":
+			to, dateOnly, err := utils.ParseDateTime(value, loc)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: to: %v", ErrInvalidFilter, err)
+			}
+			// A PLAIN DATE INCLUDES THE WHOLE DAY
+			if dateOnly {
+				query = query.Where("settled_at < ?", to.AddDate(0, 0, 1))
+			} else {
+				query = query.Where("settled_at <= ?", to)
+			}
+		case "note":
+			query = query.Where("note ILIKE ?", "%"+value+"%")
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Limit(queryParams.Limit).Offset(offset).Find(&settlements).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &settlements, total, totalPages, nil
+}
+
+func (r *SettlementRepository) GetByID(id uint) (*models.Settlement, error) {
+	var settlement models.Settlement
+
+	err := r.db.Where("id = ?", id).First(&settlement).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &settlement, nil
+}
+
+func (r *SettlementRepository) Create(settlement *models.Settlement) error {
+	return r.db.Create(settlement).Error
+}
+
+func (r *SettlementRepository) Delete(settlement *models.Settlement) error {
+	return r.db.Delete(settlement).Error
+}
@@ -0,0 +1,135 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type TagRepository struct {
+	db *gorm.DB
+}
+
+func NewTagRepository(db *gorm.DB) *TagRepository {
+	return &TagRepository{db: db}
+}
+
+func (r *TagRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Tag, int64, int64, error) {
+	var tags []models.Tag
+	var total int64
+
+	query := r.db.Model(&models.Tag{}).Where("user_id = ?", userID)
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "name":
+			query = query.Where("name ILIKE ?", "%"+value+"%")
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Limit(queryParams.Limit).Offset(offset).Find(&tags).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &tags, total, totalPages, nil
+}
+
+func (r *TagRepository) GetByID(id uint) (*models.Tag, error) {
+	var tag models.Tag
+
+	err := r.db.Where("id = ?", id).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepository) GetByName(userID uint, name string) (*models.Tag, error) {
+	var tag models.Tag
+
+	err := r.db.Where("user_id = ? AND name = ?", userID, name).First(&tag).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &tag, nil
+}
+
+func (r *TagRepository) Create(tag *models.Tag) error {
+	return r.db.Create(tag).Error
+}
+
+func (r *TagRepository) Update(tag *models.Tag) error {
+	return r.db.Save(tag).Error
+}
+
+// DELETE A TAG AND REMOVE IT FROM ALL EXPENSES AND CATEGORIZATION RULES
+func (r *TagRepository) Delete(tag *models.Tag) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Exec("DELETE FROM expense_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Exec("DELETE FROM categorization_rule_tags WHERE tag_id = ?", tag.ID).Error; err != nil {
+			return err
+		}
+
+		return tx.Delete(tag).Error
+	})
+}
+
+// GET THE USER TAGS WITH THE GIVEN NORMALIZED NAMES, CREATING THE MISSING ONES
+func (r *TagRepository) FindOrCreateByNames(userID uint, names []string) ([]models.Tag, error) {
+	if len(names) == 0 {
+		return []models.Tag{}, nil
+	}
+
+	tags := make([]models.Tag, 0, len(names))
+	for _, name := range names {
+		tags = append(tags, models.Tag{UserID: userID, Name: name})
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&tags).Error; err != nil {
+			return err
+		}
+
+		tags = tags[:0]
+		return tx.Where("user_id = ? AND name IN ?", userID, names).Order("name").Find(&tags).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return tags, nil
+}
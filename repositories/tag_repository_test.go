@@ -0,0 +1,92 @@
+package repositories
+
+import (
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+)
+
+func TestFilterByTags(t *testing.T) {
+	repo := NewExpenseRepository(testDB(t))
+	user, category := createTestUser(t, repo, "EUR")
+
+	tags := make(map[string]models.Tag)
+	for _, name := range []string{"trip", "business"} {
+		tag := models.Tag{UserID: user.ID, Name: name}
+		if err := repo.db.Create(&tag).Error; err != nil {
+			t.Fatalf("failed to create tag: %v", err)
+		}
+		tags[name] = tag
+	}
+
+	expenses := map[string][]models.Tag{
+		"Hotel":   {tags["trip"]},
+		"Flight":  {tags["trip"], tags["business"]},
+		"Laptop":  {tags["business"]},
+		"Grocery": nil,
+	}
+	for name, expenseTags := range expenses {
+		expense := &models.Expense{
+			Name:         name,
+			Amount:       mustMoney(t, "10"),
+			Currency:     "EUR",
+			BaseAmount:   mustMoney(t, "10"),
+			BaseCurrency: "EUR",
+			SpentAt:      time.Date(2025, 1, 15, 12, 0, 0, 0, time.UTC),
+			UserID:       user.ID,
+			CategoryID:   category.ID,
+			Tags:         expenseTags,
+		}
+		if err := repo.db.Omit("Category").Create(expense).Error; err != nil {
+			t.Fatalf("failed to create expense: %v", err)
+		}
+	}
+
+	tests := []struct {
+		name    string
+		filters map[string]string
+		want    []string
+	}{
+		{name: "any by default", filters: map[string]string{"tags": "trip,business"}, want: []string{"Flight", "Hotel", "Laptop"}},
+		{name: "any", filters: map[string]string{"tags": "trip", "tags_mode": "any"}, want: []string{"Flight", "Hotel"}},
+		{name: "all", filters: map[string]string{"tags": "trip,business", "tags_mode": "all"}, want: []string{"Flight"}},
+		{name: "names are normalized", filters: map[string]string{"tags": " Trip ,BUSINESS", "tags_mode": "all"}, want: []string{"Flight"}},
+		{name: "repeated names count once", filters: map[string]string{"tags": "trip,trip", "tags_mode": "all"}, want: []string{"Flight", "Hotel"}},
+		{name: "unknown tag", filters: map[string]string{"tags": "trip,camping", "tags_mode": "all"}, want: nil},
+		{name: "no names", filters: map[string]string{"tags": " , ", "tags_mode": "all"}, want: []string{"Flight", "Grocery", "Hotel", "Laptop"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, _, _, err := repo.GetByUserID(user.ID, middleware.QueryParams{Page: 1, Limit: 10, Filters: tt.filters})
+			if err != nil {
+				t.Fatalf("GetByUserID: %v", err)
+			}
+
+			var got []string
+			for _, expense := range *found {
+				got = append(got, expense.Name)
+			}
+			sort.Strings(got)
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("expenses = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i] != tt.want[i] {
+					t.Errorf("expenses = %v, want %v", got, tt.want)
+					break
+				}
+			}
+		})
+	}
+
+	_, _, _, err := repo.GetByUserID(user.ID, middleware.QueryParams{Page: 1, Limit: 10, Filters: map[string]string{"tags": "trip", "tags_mode": "some"}})
+	if !errors.Is(err, ErrInvalidFilter) {
+		t.Errorf("GetByUserID() error = %v, want ErrInvalidFilter", err)
+	}
+}
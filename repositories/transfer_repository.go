@@ -0,0 +1,118 @@
+package repositories
+
+import (
+	"fmt"
+	"go-expense-tracker-api/middleware"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/utils"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type TransferRepository struct {
+	db *gorm.DB
+}
+
+func NewTransferRepository(db *gorm.DB) *TransferRepository {
+	return &TransferRepository{db: db}
+}
+
+func (r *TransferRepository) GetByUserID(userID uint, queryParams middleware.QueryParams) (*[]models.Transfer, int64, int64, error) {
+	var transfers []models.Transfer
+	var total int64
+
+	query := r.db.Model(&models.Transfer{}).Where("user_id = ?", userID)
+
+	loc := queryParams.Location
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	// APPLY FILTERS
+	for key, value := range queryParams.Filters {
+		if value == "" {
+			continue
+		}
+		switch key {
+		case "account_id":
+			accountID, err := strconv.ParseUint(value, 10, 32)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: account_id must be a number", ErrInvalidFilter)
+			}
+			query = query.Where("from_account_id = ? OR to_account_id = ?", accountID, accountID)
+		case "from":
+			from, _, err := utils.ParseDateTime(value, loc)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: from: %v", ErrInvalidFilter, err)
+			}
+			query = query.Where("transferred_at >= ?", from)
+		case "to":
+			to, dateOnly, err := utils.ParseDateTime(value, loc)
+			if err != nil {
+				return nil, 0, 0, fmt.Errorf("%w: to: %v", ErrInvalidFilter, err)
+			}
+			// A PLAIN DATE INCLUDES THE WHOLE DAY
+			if dateOnly {
+				query = query.Where("transferred_at < ?", to.AddDate(0, 0, 1))
+			} else {
+				query = query.Where("transferred_at <= ?", to)
+			}
+		case "note":
+			query = query.Where("note ILIKE ?", "%"+value+"%")
+		}
+	}
+
+	// COUNT TOTAL RECORDS
+	if err := query.Count(&total).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	// CALCULATE TOTAL PAGES
+	totalPages := int64(total) / int64(queryParams.Limit)
+	if int64(total)%int64(queryParams.Limit) != 0 {
+		totalPages++
+	}
+
+	// APPLY SORTING
+	if queryParams.SortBy != "" {
+		order := "asc"
+		if strings.ToLower(queryParams.Order) == "desc" {
+			order = "desc"
+		}
+		query = query.Order(queryParams.SortBy + " " + order)
+	}
+
+	// APPLY PAGINATION
+	offset := (queryParams.Page - 1) * queryParams.Limit
+	if err := query.Preload("FromAccount").Preload("ToAccount").Limit(queryParams.Limit).Offset(offset).Find(&transfers).Error; err != nil {
+		return nil, 0, 0, err
+	}
+
+	return &transfers, total, totalPages, nil
+}
+
+func (r *TransferRepository) GetByID(id uint) (*models.Transfer, error) {
+	var transfer models.Transfer
+
+	err := r.db.Preload("FromAccount").Preload("ToAccount").Where("id = ?", id).First(&transfer).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &transfer, nil
+}
+
+func (r *TransferRepository) Create(transfer *models.Transfer) error {
+	return r.db.Omit("FromAccount", "ToAccount").Create(transfer).Error
+}
+
+func (r *TransferRepository) Update(transfer *models.Transfer) error {
+	return r.db.Omit("FromAccount", "ToAccount").Save(transfer).Error
+}
+
+func (r *TransferRepository) Delete(transfer *models.Transfer) error {
+	return r.db.Delete(transfer).Error
+}
@@ -0,0 +1,81 @@
+package repositories
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+func TestTransfersMoveBalancesOutsideReports(t *testing.T) {
+	db := testDB(t)
+	accountRepo := NewAccountRepository(db)
+	expenseRepo := NewExpenseRepository(db)
+	transferRepo := NewTransferRepository(db)
+	user, groceries := createTestUser(t, expenseRepo, "EUR")
+
+	bank := createTestAccount(t, accountRepo, user.ID, "Bank", "EUR", "1000")
+	savings := createTestAccount(t, accountRepo, user.ID, "Savings", "EUR", "0")
+
+	asOf := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+	transfers := []*models.Transfer{
+		{Amount: mustMoney(t, "100"), ToAmount: mustMoney(t, "100"), Fee: mustMoney(t, "2.5"), TransferredAt: asOf.AddDate(0, 0, -3)},
+		// AFTER THE BALANCE
+		{Amount: mustMoney(t, "50"), ToAmount: mustMoney(t, "50"), TransferredAt: asOf},
+	}
+	for _, transfer := range transfers {
+		transfer.UserID, transfer.FromAccountID, transfer.ToAccountID = user.ID, bank.ID, savings.ID
+		if err := transferRepo.Create(transfer); err != nil {
+			t.Fatalf("failed to create transfer: %v", err)
+		}
+	}
+
+	expense := &models.Expense{
+		Name:         "Market",
+		Amount:       mustMoney(t, "10"),
+		Currency:     "EUR",
+		BaseAmount:   mustMoney(t, "10"),
+		BaseCurrency: "EUR",
+		SpentAt:      asOf.AddDate(0, 0, -2),
+		UserID:       user.ID,
+		CategoryID:   groceries.ID,
+		AccountID:    &bank.ID,
+	}
+	if err := db.Omit("Category").Create(expense).Error; err != nil {
+		t.Fatalf("failed to create expense: %v", err)
+	}
+
+	balances, err := accountRepo.Balances(user.ID, 0, asOf)
+	if err != nil {
+		t.Fatalf("Balances: %v", err)
+	}
+	if len(balances) != 2 {
+		t.Fatalf("got %d balances, want 2: %+v", len(balances), balances)
+	}
+
+	// THE FEE LEAVES THE SOURCE ACCOUNT ON TOP OF THE AMOUNT
+	if got := balances[0]; got.TransfersOut != mustMoney(t, "102.5") || got.TransfersIn != 0 || got.Balance != mustMoney(t, "887.5") {
+		t.Errorf("bank balance = %+v, want 102.5 out and 887.5 left", got)
+	}
+	if got := balances[1]; got.TransfersIn != mustMoney(t, "100") || got.TransfersOut != 0 || got.Balance != mustMoney(t, "100") {
+		t.Errorf("savings balance = %+v, want 100 in and 100 left", got)
+	}
+
+	// TRANSFERS ARE NEITHER SPENDING NOR INCOME
+	from := asOf.AddDate(0, -1, 0)
+	summaries, err := NewReportRepository(db).SummaryByCategory(user.ID, nil, from, asOf.AddDate(0, 0, 1), false)
+	if err != nil {
+		t.Fatalf("SummaryByCategory: %v", err)
+	}
+	if len(summaries) != 1 || summaries[0].CategoryID != groceries.ID || summaries[0].Total != mustMoney(t, "10") {
+		t.Errorf("summaries = %+v, want only the 10 on groceries", summaries)
+	}
+
+	spent, err := expenseRepo.SumByCategory(user.ID, groceries.ID, from, asOf.AddDate(0, 0, 1))
+	if err != nil {
+		t.Fatalf("SumByCategory: %v", err)
+	}
+	if spent != mustMoney(t, "10") {
+		t.Errorf("spent on groceries = %s, want 10 without the transfers", spent)
+	}
+}
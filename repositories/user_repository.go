@@ -36,3 +36,7 @@ func (r *UserRepository) GetByID(id uint) (*models.User, error) {
 	}
 	return &user, nil
 }
+
+func (r *UserRepository) UpdateSettings(userID uint, settings map[string]any) error {
+	return r.db.Model(&models.User{}).Where("id = ?", userID).Updates(settings).Error
+}
@@ -0,0 +1,36 @@
+package repositories
+
+import (
+	"go-expense-tracker-api/models"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type WebhookDeliveryRepository struct {
+	db *gorm.DB
+}
+
+func NewWebhookDeliveryRepository(db *gorm.DB) *WebhookDeliveryRepository {
+	return &WebhookDeliveryRepository{db: db}
+}
+
+func (r *WebhookDeliveryRepository) Create(delivery *models.WebhookDelivery) error {
+	return r.db.Create(delivery).Error
+}
+
+func (r *WebhookDeliveryRepository) Update(delivery *models.WebhookDelivery) error {
+	return r.db.Save(delivery).Error
+}
+
+// UP TO limit PENDING DELIVERIES WHOSE NEXT ATTEMPT IS DUE AT now, LONGEST WAITING FIRST
+func (r *WebhookDeliveryRepository) GetDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+
+	err := r.db.Where("status = ? AND next_attempt_at <= ?", models.WebhookDeliveryPending, now).Order("next_attempt_at, id").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,180 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-expense-tracker-api/models"
+	"io"
+	"log"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+var (
+	ErrAttachmentTooLarge      = errors.New("attachment is too large")
+	ErrAttachmentTypeForbidden = errors.New("attachment type is not allowed")
+)
+
+// CONTENT TYPES ACCEPTED AS RECEIPTS, DETECTED FROM THE FILE CONTENT RATHER THAN TRUSTING THE CLIENT
+var allowedAttachmentTypes = map[string]bool{
+	"image/jpeg":      true,
+	"image/png":       true,
+	"image/gif":       true,
+	"image/webp":      true,
+	"application/pdf": true,
+}
+
+// IMPLEMENTED BY repositories.AttachmentRepository
+type AttachmentStore interface {
+	Create(attachment *models.Attachment) error
+	GetByExpenseID(expenseID uint) ([]models.Attachment, error)
+	Delete(attachment *models.Attachment) error
+}
+
+type AttachmentService struct {
+	store   AttachmentStore
+	storage Storage
+	maxSize int64
+}
+
+func NewAttachmentService(store AttachmentStore, storage Storage, maxSize int64) *AttachmentService {
+	return &AttachmentService{
+		store:   store,
+		storage: storage,
+		maxSize: maxSize,
+	}
+}
+
+func (s *AttachmentService) MaxSize() int64 {
+	return s.maxSize
+}
+
+// VALIDATE AND STORE AN UPLOADED FILE FOR AN EXPENSE
+func (s *AttachmentService) Upload(ctx context.Context, expense *models.Expense, fileName string, r io.Reader, size int64) (*models.Attachment, error) {
+	if size > s.maxSize {
+		return nil, fmt.Errorf("%w, the limit is %d bytes", ErrAttachmentTooLarge, s.maxSize)
+	}
+
+	// SNIFF THE CONTENT TYPE FROM THE FIRST 512 BYTES
+	head := make([]byte, 512)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !allowedAttachmentTypes[contentType] {
+		return nil, fmt.Errorf("%w: %s", ErrAttachmentTypeForbidden, contentType)
+	}
+
+	// READ ONE BYTE MORE THAN THE LIMIT TO CATCH A WRONG SIZE
+	content := io.LimitReader(io.MultiReader(bytes.NewReader(head), r), s.maxSize+1)
+	data, err := io.ReadAll(content)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > s.maxSize {
+		return nil, fmt.Errorf("%w, the limit is %d bytes", ErrAttachmentTooLarge, s.maxSize)
+	}
+
+	key, err := attachmentKey(expense, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.storage.Put(ctx, key, bytes.NewReader(data), int64(len(data)), contentType); err != nil {
+		return nil, err
+	}
+
+	attachment := &models.Attachment{
+		ExpenseID:   expense.ID,
+		UserID:      expense.UserID,
+		FileName:    cleanFileName(fileName),
+		ContentType: contentType,
+		Size:        int64(len(data)),
+		StorageKey:  key,
+	}
+
+	if err := s.store.Create(attachment); err != nil {
+		// DO NOT LEAVE AN UNREFERENCED OBJECT BEHIND
+		if deleteErr := s.storage.Delete(ctx, key); deleteErr != nil {
+			log.Printf("Failed to delete orphaned attachment %s: %v", key, deleteErr)
+		}
+		return nil, err
+	}
+
+	return attachment, nil
+}
+
+// OPEN THE CONTENT OF AN ATTACHMENT, THE CALLER CLOSES IT
+func (s *AttachmentService) Open(ctx context.Context, attachment *models.Attachment) (io.ReadCloser, error) {
+	return s.storage.Get(ctx, attachment.StorageKey)
+}
+
+// DELETE THE CONTENT OF AN ATTACHMENT, THEN ITS RECORD
+// THE RECORD IS KEPT WHEN THE STORAGE FAILS SO THE DELETE CAN BE RETRIED, A MISSING OBJECT IS NOT AN ERROR
+func (s *AttachmentService) Delete(ctx context.Context, attachment *models.Attachment) error {
+	if err := s.storage.Delete(ctx, attachment.StorageKey); err != nil {
+		return err
+	}
+
+	return s.store.Delete(attachment)
+}
+
+// DELETE ALL ATTACHMENTS OF AN EXPENSE
+func (s *AttachmentService) DeleteByExpense(ctx context.Context, expenseID uint) error {
+	attachments, err := s.store.GetByExpenseID(expenseID)
+	if err != nil {
+		return err
+	}
+
+	for i := range attachments {
+		if err := s.Delete(ctx, &attachments[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// expenses/<user>/<expense>/<random><ext>, THE CLIENT FILE NAME NEVER BECOMES PART OF A PATH
+func attachmentKey(expense *models.Expense, fileName string) (string, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return "", err
+	}
+
+	ext := strings.ToLower(filepath.Ext(cleanFileName(fileName)))
+	if len(ext) > 10 || strings.ContainsAny(ext, `/\ `) {
+		ext = ""
+	}
+
+	return fmt.Sprintf("expenses/%d/%d/%s%s", expense.UserID, expense.ID, hex.EncodeToString(random), ext), nil
+}
+
+// BASE NAME WITHOUT PATH OR CONTROL CHARACTERS, FOR DISPLAY AND Content-Disposition
+func cleanFileName(fileName string) string {
+	fileName = filepath.Base(strings.ReplaceAll(fileName, `\`, "/"))
+	fileName = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f || r == '"' {
+			return -1
+		}
+		return r
+	}, fileName)
+
+	if fileName == "" || fileName == "." || fileName == "/" {
+		return "attachment"
+	}
+	if len(fileName) > 255 {
+		fileName = fileName[len(fileName)-255:]
+	}
+
+	return fileName
+}
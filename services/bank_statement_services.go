@@ -0,0 +1,333 @@
+package services
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"go-expense-tracker-api/models"
+	"html"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ofxTransactionPattern = regexp.MustCompile(`(?is)<STMTTRN>(.*?)</STMTTRN>`)
+	ofxTimezonePattern    = regexp.MustCompile(`\[([+-]?\d+(?:\.\d+)?)(?::[^\]]*)?\]`)
+)
+
+// PARSE AN OFX OR QFX STATEMENT, BOTH THE SGML (1.x) AND XML (2.x) VARIANTS
+// DEBITS BECOME expense CANDIDATES AND CREDITS income CANDIDATES, THE EXTERNAL ID IS THE ACCOUNT AND FITID
+func ParseOFX(r io.Reader, loc *time.Location) ([]ExpenseCandidate, []models.ExpenseImportError, error) {
+	data, err := io.ReadAll(io.LimitReader(r, 32<<20))
+	if err != nil {
+		return nil, nil, err
+	}
+	content := string(data)
+
+	if !strings.Contains(strings.ToUpper(content), "<OFX>") {
+		return nil, nil, errors.New("not an OFX file")
+	}
+
+	var candidates []ExpenseCandidate
+	var rowErrors []models.ExpenseImportError
+
+	matches := ofxTransactionPattern.FindAllStringSubmatchIndex(content, -1)
+	if len(matches) > maxImportRows {
+		return nil, nil, fmt.Errorf("file has more than %d transactions", maxImportRows)
+	}
+
+	for i, match := range matches {
+		transaction := content[match[2]:match[3]]
+		row := i + 1
+
+		// A FILE MAY HOLD SEVERAL STATEMENTS, USE THE ACCOUNT AND CURRENCY OF THE ENCLOSING ONE
+		account := ofxLastValue(content[:match[0]], "ACCTID")
+		currency := ofxLastValue(content[:match[0]], "CURDEF")
+
+		name := ofxValue(transaction, "NAME")
+		if name == "" {
+			name = ofxValue(transaction, "PAYEE")
+		}
+		if name == "" {
+			name = ofxValue(transaction, "MEMO")
+		}
+
+		candidate := ExpenseCandidate{
+			Row:      row,
+			Name:     name,
+			Currency: currency,
+		}
+		valid := true
+
+		candidate.SpentAt, err = parseOFXDate(ofxValue(transaction, "DTPOSTED"), loc)
+		if err != nil {
+			rowErrors = append(rowErrors, models.ExpenseImportError{Row: row, Field: "date", Message: err.Error()})
+			valid = false
+		}
+
+		amount, err := models.ParseMoney(strings.ReplaceAll(ofxValue(transaction, "TRNAMT"), ",", "."))
+		if err != nil {
+			rowErrors = append(rowErrors, models.ExpenseImportError{Row: row, Field: "amount", Message: "invalid amount " + ofxValue(transaction, "TRNAMT")})
+			valid = false
+		}
+		candidate.Amount, candidate.CategoryType = signedAmount(amount)
+
+		fitID := ofxValue(transaction, "FITID")
+		if fitID == "" {
+			rowErrors = append(rowErrors, models.ExpenseImportError{Row: row, Field: "fitid", Message: "transaction has no FITID"})
+			valid = false
+		}
+		candidate.ExternalID = "ofx:" + account + ":" + fitID
+
+		if valid {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, rowErrors, nil
+}
+
+// VALUE OF THE FIRST <TAG> IN content, UP TO THE NEXT TAG, WORKS WITH AND WITHOUT CLOSING TAGS
+func ofxValue(content, tag string) string {
+	upper := strings.ToUpper(content)
+	start := strings.Index(upper, "<"+tag+">")
+	if start < 0 {
+		return ""
+	}
+
+	value := content[start+len(tag)+2:]
+	if end := strings.Index(value, "<"); end >= 0 {
+		value = value[:end]
+	}
+
+	return html.UnescapeString(strings.TrimSpace(value))
+}
+
+// VALUE OF THE LAST <TAG> IN content
+func ofxLastValue(content, tag string) string {
+	start := strings.LastIndex(strings.ToUpper(content), "<"+tag+">")
+	if start < 0 {
+		return ""
+	}
+
+	return ofxValue(content[start:], tag)
+}
+
+// PARSE YYYYMMDD[HHMMSS[.XXX]][[gmt offset[:tz name]]], DATES WITHOUT A TIME ARE IN loc, TIMES WITHOUT AN OFFSET IN GMT
+func parseOFXDate(value string, loc *time.Location) (time.Time, error) {
+	invalid := fmt.Errorf("invalid date %s", value)
+
+	zone := time.UTC
+	if match := ofxTimezonePattern.FindStringSubmatch(value); match != nil {
+		hours, err := strconv.ParseFloat(match[1], 64)
+		if err != nil {
+			return time.Time{}, invalid
+		}
+		zone = time.FixedZone("", int(hours*3600))
+	}
+
+	digits := value
+	if i := strings.IndexAny(digits, ".["); i >= 0 {
+		digits = digits[:i]
+	}
+
+	switch len(digits) {
+	case 8:
+		parsed, err := time.ParseInLocation("20060102", digits, loc)
+		if err != nil {
+			return time.Time{}, invalid
+		}
+		return parsed, nil
+	case 12:
+		digits += "00"
+	case 14:
+	default:
+		return time.Time{}, invalid
+	}
+
+	parsed, err := time.ParseInLocation("20060102150405", digits, zone)
+	if err != nil {
+		return time.Time{}, invalid
+	}
+
+	return parsed, nil
+}
+
+// SPLIT A SIGNED STATEMENT AMOUNT INTO A POSITIVE AMOUNT AND THE CATEGORY TYPE IT MAPS TO
+func signedAmount(amount models.Money) (models.Money, string) {
+	if amount < 0 {
+		return -amount, "expense"
+	}
+
+	return amount, "income"
+}
+
+// PARSE A QIF BANK, CASH OR CREDIT CARD EXPORT
+// dayFirst READS AMBIGUOUS DATES AS DD/MM INSTEAD OF THE US MM/DD MOST BANKS USE
+// QIF HAS NO TRANSACTION ID, SO THE EXTERNAL ID IS A HASH OF THE TRANSACTION AND ITS OCCURRENCE IN THE FILE
+func ParseQIF(r io.Reader, dayFirst bool, loc *time.Location) ([]ExpenseCandidate, []models.ExpenseImportError, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var candidates []ExpenseCandidate
+	var rowErrors []models.ExpenseImportError
+
+	fields := make(map[byte]string)
+	line, start := 0, 0
+	seen := make(map[string]int)
+
+	// RECORDS OF ACCOUNT, CATEGORY AND OTHER NON TRANSACTION SECTIONS ARE SKIPPED
+	skipping := false
+
+	flush := func() {
+		defer func() { fields = make(map[byte]string) }()
+		if len(fields) == 0 || skipping {
+			return
+		}
+
+		name := fields['P']
+		if name == "" {
+			name = fields['M']
+		}
+
+		candidate := ExpenseCandidate{Row: start, Name: name}
+		valid := true
+
+		spentAt, err := parseQIFDate(fields['D'], dayFirst, loc)
+		if err != nil {
+			rowErrors = append(rowErrors, models.ExpenseImportError{Row: start, Field: "date", Message: err.Error()})
+			valid = false
+		}
+		candidate.SpentAt = spentAt
+
+		rawAmount := fields['T']
+		if rawAmount == "" {
+			rawAmount = fields['U']
+		}
+		amount, err := models.ParseMoney(strings.ReplaceAll(rawAmount, ",", ""))
+		if err != nil {
+			rowErrors = append(rowErrors, models.ExpenseImportError{Row: start, Field: "amount", Message: "invalid amount " + rawAmount})
+			valid = false
+		}
+		candidate.Amount, candidate.CategoryType = signedAmount(amount)
+
+		// [Account] IS A TRANSFER, Parent:Child IS MATCHED BY THE CHILD NAME
+		if category := fields['L']; category != "" && !strings.HasPrefix(category, "[") {
+			if i := strings.LastIndex(category, ":"); i >= 0 {
+				category = category[i+1:]
+			}
+			if i := strings.Index(category, "/"); i >= 0 {
+				category = category[:i]
+			}
+			candidate.CategoryName = strings.TrimSpace(category)
+		}
+
+		key := strings.Join([]string{fields['D'], rawAmount, fields['P'], fields['M'], fields['N']}, "\x1f")
+		seen[key]++
+		hash := sha1.Sum([]byte(key + "\x1f" + strconv.Itoa(seen[key])))
+		candidate.ExternalID = "qif:" + hex.EncodeToString(hash[:])
+
+		if valid {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimRight(scanner.Text(), "\r")
+		if strings.TrimSpace(text) == "" {
+			continue
+		}
+
+		switch text[0] {
+		case '!':
+			// HEADERS LIKE !Type:Bank, ONLY BANK, CASH, CREDIT CARD AND ASSET OR LIABILITY TRANSACTIONS ARE IMPORTED
+			flush()
+			header := strings.ToLower(strings.TrimSpace(text))
+			switch {
+			case strings.HasPrefix(header, "!type:"):
+				switch strings.TrimSpace(header[len("!type:"):]) {
+				case "bank", "cash", "ccard", "oth a", "oth l":
+					skipping = false
+				default:
+					skipping = true
+				}
+			case header == "!account":
+				skipping = true
+			}
+		case '^':
+			flush()
+			if len(candidates)+countRows(rowErrors) > maxImportRows {
+				return nil, nil, fmt.Errorf("file has more than %d transactions", maxImportRows)
+			}
+		default:
+			if len(fields) == 0 {
+				start = line
+			}
+			// SPLIT LINES (S, E, $) ARE IGNORED, THE TRANSACTION TOTAL IS IMPORTED
+			if _, exists := fields[text[0]]; !exists {
+				fields[text[0]] = strings.TrimSpace(text[1:])
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, err
+	}
+
+	// THE LAST RECORD MAY MISS ITS ^
+	flush()
+
+	return candidates, rowErrors, nil
+}
+
+// PARSE QIF DATES LIKE 01/31/2025, 1/31'25, 31.01.2025 OR 2025-01-31
+func parseQIFDate(value string, dayFirst bool, loc *time.Location) (time.Time, error) {
+	invalid := fmt.Errorf("invalid date %s", value)
+
+	parts := strings.FieldsFunc(strings.TrimSpace(value), func(r rune) bool {
+		return r == '/' || r == '-' || r == '.' || r == '\'' || r == ' '
+	})
+	if len(parts) != 3 {
+		return time.Time{}, invalid
+	}
+
+	numbers := make([]int, 3)
+	for i, part := range parts {
+		number, err := strconv.Atoi(part)
+		if err != nil {
+			return time.Time{}, invalid
+		}
+		numbers[i] = number
+	}
+
+	var year, month, day int
+	switch {
+	case len(parts[0]) == 4:
+		year, month, day = numbers[0], numbers[1], numbers[2]
+	case dayFirst:
+		day, month, year = numbers[0], numbers[1], numbers[2]
+	default:
+		month, day, year = numbers[0], numbers[1], numbers[2]
+	}
+
+	// TWO DIGIT YEARS, 70-99 ARE 19XX
+	if len(parts[2]) <= 2 && len(parts[0]) != 4 {
+		if year < 70 {
+			year += 2000
+		} else {
+			year += 1900
+		}
+	}
+
+	parsed := time.Date(year, time.Month(month), day, 0, 0, 0, 0, loc)
+	if parsed.Year() != year || int(parsed.Month()) != month || parsed.Day() != day {
+		return time.Time{}, invalid
+	}
+
+	return parsed, nil
+}
@@ -0,0 +1,250 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseOFX(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	// SGML, TWO STATEMENTS OF DIFFERENT ACCOUNTS AND CURRENCIES
+	sgml := strings.Join([]string{
+		"OFXHEADER:100",
+		"DATA:OFXSGML",
+		"<OFX>",
+		"<BANKMSGSRSV1><STMTTRNRS><STMTRS>",
+		"<CURDEF>EUR",
+		"<BANKACCTFROM><ACCTID>111</BANKACCTFROM>",
+		"<BANKTRANLIST>",
+		"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20250115<TRNAMT>-12,50<FITID>A1<NAME>Coffee &amp; Co</STMTTRN>",
+		"<STMTTRN><TRNTYPE>CREDIT<DTPOSTED>20250131120000[-5:EST]<TRNAMT>1000.00<FITID>A2<MEMO>Salary</STMTTRN>",
+		"</BANKTRANLIST></STMTRS>",
+		"<STMTRS>",
+		"<CURDEF>USD",
+		"<BANKACCTFROM><ACCTID>222</BANKACCTFROM>",
+		"<BANKTRANLIST>",
+		"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20250201<TRNAMT>-20<FITID>B1<NAME>Book</STMTTRN>",
+		"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>20250202<TRNAMT>-5<NAME>No FITID</STMTTRN>",
+		"<STMTTRN><TRNTYPE>DEBIT<DTPOSTED>2025020<TRNAMT>abc<FITID>B3<NAME>Broken</STMTTRN>",
+		"</BANKTRANLIST></STMTRS></STMTTRNRS></BANKMSGSRSV1>",
+		"</OFX>",
+	}, "\n")
+
+	candidates, rowErrors, err := ParseOFX(strings.NewReader(sgml), jakarta)
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+
+	want := []ExpenseCandidate{
+		{Row: 1, Name: "Coffee & Co", Amount: money(t, "12.5"), Currency: "EUR", SpentAt: time.Date(2025, 1, 15, 0, 0, 0, 0, jakarta), CategoryType: "expense", ExternalID: "ofx:111:A1"},
+		{Row: 2, Name: "Salary", Amount: money(t, "1000"), Currency: "EUR", SpentAt: time.Date(2025, 1, 31, 17, 0, 0, 0, time.UTC), CategoryType: "income", ExternalID: "ofx:111:A2"},
+		{Row: 3, Name: "Book", Amount: money(t, "20"), Currency: "USD", SpentAt: time.Date(2025, 2, 1, 0, 0, 0, 0, jakarta), CategoryType: "expense", ExternalID: "ofx:222:B1"},
+	}
+	if len(candidates) != len(want) {
+		t.Fatalf("got %d candidates, want %d: %+v", len(candidates), len(want), candidates)
+	}
+	for i := range want {
+		if !sameCandidate(candidates[i], want[i]) {
+			t.Errorf("candidate %d = %+v, want %+v", i, candidates[i], want[i])
+		}
+	}
+
+	wantErrors := []struct {
+		row   int
+		field string
+	}{{4, "fitid"}, {5, "date"}, {5, "amount"}}
+	if len(rowErrors) != len(wantErrors) {
+		t.Fatalf("row errors = %+v, want %v", rowErrors, wantErrors)
+	}
+	for i, wantError := range wantErrors {
+		if rowErrors[i].Row != wantError.row || rowErrors[i].Field != wantError.field {
+			t.Errorf("row error %d = %+v, want %v", i, rowErrors[i], wantError)
+		}
+	}
+}
+
+func TestParseOFXXML(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<?OFX OFXHEADER="200" VERSION="220"?>
+<OFX>
+  <CREDITCARDMSGSRSV1><CCSTMTTRNRS><CCSTMTRS>
+    <CURDEF>IDR</CURDEF>
+    <CCACCTFROM><ACCTID>333</ACCTID></CCACCTFROM>
+    <BANKTRANLIST>
+      <STMTTRN>
+        <TRNTYPE>DEBIT</TRNTYPE>
+        <DTPOSTED>20250301093000.000[+7:WIB]</DTPOSTED>
+        <TRNAMT>-25000</TRNAMT>
+        <FITID>X1</FITID>
+        <NAME>Bus</NAME>
+      </STMTTRN>
+    </BANKTRANLIST>
+  </CCSTMTRS></CCSTMTTRNRS></CREDITCARDMSGSRSV1>
+</OFX>`
+
+	candidates, rowErrors, err := ParseOFX(strings.NewReader(xml), time.UTC)
+	if err != nil {
+		t.Fatalf("ParseOFX() error = %v", err)
+	}
+
+	want := ExpenseCandidate{Row: 1, Name: "Bus", Amount: money(t, "25000"), Currency: "IDR", SpentAt: time.Date(2025, 3, 1, 2, 30, 0, 0, time.UTC), CategoryType: "expense", ExternalID: "ofx:333:X1"}
+	if len(rowErrors) > 0 || len(candidates) != 1 || !sameCandidate(candidates[0], want) {
+		t.Errorf("ParseOFX() = %+v, %+v, want %+v", candidates, rowErrors, want)
+	}
+}
+
+func TestParseOFXNotOFX(t *testing.T) {
+	if _, _, err := ParseOFX(strings.NewReader("date,name,amount\n"), time.UTC); err == nil {
+		t.Error("ParseOFX() error = nil, want an error")
+	}
+}
+
+func TestParseOFXDate(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{value: "20250115", want: time.Date(2025, 1, 15, 0, 0, 0, 0, jakarta)},
+		{value: "202501151230", want: time.Date(2025, 1, 15, 12, 30, 0, 0, time.UTC)},
+		{value: "20250115123045.123[-5:EST]", want: time.Date(2025, 1, 15, 17, 30, 45, 0, time.UTC)},
+		{value: "20250115123045[+5.5]", want: time.Date(2025, 1, 15, 7, 0, 45, 0, time.UTC)},
+		{value: "2025011", wantErr: true},
+		{value: "20251315", wantErr: true},
+		{value: "", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseOFXDate(tt.value, jakarta)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseOFXDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("parseOFXDate(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQIF(t *testing.T) {
+	qif := strings.Join([]string{
+		"!Account", // 1
+		"NChecking",
+		"TBank",
+		"^",
+		"!Type:Bank", // 5
+		"D01/31/2025",
+		"T-1,234.56",
+		"PSupermarket",
+		"LFood:Groceries",
+		"^", // 10
+		"D02/01'25",
+		"T2000",
+		"MPayroll",
+		"LSalary/Job",
+		"^", // 15
+		"D02/02/2025",
+		"T-100",
+		"PTransfer",
+		"L[Savings]",
+		"^", // 20
+		"D01/31/2025",
+		"T-1,234.56",
+		"PSupermarket",
+		"LFood:Groceries",
+		"^", // 25
+		"!Type:Cat",
+		"NGroceries",
+		"E",
+		"^",
+		"!Type:Bank", // 30
+		"D13/02/2025",
+		"T-1",
+		"PBad date",
+		"^",
+		"D2025-03-01", // 35
+		"T-7",
+		"PLast",
+	}, "\r\n")
+
+	candidates, rowErrors, err := ParseQIF(strings.NewReader(qif), false, time.UTC)
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+
+	want := []ExpenseCandidate{
+		{Row: 6, Name: "Supermarket", Amount: money(t, "1234.56"), SpentAt: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), CategoryName: "Groceries", CategoryType: "expense"},
+		{Row: 11, Name: "Payroll", Amount: money(t, "2000"), SpentAt: time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC), CategoryName: "Salary", CategoryType: "income"},
+		{Row: 16, Name: "Transfer", Amount: money(t, "100"), SpentAt: time.Date(2025, 2, 2, 0, 0, 0, 0, time.UTC), CategoryType: "expense"},
+		{Row: 21, Name: "Supermarket", Amount: money(t, "1234.56"), SpentAt: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC), CategoryName: "Groceries", CategoryType: "expense"},
+		{Row: 35, Name: "Last", Amount: money(t, "7"), SpentAt: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), CategoryType: "expense"},
+	}
+	if len(candidates) != len(want) {
+		t.Fatalf("got %d candidates, want %d: %+v", len(candidates), len(want), candidates)
+	}
+	for i := range want {
+		// EXTERNAL IDS ARE HASHES, CHECKED BELOW
+		want[i].ExternalID = candidates[i].ExternalID
+		if !sameCandidate(candidates[i], want[i]) {
+			t.Errorf("candidate %d = %+v, want %+v", i, candidates[i], want[i])
+		}
+		if !strings.HasPrefix(candidates[i].ExternalID, "qif:") {
+			t.Errorf("candidate %d external ID = %q, want a qif: hash", i, candidates[i].ExternalID)
+		}
+	}
+
+	// THE SAME TRANSACTION TWICE IN ONE FILE IS TWO TRANSACTIONS
+	if candidates[0].ExternalID == candidates[3].ExternalID {
+		t.Errorf("repeated transactions share the external ID %q", candidates[0].ExternalID)
+	}
+
+	// THE EXTERNAL ID IS STABLE ACROSS IMPORTS OF THE SAME FILE
+	again, _, err := ParseQIF(strings.NewReader(qif), false, time.UTC)
+	if err != nil {
+		t.Fatalf("ParseQIF() error = %v", err)
+	}
+	for i := range candidates {
+		if again[i].ExternalID != candidates[i].ExternalID {
+			t.Errorf("candidate %d external ID changed from %q to %q", i, candidates[i].ExternalID, again[i].ExternalID)
+		}
+	}
+
+	if len(rowErrors) != 1 || rowErrors[0].Row != 31 || rowErrors[0].Field != "date" {
+		t.Errorf("row errors = %+v, want a date error on row 31", rowErrors)
+	}
+}
+
+func TestParseQIFDate(t *testing.T) {
+	tests := []struct {
+		value    string
+		dayFirst bool
+		want     time.Time
+		wantErr  bool
+	}{
+		{value: "01/31/2025", want: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{value: "31/01/2025", dayFirst: true, want: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{value: "1/2'25", want: time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{value: "31.01.99", dayFirst: true, want: time.Date(1999, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{value: "2025-01-31", dayFirst: true, want: time.Date(2025, 1, 31, 0, 0, 0, 0, time.UTC)},
+		{value: "31/01/2025", wantErr: true},
+		{value: "02/30/2025", wantErr: true},
+		{value: "1/2", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseQIFDate(tt.value, tt.dayFirst, time.UTC)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseQIFDate(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("parseQIFDate(%q) = %s, want %s", tt.value, got, tt.want)
+			}
+		})
+	}
+}
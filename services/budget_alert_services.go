@@ -0,0 +1,98 @@
+package services
+
+import (
+	"go-expense-tracker-api/models"
+	"log"
+	"time"
+)
+
+// IMPLEMENTED BY repositories.BudgetRepository
+type BudgetStore interface {
+	GetByCategoryIDs(userID uint, categoryIDs []uint) ([]models.Budget, error)
+}
+
+// IMPLEMENTED BY repositories.BudgetAlertRepository
+type BudgetAlertStore interface {
+	CreateIfAbsent(alert *models.BudgetAlert) (bool, error)
+}
+
+type BudgetAlertService struct {
+	budgets       BudgetStore
+	alerts        BudgetAlertStore
+	budgetService *BudgetService
+	notifiers     []Notifier
+}
+
+func NewBudgetAlertService(budgets BudgetStore, alerts BudgetAlertStore, budgetService *BudgetService, notifiers ...Notifier) *BudgetAlertService {
+	return &BudgetAlertService{
+		budgets:       budgets,
+		alerts:        alerts,
+		budgetService: budgetService,
+		notifiers:     notifiers,
+	}
+}
+
+// CHECK THE CURRENT PERIOD OF EVERY BUDGET ON THE GIVEN CATEGORIES AND DELIVER ALERTS
+// FOR THRESHOLDS CROSSED FOR THE FIRST TIME IN THAT PERIOD, RETURNS THE NEW ALERTS
+func (s *BudgetAlertService) Evaluate(user *models.User, categoryIDs ...uint) ([]*models.BudgetAlert, error) {
+	budgets, err := s.budgets.GetByCategoryIDs(user.ID, categoryIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	var alerts []*models.BudgetAlert
+
+	for i := range budgets {
+		budget := &budgets[i]
+
+		// ONE BUDGET FAILING, E.G. ON A MISSING EXCHANGE RATE, DOES NOT HOLD BACK THE ALERTS OF THE OTHERS
+		status, err := s.budgetService.Status(user, budget, now)
+		if err != nil {
+			log.Printf("Failed to get the status of budget %d: %v", budget.ID, err)
+			continue
+		}
+
+		for _, threshold := range budget.AlertThresholds {
+			if status.PercentageUsed < float64(threshold) {
+				continue
+			}
+
+			alert := &models.BudgetAlert{
+				UserID:         user.ID,
+				BudgetID:       budget.ID,
+				CategoryID:     budget.CategoryID,
+				CategoryName:   budget.Category.Name,
+				Threshold:      threshold,
+				PeriodStart:    status.PeriodStart,
+				PeriodEnd:      status.PeriodEnd,
+				Currency:       status.Currency,
+				LimitAmount:    status.Limit,
+				Spent:          status.Spent,
+				PercentageUsed: status.PercentageUsed,
+			}
+
+			created, err := s.alerts.CreateIfAbsent(alert)
+			if err != nil {
+				return alerts, err
+			}
+			if !created {
+				continue
+			}
+
+			alerts = append(alerts, alert)
+			s.dispatch(alert)
+		}
+	}
+
+	return alerts, nil
+}
+
+// NOTIFIERS ONLY RECORD THE ALERT, A WEBHOOK IS POSTED IN THE BACKGROUND AND RETRIED FROM ITS RECORD
+func (s *BudgetAlertService) dispatch(alert *models.BudgetAlert) {
+	for _, notifier := range s.notifiers {
+		if err := notifier.Notify(alert); err != nil {
+			log.Printf("Failed to deliver budget alert %d with %T: %v", alert.ID, notifier, err)
+		}
+	}
+}
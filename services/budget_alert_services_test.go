@@ -0,0 +1,78 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+type fakeBudgetStore struct {
+	budgets []models.Budget
+}
+
+func (s *fakeBudgetStore) GetByCategoryIDs(userID uint, categoryIDs []uint) ([]models.Budget, error) {
+	return s.budgets, nil
+}
+
+type fakeBudgetAlertStore struct {
+	alerts []*models.BudgetAlert
+}
+
+func (s *fakeBudgetAlertStore) CreateIfAbsent(alert *models.BudgetAlert) (bool, error) {
+	for _, existing := range s.alerts {
+		if existing.BudgetID == alert.BudgetID && existing.Threshold == alert.Threshold && existing.PeriodStart.Equal(alert.PeriodStart) {
+			return false, nil
+		}
+	}
+	alert.ID = uint(len(s.alerts) + 1)
+	s.alerts = append(s.alerts, alert)
+
+	return true, nil
+}
+
+// THE SAME SPENDING ON EVERY CATEGORY
+type fakeExpenseSumStore struct {
+	spent models.Money
+}
+
+func (s *fakeExpenseSumStore) SumByCategory(userID, categoryID uint, from, to time.Time) (models.Money, error) {
+	return s.spent, nil
+}
+
+type fakeNotifier struct {
+	notified []*models.BudgetAlert
+}
+
+func (n *fakeNotifier) Notify(alert *models.BudgetAlert) error {
+	n.notified = append(n.notified, alert)
+	return nil
+}
+
+func TestBudgetAlertServiceEvaluate(t *testing.T) {
+	user := &models.User{ID: 1, BaseCurrency: "EUR"}
+	notifier := &fakeNotifier{}
+	alerts := &fakeBudgetAlertStore{}
+	budgets := &fakeBudgetStore{budgets: []models.Budget{
+		// NO RATE FROM USD TO EUR, ITS STATUS FAILS
+		{ID: 1, UserID: 1, CategoryID: 1, Amount: money(t, "100"), Currency: "USD", Period: models.BudgetPeriodMonthly, AlertThresholds: models.Thresholds{80}},
+		{ID: 2, UserID: 1, CategoryID: 2, Amount: money(t, "100"), Currency: "EUR", Period: models.BudgetPeriodMonthly, AlertThresholds: models.Thresholds{50, 80, 100}},
+	}}
+	budgetService := NewBudgetService(&fakeExpenseSumStore{spent: money(t, "85")}, NewExchangeRateService(&fakeExchangeRateStore{}))
+	service := NewBudgetAlertService(budgets, alerts, budgetService, notifier)
+
+	created, err := service.Evaluate(user, 1, 2)
+	if err != nil {
+		t.Fatalf("Evaluate() error = %v", err)
+	}
+
+	if len(created) != 2 || created[0].BudgetID != 2 || created[0].Threshold != 50 || created[1].Threshold != 80 || len(notifier.notified) != 2 {
+		t.Fatalf("Evaluate() = %+v, want the 50 and 80 alerts of budget 2", created)
+	}
+
+	// THRESHOLDS ALREADY CROSSED IN THE PERIOD ARE NOT RAISED AGAIN
+	created, err = service.Evaluate(user, 2)
+	if err != nil || len(created) != 0 || len(notifier.notified) != 2 {
+		t.Errorf("Evaluate() again = %+v, %v, want no new alerts", created, err)
+	}
+}
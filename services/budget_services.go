@@ -0,0 +1,84 @@
+package services
+
+import (
+	"go-expense-tracker-api/models"
+	"math"
+	"time"
+)
+
+// IMPLEMENTED BY repositories.ExpenseRepository
+type ExpenseSumStore interface {
+	SumByCategory(userID, categoryID uint, from, to time.Time) (models.Money, error)
+}
+
+type BudgetService struct {
+	expenses            ExpenseSumStore
+	exchangeRateService *ExchangeRateService
+}
+
+func NewBudgetService(expenses ExpenseSumStore, exchangeRateService *ExchangeRateService) *BudgetService {
+	return &BudgetService{
+		expenses:            expenses,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// COMPUTE SPENT, REMAINING AND PERCENTAGE USED OF A BUDGET FOR THE PERIOD CONTAINING now
+func (s *BudgetService) Status(user *models.User, budget *models.Budget, now time.Time) (*models.BudgetStatus, error) {
+	start, end := BudgetPeriod(budget, now.In(user.Location()))
+
+	spent, err := s.expenses.SumByCategory(user.ID, budget.CategoryID, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	// BUDGETS CREATED BEFORE A BASE CURRENCY CHANGE ARE CONVERTED AT TODAY'S RATE
+	limit, err := s.exchangeRateService.Convert(user.ID, budget.Amount, budget.Currency, user.BaseCurrency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	percentageUsed := 0.0
+	if limit > 0 {
+		percentageUsed = math.Round(spent.Float64()/limit.Float64()*10000) / 100
+	}
+
+	return &models.BudgetStatus{
+		Budget:         *budget,
+		PeriodStart:    start,
+		PeriodEnd:      end,
+		Currency:       user.BaseCurrency,
+		Limit:          limit,
+		Spent:          spent,
+		Remaining:      limit - spent,
+		PercentageUsed: percentageUsed,
+	}, nil
+}
+
+// GET THE [start, end) RANGE OF THE BUDGET PERIOD CONTAINING now, IN THE LOCATION OF now
+// WEEKS START ON MONDAY, CUSTOM PERIODS ALWAYS USE THEIR OWN DATES AND COVER THEM AS WHOLE DAYS, END DATE INCLUDED
+func BudgetPeriod(budget *models.Budget, now time.Time) (time.Time, time.Time) {
+	year, month, _ := now.Date()
+	today := startOfDay(now)
+
+	switch budget.Period {
+	case models.BudgetPeriodWeekly:
+		offset := (int(today.Weekday()) + 6) % 7
+		start := today.AddDate(0, 0, -offset)
+		return start, start.AddDate(0, 0, 7)
+	case models.BudgetPeriodCustom:
+		if budget.StartDate != nil && budget.EndDate != nil {
+			return startOfDay(budget.StartDate.In(now.Location())), startOfDay(budget.EndDate.In(now.Location())).AddDate(0, 0, 1)
+		}
+		fallthrough
+	default:
+		start := time.Date(year, month, 1, 0, 0, 0, 0, now.Location())
+		return start, start.AddDate(0, 1, 0)
+	}
+}
+
+// MIDNIGHT AT THE START OF THE DAY OF t, IN THE LOCATION OF t
+func startOfDay(t time.Time) time.Time {
+	year, month, day := t.Date()
+	return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+}
@@ -0,0 +1,71 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+func TestBudgetPeriod(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+	at := func(year int, month time.Month, day, hour int) time.Time {
+		return time.Date(year, month, day, hour, 0, 0, 0, jakarta)
+	}
+	timePtr := func(t time.Time) *time.Time { return &t }
+
+	tests := []struct {
+		name      string
+		budget    models.Budget
+		now       time.Time
+		wantStart time.Time
+		wantEnd   time.Time
+	}{
+		{
+			name:      "weekly starts on monday",
+			budget:    models.Budget{Period: models.BudgetPeriodWeekly},
+			now:       at(2025, 3, 16, 23), // A SUNDAY
+			wantStart: at(2025, 3, 10, 0),
+			wantEnd:   at(2025, 3, 17, 0),
+		},
+		{
+			name:      "monthly",
+			budget:    models.Budget{Period: models.BudgetPeriodMonthly},
+			now:       at(2025, 2, 28, 12),
+			wantStart: at(2025, 2, 1, 0),
+			wantEnd:   at(2025, 3, 1, 0),
+		},
+		{
+			// DATES ARE READ BACK FROM THE DATABASE IN UTC
+			name:      "custom plain dates include the end date",
+			budget:    models.Budget{Period: models.BudgetPeriodCustom, StartDate: timePtr(at(2025, 3, 1, 0).UTC()), EndDate: timePtr(at(2025, 3, 31, 0).UTC())},
+			now:       at(2025, 3, 15, 12),
+			wantStart: at(2025, 3, 1, 0),
+			wantEnd:   at(2025, 4, 1, 0),
+		},
+		{
+			name:      "custom times cover whole days",
+			budget:    models.Budget{Period: models.BudgetPeriodCustom, StartDate: timePtr(at(2025, 3, 1, 9)), EndDate: timePtr(at(2025, 3, 31, 18).UTC())},
+			now:       at(2025, 3, 15, 12),
+			wantStart: at(2025, 3, 1, 0),
+			wantEnd:   at(2025, 4, 1, 0),
+		},
+		{
+			name:      "custom without dates falls back to the month",
+			budget:    models.Budget{Period: models.BudgetPeriodCustom},
+			now:       at(2025, 3, 15, 12),
+			wantStart: at(2025, 3, 1, 0),
+			wantEnd:   at(2025, 4, 1, 0),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start, end := BudgetPeriod(&tt.budget, tt.now)
+
+			if !start.Equal(tt.wantStart) || !end.Equal(tt.wantEnd) {
+				t.Errorf("BudgetPeriod() = [%s, %s), want [%s, %s)", start, end, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"go-expense-tracker-api/models"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// MOST EXPENSES ONE Apply OR Preview CHANGES, THE REST ARE LEFT FOR THE NEXT CALL
+const categorizationBatchSize = 1000
+
+var errCategorizationBatchFull = errors.New("categorization batch full")
+
+// IMPLEMENTED BY repositories.CategorizationRuleRepository
+type CategorizationRuleStore interface {
+	GetActiveByUserID(userID uint) ([]models.CategorizationRule, error)
+}
+
+// IMPLEMENTED BY repositories.ExpenseRepository
+type CategorizationExpenseStore interface {
+	EachCategorizable(userID uint, from, to *time.Time, categoryID uint, fn func(expense *models.Expense) error) error
+	ApplyCategorization(changes []models.CategorizationChange) error
+}
+
+type CategorizationService struct {
+	rules    CategorizationRuleStore
+	expenses CategorizationExpenseStore
+}
+
+func NewCategorizationService(rules CategorizationRuleStore, expenses CategorizationExpenseStore) *CategorizationService {
+	return &CategorizationService{
+		rules:    rules,
+		expenses: expenses,
+	}
+}
+
+// COMPILED CATEGORIZATION RULES IN PRIORITY ORDER
+type RuleMatcher struct {
+	rules []categorizationRule
+}
+
+type categorizationRule struct {
+	rule    *models.CategorizationRule
+	pattern string
+	regex   *regexp.Regexp
+}
+
+// LOAD THE ACTIVE RULES OF A USER, BUILD ONE MATCHER PER REQUEST OR IMPORT
+func (s *CategorizationService) Matcher(userID uint) (*RuleMatcher, error) {
+	rules, err := s.rules.GetActiveByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return NewRuleMatcher(rules), nil
+}
+
+// COMPILE rules, WHICH MUST ALREADY BE IN PRIORITY ORDER
+func NewRuleMatcher(rules []models.CategorizationRule) *RuleMatcher {
+	matcher := &RuleMatcher{}
+	for i := range rules {
+		// A RULE WHOSE CATEGORY WAS DELETED HAS NOTHING TO SET
+		if rules[i].Category.ID == 0 {
+			continue
+		}
+
+		rule := categorizationRule{rule: &rules[i], pattern: normalizePayeeName(rules[i].Pattern)}
+		if rules[i].MatchType == models.RuleMatchRegex {
+			// PATTERNS ARE VALIDATED ON SAVE, SKIP ANY THAT NO LONGER COMPILE
+			var err error
+			if rule.regex, err = CompileRulePattern(rules[i].Pattern); err != nil {
+				continue
+			}
+		}
+		matcher.rules = append(matcher.rules, rule)
+	}
+
+	return matcher
+}
+
+// THE FIRST RULE MATCHING AN EXPENSE, NIL WHEN NONE DOES
+// categoryType KEEPS RULES SETTING A CATEGORY OF THAT TYPE ONLY, EMPTY FOR ANY
+func (m *RuleMatcher) Match(name string, amount models.Money, payeeID *uint, categoryType string) *models.CategorizationRule {
+	normalized := normalizePayeeName(name)
+
+	for _, candidate := range m.rules {
+		rule := candidate.rule
+
+		if categoryType != "" && rule.Category.Type != categoryType {
+			continue
+		}
+
+		switch rule.MatchType {
+		case models.RuleMatchContains:
+			if !strings.Contains(normalized, candidate.pattern) {
+				continue
+			}
+		case models.RuleMatchRegex:
+			if !candidate.regex.MatchString(name) {
+				continue
+			}
+		}
+
+		if rule.MinAmount != nil && amount < *rule.MinAmount {
+			continue
+		}
+		if rule.MaxAmount != nil && amount > *rule.MaxAmount {
+			continue
+		}
+		if rule.PayeeID != nil && (payeeID == nil || *payeeID != *rule.PayeeID) {
+			continue
+		}
+
+		return rule
+	}
+
+	return nil
+}
+
+// WHAT A SINGLE, POSSIBLY UNSAVED, RULE WOULD DO TO THE EXISTING PERSONAL EXPENSES OF THE USER, CATEGORIES INCLUDED
+func (s *CategorizationService) Preview(userID uint, rule *models.CategorizationRule) (*models.CategorizationResult, error) {
+	return s.evaluate(userID, NewRuleMatcher([]models.CategorizationRule{*rule}), nil, nil, 0, true, true)
+}
+
+// RUN THE ACTIVE RULES OF THE USER OVER THEIR PERSONAL EXPENSES SPENT IN [from, to) AND, UNLESS dryRun, SAVE THE CHANGES
+// IN ONE TRANSACTION. NIL BOUNDS ARE OPEN, A categoryID OTHER THAN 0 KEEPS THE EXPENSES CURRENTLY IN THAT CATEGORY ONLY
+// THE MATCHING RULE ONLY ADDS ITS TAGS UNLESS overwrite, WHICH ALSO REPLACES THE CATEGORY, EVEN ONE CHOSEN BY HAND
+// AT MOST categorizationBatchSize EXPENSES CHANGE PER CALL, HasMore IS SET WHEN MORE WOULD. SPLIT EXPENSES ARE LEFT ALONE
+func (s *CategorizationService) Apply(userID uint, from, to *time.Time, categoryID uint, overwrite, dryRun bool) (*models.CategorizationResult, error) {
+	matcher, err := s.Matcher(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := s.evaluate(userID, matcher, from, to, categoryID, overwrite, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	if dryRun || len(result.Changes) == 0 {
+		return result, nil
+	}
+
+	if err := s.expenses.ApplyCategorization(result.Changes); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+func (s *CategorizationService) evaluate(userID uint, matcher *RuleMatcher, from, to *time.Time, categoryID uint, overwrite, dryRun bool) (*models.CategorizationResult, error) {
+	result := &models.CategorizationResult{
+		DryRun:  dryRun,
+		Changes: []models.CategorizationChange{},
+	}
+
+	err := s.expenses.EachCategorizable(userID, from, to, categoryID, func(expense *models.Expense) error {
+		// KEEP THE TYPE OF THE CURRENT CATEGORY, MOVING INCOME TO AN EXPENSE CATEGORY WOULD FLIP ITS SIGN IN REPORTS
+		rule := matcher.Match(expense.Name, expense.Amount, expense.PayeeID, expense.Category.Type)
+		if rule == nil {
+			result.Checked++
+			return nil
+		}
+
+		change := models.CategorizationChange{
+			ExpenseID:      expense.ID,
+			Name:           expense.Name,
+			Amount:         expense.Amount,
+			Currency:       expense.Currency,
+			SpentAt:        expense.SpentAt,
+			RuleID:         rule.ID,
+			FromCategoryID: expense.CategoryID,
+			CategoryID:     expense.CategoryID,
+		}
+		if overwrite {
+			change.CategoryID = rule.CategoryID
+		}
+
+		// ONLY THE RULE TAGS THE EXPENSE DOES NOT HAVE YET
+		current := make(map[uint]bool, len(expense.Tags))
+		for _, tag := range expense.Tags {
+			current[tag.ID] = true
+		}
+		for _, tag := range rule.Tags {
+			if !current[tag.ID] {
+				change.AddedTags = append(change.AddedTags, tag.Name)
+				change.TagIDs = append(change.TagIDs, tag.ID)
+			}
+		}
+
+		unchanged := change.CategoryID == change.FromCategoryID && len(change.TagIDs) == 0
+
+		// THE BATCH IS FULL, THIS EXPENSE IS LEFT FOR THE NEXT CALL
+		if !unchanged && len(result.Changes) == categorizationBatchSize {
+			result.HasMore = true
+			return errCategorizationBatchFull
+		}
+
+		result.Checked++
+		result.Matched++
+		if unchanged {
+			return nil
+		}
+
+		result.Changed++
+		result.Changes = append(result.Changes, change)
+
+		return nil
+	})
+	if err != nil && !errors.Is(err, errCategorizationBatchFull) {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// COMPILE A REGEX RULE PATTERN, MATCHING IS CASE INSENSITIVE
+func CompileRulePattern(pattern string) (*regexp.Regexp, error) {
+	regex, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+
+	return regex, nil
+}
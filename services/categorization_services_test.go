@@ -0,0 +1,303 @@
+package services
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+func moneyPtr(t *testing.T, value string) *models.Money {
+	m := money(t, value)
+	return &m
+}
+
+func uintPtr(value uint) *uint {
+	return &value
+}
+
+func category(id uint, categoryType string) models.Category {
+	return models.Category{ID: id, Name: "Category", Type: categoryType}
+}
+
+func TestRuleMatcherMatch(t *testing.T) {
+	// IN PRIORITY ORDER, AS GetActiveByUserID RETURNS THEM
+	rules := []models.CategorizationRule{
+		{ID: 1, Name: "uber rides", MatchType: models.RuleMatchRegex, Pattern: `^uber\s+(trip|ride)`, CategoryID: 10, Category: category(10, "expense")},
+		{ID: 2, Name: "big fuel", MatchType: models.RuleMatchContains, Pattern: "Shell", MinAmount: moneyPtr(t, "50"), CategoryID: 11, Category: category(11, "expense")},
+		{ID: 3, Name: "any fuel", MatchType: models.RuleMatchContains, Pattern: "shell", CategoryID: 12, Category: category(12, "expense")},
+		{ID: 4, Name: "coffee range", MinAmount: moneyPtr(t, "2"), MaxAmount: moneyPtr(t, "5"), CategoryID: 13, Category: category(13, "expense")},
+		{ID: 5, Name: "employer", PayeeID: uintPtr(7), CategoryID: 14, Category: category(14, "income")},
+		{ID: 6, Name: "employer name", MatchType: models.RuleMatchContains, Pattern: "acme", PayeeID: uintPtr(8), CategoryID: 15, Category: category(15, "income")},
+		{ID: 7, Name: "broken regex", MatchType: models.RuleMatchRegex, Pattern: "(", CategoryID: 16, Category: category(16, "expense")},
+		{ID: 8, Name: "deleted category", MatchType: models.RuleMatchContains, Pattern: "everything", CategoryID: 17},
+		{ID: 9, Name: "refunds", MatchType: models.RuleMatchContains, Pattern: "refund", CategoryID: 18, Category: category(18, "income")},
+		{ID: 10, Name: "refund fallback", MatchType: models.RuleMatchContains, Pattern: "refund", CategoryID: 19, Category: category(19, "expense")},
+	}
+	matcher := NewRuleMatcher(rules)
+
+	tests := []struct {
+		name         string
+		expense      string
+		amount       string
+		payeeID      *uint
+		categoryType string
+		want         uint // RULE ID, 0 FOR NO MATCH
+	}{
+		{name: "regex is case insensitive", expense: "UBER Trip 1234", amount: "12", want: 1},
+		{name: "regex must match", expense: "my uber trip", amount: "12", want: 0},
+		{name: "contains is case insensitive", expense: "SHELL station 42", amount: "60", want: 2},
+		{name: "contains ignores repeated whitespace", expense: "Gas  at   SHELL", amount: "60", want: 2},
+		{name: "first match wins by priority", expense: "shell", amount: "50", want: 2},
+		{name: "later rule when an earlier condition fails", expense: "shell", amount: "49.99", want: 3},
+		{name: "minimum amount is inclusive", expense: "latte", amount: "2", want: 4},
+		{name: "maximum amount is inclusive", expense: "latte", amount: "5", want: 4},
+		{name: "below the minimum", expense: "latte", amount: "1.99", want: 0},
+		{name: "above the maximum", expense: "latte", amount: "5.01", want: 0},
+		{name: "payee condition matches", expense: "transfer", amount: "1000", payeeID: uintPtr(7), want: 5},
+		{name: "payee condition needs a payee", expense: "transfer", amount: "1000", want: 0},
+		{name: "payee condition needs the same payee", expense: "transfer", amount: "1000", payeeID: uintPtr(9), want: 0},
+		{name: "every condition must hold", expense: "ACME salary", amount: "1000", payeeID: uintPtr(7), want: 5},
+		{name: "name and payee", expense: "ACME salary", amount: "1000", payeeID: uintPtr(8), want: 6},
+		{name: "invalid regex and deleted category are skipped", expense: "everything (", amount: "100", want: 0},
+		{name: "category type limits the rules", expense: "refund", amount: "10", categoryType: "expense", want: 10},
+		{name: "any category type", expense: "refund", amount: "10", want: 9},
+		{name: "category type excludes every rule", expense: "UBER ride", amount: "12", categoryType: "income", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rule := matcher.Match(tt.expense, money(t, tt.amount), tt.payeeID, tt.categoryType)
+
+			var got uint
+			if rule != nil {
+				got = rule.ID
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q, %s) = rule %d, want rule %d", tt.expense, tt.amount, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRuleMatcherEmpty(t *testing.T) {
+	if rule := NewRuleMatcher(nil).Match("anything", 1, nil, ""); rule != nil {
+		t.Errorf("empty matcher matched rule %d", rule.ID)
+	}
+}
+
+type fakeRuleStore struct {
+	rules []models.CategorizationRule
+}
+
+func (s *fakeRuleStore) GetActiveByUserID(userID uint) ([]models.CategorizationRule, error) {
+	return s.rules, nil
+}
+
+type fakeCategorizationExpenseStore struct {
+	expenses []models.Expense
+	applied  []models.CategorizationChange
+	calls    int
+}
+
+func (s *fakeCategorizationExpenseStore) EachCategorizable(userID uint, from, to *time.Time, categoryID uint, fn func(expense *models.Expense) error) error {
+	for i := range s.expenses {
+		if categoryID != 0 && s.expenses[i].CategoryID != categoryID {
+			continue
+		}
+		if err := fn(&s.expenses[i]); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *fakeCategorizationExpenseStore) ApplyCategorization(changes []models.CategorizationChange) error {
+	s.calls++
+	s.applied = changes
+	return nil
+}
+
+func TestCategorizationServiceApply(t *testing.T) {
+	other := category(1, "expense")
+	salary := category(2, "income")
+	fuel := category(3, "expense")
+	car := models.Tag{ID: 20, Name: "car"}
+	work := models.Tag{ID: 21, Name: "work"}
+
+	rules := &fakeRuleStore{rules: []models.CategorizationRule{
+		// MATCHES ON AMOUNT ALONE, MUST NOT MOVE INCOME INTO AN EXPENSE CATEGORY
+		{ID: 1, MinAmount: moneyPtr(t, "1000"), CategoryID: fuel.ID, Category: fuel},
+		{ID: 2, MatchType: models.RuleMatchContains, Pattern: "shell", CategoryID: fuel.ID, Category: fuel, Tags: []models.Tag{car, work}},
+	}}
+
+	newStore := func() *fakeCategorizationExpenseStore {
+		return &fakeCategorizationExpenseStore{expenses: []models.Expense{
+			{ID: 100, Name: "Shell 42", Amount: money(t, "40"), CategoryID: other.ID, Category: other, Tags: []models.Tag{work}},
+			{ID: 101, Name: "Salary", Amount: money(t, "3000"), CategoryID: salary.ID, Category: salary},
+			{ID: 102, Name: "Shell 43", Amount: money(t, "45"), CategoryID: fuel.ID, Category: fuel, Tags: []models.Tag{car, work}},
+			{ID: 103, Name: "Groceries", Amount: money(t, "30"), CategoryID: other.ID, Category: other},
+			{ID: 104, Name: "New laptop", Amount: money(t, "1500"), CategoryID: other.ID, Category: other},
+		}}
+	}
+
+	t.Run("dry run lists the changes without saving", func(t *testing.T) {
+		expenses := newStore()
+		service := NewCategorizationService(rules, expenses)
+
+		result, err := service.Apply(1, nil, nil, 0, true, true)
+		if err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+
+		if !result.DryRun || result.Checked != 5 || result.Matched != 3 || result.Changed != 2 {
+			t.Errorf("result = dry run %v, checked %d, matched %d, changed %d, want true, 5, 3, 2",
+				result.DryRun, result.Checked, result.Matched, result.Changed)
+		}
+		if expenses.calls != 0 {
+			t.Errorf("dry run saved %d times", expenses.calls)
+		}
+
+		want := []models.CategorizationChange{
+			{ExpenseID: 100, Name: "Shell 42", Amount: money(t, "40"), RuleID: 2, FromCategoryID: other.ID, CategoryID: fuel.ID, AddedTags: []string{"car"}, TagIDs: []uint{car.ID}},
+			{ExpenseID: 104, Name: "New laptop", Amount: money(t, "1500"), RuleID: 1, FromCategoryID: other.ID, CategoryID: fuel.ID},
+		}
+		if !reflect.DeepEqual(result.Changes, want) {
+			t.Errorf("changes = %+v, want %+v", result.Changes, want)
+		}
+	})
+
+	t.Run("apply saves the changes", func(t *testing.T) {
+		expenses := newStore()
+		service := NewCategorizationService(rules, expenses)
+
+		result, err := service.Apply(1, nil, nil, 0, true, false)
+		if err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+
+		if expenses.calls != 1 || !reflect.DeepEqual(expenses.applied, result.Changes) {
+			t.Errorf("saved %d times with %+v, want once with %+v", expenses.calls, expenses.applied, result.Changes)
+		}
+	})
+
+	t.Run("without overwrite only tags are added", func(t *testing.T) {
+		expenses := newStore()
+		service := NewCategorizationService(rules, expenses)
+
+		result, err := service.Apply(1, nil, nil, 0, false, false)
+		if err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+
+		want := []models.CategorizationChange{
+			{ExpenseID: 100, Name: "Shell 42", Amount: money(t, "40"), RuleID: 2, FromCategoryID: other.ID, CategoryID: other.ID, AddedTags: []string{"car"}, TagIDs: []uint{car.ID}},
+		}
+		if result.Matched != 3 || result.Changed != 1 || !reflect.DeepEqual(expenses.applied, want) {
+			t.Errorf("matched %d, changed %d, saved %+v, want 3, 1, %+v", result.Matched, result.Changed, expenses.applied, want)
+		}
+	})
+
+	t.Run("category limits the expenses", func(t *testing.T) {
+		expenses := newStore()
+		service := NewCategorizationService(rules, expenses)
+
+		result, err := service.Apply(1, nil, nil, salary.ID, true, false)
+		if err != nil {
+			t.Fatalf("Apply: %v", err)
+		}
+
+		if result.Checked != 1 || result.Matched != 0 || expenses.calls != 0 {
+			t.Errorf("checked %d, matched %d, saved %d times, want 1, 0, 0", result.Checked, result.Matched, expenses.calls)
+		}
+	})
+}
+
+func TestCategorizationServiceApplyBatch(t *testing.T) {
+	other := category(1, "expense")
+	fuel := category(3, "expense")
+
+	expenses := &fakeCategorizationExpenseStore{}
+	for i := 0; i < categorizationBatchSize+10; i++ {
+		expenses.expenses = append(expenses.expenses, models.Expense{ID: uint(i + 1), Name: "Shell", Amount: money(t, "40"), CategoryID: other.ID, Category: other})
+	}
+	rules := &fakeRuleStore{rules: []models.CategorizationRule{{ID: 1, MatchType: models.RuleMatchContains, Pattern: "shell", CategoryID: fuel.ID, Category: fuel}}}
+	service := NewCategorizationService(rules, expenses)
+
+	result, err := service.Apply(1, nil, nil, 0, true, false)
+	if err != nil {
+		t.Fatalf("Apply: %v", err)
+	}
+
+	if !result.HasMore || result.Checked != categorizationBatchSize || result.Changed != categorizationBatchSize || len(expenses.applied) != categorizationBatchSize {
+		t.Errorf("has more %v, checked %d, changed %d, saved %d, want true and %d", result.HasMore, result.Checked, result.Changed, len(expenses.applied), categorizationBatchSize)
+	}
+}
+
+func TestCategorizationServicePreview(t *testing.T) {
+	other := category(1, "expense")
+	fuel := category(3, "expense")
+
+	expenses := &fakeCategorizationExpenseStore{expenses: []models.Expense{
+		{ID: 100, Name: "Shell 42", Amount: money(t, "40"), CategoryID: other.ID, Category: other, Tags: []models.Tag{{ID: 21, Name: "work"}}},
+	}}
+	service := NewCategorizationService(&fakeRuleStore{}, expenses)
+
+	// AN UNSAVED RULE WITH A TAG THAT DOES NOT EXIST YET
+	rule := &models.CategorizationRule{
+		MatchType:  models.RuleMatchContains,
+		Pattern:    "shell",
+		CategoryID: fuel.ID,
+		Category:   fuel,
+		Tags:       []models.Tag{{Name: "car"}, {ID: 21, Name: "work"}},
+	}
+
+	result, err := service.Preview(1, rule)
+	if err != nil {
+		t.Fatalf("Preview: %v", err)
+	}
+
+	if !result.DryRun || result.Changed != 1 || expenses.calls != 0 {
+		t.Fatalf("dry run %v, changed %d, saved %d times, want true, 1, 0", result.DryRun, result.Changed, expenses.calls)
+	}
+	if got := result.Changes[0].AddedTags; !reflect.DeepEqual(got, []string{"car"}) {
+		t.Errorf("added tags = %v, want [car]", got)
+	}
+}
+
+func TestCompileRulePattern(t *testing.T) {
+	if _, err := CompileRulePattern("(unclosed"); err == nil {
+		t.Error("invalid pattern compiled")
+	}
+
+	regex, err := CompileRulePattern(`^netflix\.com`)
+	if err != nil {
+		t.Fatalf("CompileRulePattern: %v", err)
+	}
+	if !regex.MatchString("NETFLIX.COM Amsterdam") {
+		t.Error("pattern is not case insensitive")
+	}
+}
+
+var errStop = errors.New("stop")
+
+type failingExpenseStore struct{}
+
+func (failingExpenseStore) EachCategorizable(userID uint, from, to *time.Time, categoryID uint, fn func(expense *models.Expense) error) error {
+	return errStop
+}
+
+func (failingExpenseStore) ApplyCategorization(changes []models.CategorizationChange) error {
+	return nil
+}
+
+func TestCategorizationServiceApplyError(t *testing.T) {
+	service := NewCategorizationService(&fakeRuleStore{}, failingExpenseStore{})
+
+	if _, err := service.Apply(1, nil, nil, 0, true, false); !errors.Is(err, errStop) {
+		t.Errorf("Apply error = %v, want %v", err, errStop)
+	}
+}
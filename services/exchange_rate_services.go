@@ -0,0 +1,238 @@
+package services
+
+import (
+	"encoding/csv"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/utils"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrExchangeRateNotFound = errors.New("exchange rate not found")
+
+// IMPLEMENTED BY repositories.ExchangeRateRepository
+type ExchangeRateStore interface {
+	Upsert(rates []*models.ExchangeRate) error
+	GetLatest(userID uint, base, currency string, at time.Time) (*models.ExchangeRate, error)
+	GetBasesFor(userID uint, currency string) ([]string, error)
+}
+
+type ExchangeRateService struct {
+	store ExchangeRateStore
+}
+
+func NewExchangeRateService(store ExchangeRateStore) *ExchangeRateService {
+	return &ExchangeRateService{
+		store: store,
+	}
+}
+
+// CONVERT AN AMOUNT BETWEEN CURRENCIES USING THE LATEST RATE ON OR BEFORE at
+// THE RESULT IS ROUNDED TO THE MINOR UNITS OF THE TARGET CURRENCY
+func (s *ExchangeRateService) Convert(userID uint, amount models.Money, from, to string, at time.Time) (models.Money, error) {
+	if from == to {
+		return amount, nil
+	}
+
+	factor, err := s.rateBetween(userID, from, to, at)
+	if err != nil {
+		return 0, err
+	}
+
+	converted, err := models.MoneyFromRat(new(big.Rat).Mul(amount.Rat(), factor))
+	if err != nil {
+		return 0, err
+	}
+
+	return converted.Round(models.CurrencyExponent(to)), nil
+}
+
+// SET BaseAmount AND BaseCurrency OF AN EXPENSE AND ITS SPLITS FROM ITS Amount, Currency AND SpentAt
+func (s *ExchangeRateService) ConvertExpense(userID uint, baseCurrency string, expense *models.Expense) error {
+	baseAmount, err := s.Convert(userID, expense.Amount, expense.Currency, baseCurrency, expense.SpentAt)
+	if err != nil {
+		return err
+	}
+
+	expense.Rebase(baseCurrency, baseAmount)
+
+	return nil
+}
+
+// FIND 1 from = ? to, DIRECTLY, INVERSELY OR THROUGH A SHARED BASE CURRENCY
+func (s *ExchangeRateService) rateBetween(userID uint, from, to string, at time.Time) (*big.Rat, error) {
+	direct, err := s.store.GetLatest(userID, from, to, at)
+	if err == nil {
+		return direct.Rate.Rat(), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	inverse, err := s.store.GetLatest(userID, to, from, at)
+	if err == nil {
+		return new(big.Rat).Inv(inverse.Rate.Rat()), nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	bases, err := s.store.GetBasesFor(userID, from)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, base := range bases {
+		fromRate, err := s.store.GetLatest(userID, base, from, at)
+		if err != nil {
+			continue
+		}
+		toRate, err := s.store.GetLatest(userID, base, to, at)
+		if err != nil {
+			continue
+		}
+		return new(big.Rat).Quo(toRate.Rate.Rat(), fromRate.Rate.Rat()), nil
+	}
+
+	return nil, fmt.Errorf("%w: no rate from %s to %s on or before %s", ErrExchangeRateNotFound, from, to, at.Format(utils.DateLayout))
+}
+
+// LOAD SHARED RATES FROM A CSV OR ECB XML FILE
+func (s *ExchangeRateService) LoadFile(path string) (int, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	return s.Import(0, filepath.Base(path), file)
+}
+
+// IMPORT RATES FOR A USER (0 FOR SHARED RATES), THE FORMAT IS DETECTED FROM THE FILE NAME
+func (s *ExchangeRateService) Import(userID uint, filename string, r io.Reader) (int, error) {
+	var rates []*models.ExchangeRate
+	var err error
+
+	if strings.EqualFold(filepath.Ext(filename), ".xml") {
+		rates, err = ParseECBXML(r)
+	} else {
+		rates, err = ParseExchangeRatesCSV(r)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	for _, rate := range rates {
+		rate.UserID = userID
+	}
+
+	if err := s.store.Upsert(rates); err != nil {
+		return 0, err
+	}
+
+	return len(rates), nil
+}
+
+// PARSE CSV WITH COLUMNS date,base,currency,rate, THE HEADER ROW IS OPTIONAL
+func ParseExchangeRatesCSV(r io.Reader) ([]*models.ExchangeRate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, err
+	}
+
+	columns := map[string]int{"date": 0, "base": 1, "currency": 2, "rate": 3}
+	if len(records) > 0 {
+		if _, _, err := utils.ParseDateTime(records[0][0], time.UTC); err != nil {
+			for i, name := range records[0] {
+				columns[strings.ToLower(strings.TrimSpace(name))] = i
+			}
+			records = records[1:]
+		}
+	}
+
+	rates := make([]*models.ExchangeRate, 0, len(records))
+	for i, record := range records {
+		field := func(name string) string {
+			if columns[name] < len(record) {
+				return record[columns[name]]
+			}
+			return ""
+		}
+
+		rate, err := newExchangeRate(field("date"), field("base"), field("currency"), field("rate"))
+		if err != nil {
+			return nil, fmt.Errorf("row %d: %w", i+1, err)
+		}
+		rates = append(rates, rate)
+	}
+
+	return rates, nil
+}
+
+type ecbEnvelope struct {
+	Days []struct {
+		Time  string `xml:"time,attr"`
+		Rates []struct {
+			Currency string `xml:"currency,attr"`
+			Rate     string `xml:"rate,attr"`
+		} `xml:"Cube"`
+	} `xml:"Cube>Cube"`
+}
+
+// PARSE THE ECB eurofxref DAILY OR HISTORICAL XML, ALL RATES ARE BASED ON EUR
+func ParseECBXML(r io.Reader) ([]*models.ExchangeRate, error) {
+	var envelope ecbEnvelope
+	if err := xml.NewDecoder(r).Decode(&envelope); err != nil {
+		return nil, err
+	}
+
+	var rates []*models.ExchangeRate
+	for _, day := range envelope.Days {
+		for _, item := range day.Rates {
+			rate, err := newExchangeRate(day.Time, "EUR", item.Currency, item.Rate)
+			if err != nil {
+				return nil, fmt.Errorf("%s %s: %w", day.Time, item.Currency, err)
+			}
+			rates = append(rates, rate)
+		}
+	}
+
+	return rates, nil
+}
+
+func newExchangeRate(date, base, currency, rate string) (*models.ExchangeRate, error) {
+	parsedDate, _, err := utils.ParseDateTime(date, time.UTC)
+	if err != nil {
+		return nil, err
+	}
+
+	base = models.NormalizeCurrency(base)
+	currency = models.NormalizeCurrency(currency)
+	if base == "" || currency == "" {
+		return nil, errors.New("invalid currency code")
+	}
+
+	parsedRate, err := models.ParseRate(rate)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.ExchangeRate{
+		Date:     parsedDate,
+		Base:     base,
+		Currency: currency,
+		Rate:     parsedRate,
+	}, nil
+}
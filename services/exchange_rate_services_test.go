@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+
+	"gorm.io/gorm"
+)
+
+type fakeExchangeRateStore struct {
+	rates []*models.ExchangeRate
+}
+
+func (s *fakeExchangeRateStore) Upsert(rates []*models.ExchangeRate) error {
+	s.rates = append(s.rates, rates...)
+	return nil
+}
+
+// THE LATEST RATE ON OR BEFORE at, A RATE OF THE USER WINS OVER A SHARED ONE OF THE SAME DAY
+func (s *fakeExchangeRateStore) GetLatest(userID uint, base, currency string, at time.Time) (*models.ExchangeRate, error) {
+	var latest *models.ExchangeRate
+	for _, rate := range s.rates {
+		if (rate.UserID != userID && rate.UserID != 0) || rate.Base != base || rate.Currency != currency || rate.Date.After(at) {
+			continue
+		}
+		if latest == nil || rate.Date.After(latest.Date) || (rate.Date.Equal(latest.Date) && rate.UserID > latest.UserID) {
+			latest = rate
+		}
+	}
+	if latest == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return latest, nil
+}
+
+func (s *fakeExchangeRateStore) GetBasesFor(userID uint, currency string) ([]string, error) {
+	var bases []string
+	seen := make(map[string]bool)
+	for _, rate := range s.rates {
+		if (rate.UserID == userID || rate.UserID == 0) && rate.Currency == currency && !seen[rate.Base] {
+			seen[rate.Base] = true
+			bases = append(bases, rate.Base)
+		}
+	}
+
+	return bases, nil
+}
+
+func rate(t *testing.T, userID uint, date time.Time, base, currency, value string) *models.ExchangeRate {
+	t.Helper()
+
+	parsed, err := models.ParseRate(value)
+	if err != nil {
+		t.Fatalf("ParseRate(%q): %v", value, err)
+	}
+
+	return &models.ExchangeRate{UserID: userID, Date: date, Base: base, Currency: currency, Rate: parsed}
+}
+
+func TestExchangeRateServiceConvert(t *testing.T) {
+	jan := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	feb := time.Date(2025, 2, 1, 0, 0, 0, 0, time.UTC)
+
+	service := NewExchangeRateService(&fakeExchangeRateStore{rates: []*models.ExchangeRate{
+		rate(t, 0, jan, "EUR", "USD", "1.25"),
+		rate(t, 0, feb, "EUR", "USD", "1.1"),
+		rate(t, 0, jan, "EUR", "JPY", "160.3"),
+		rate(t, 0, jan, "EUR", "GBP", "0.8"),
+		rate(t, 7, jan, "EUR", "GBP", "0.9"), // THE USER'S OWN RATE
+	}})
+
+	tests := []struct {
+		name     string
+		amount   string
+		from, to string
+		at       time.Time
+		want     string
+		wantErr  bool
+	}{
+		{name: "same currency", amount: "12.3456", from: "EUR", to: "EUR", at: jan, want: "12.3456"},
+		{name: "direct", amount: "100", from: "EUR", to: "USD", at: jan, want: "125"},
+		{name: "latest rate on or before the date", amount: "100", from: "EUR", to: "USD", at: feb.AddDate(0, 0, 10), want: "110"},
+		{name: "inverse", amount: "100", from: "USD", to: "EUR", at: jan, want: "80"},
+		{name: "cross through a shared base", amount: "10", from: "USD", to: "JPY", at: jan, want: "1282"},
+		{name: "rounded to the minor units of the target", amount: "1.5", from: "EUR", to: "JPY", at: jan, want: "240"},
+		{name: "user rate wins over the shared rate", amount: "100", from: "EUR", to: "GBP", at: jan, want: "90"},
+		{name: "no rate before the date", amount: "100", from: "EUR", to: "USD", at: jan.AddDate(0, 0, -1), wantErr: true},
+		{name: "unknown currency", amount: "100", from: "EUR", to: "CHF", at: jan, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.Convert(7, money(t, tt.amount), tt.from, tt.to, tt.at)
+			if tt.wantErr {
+				if !errors.Is(err, ErrExchangeRateNotFound) {
+					t.Errorf("Convert() error = %v, want ErrExchangeRateNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			if got != money(t, tt.want) {
+				t.Errorf("Convert() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseExchangeRatesCSV(t *testing.T) {
+	for name, file := range map[string]string{
+		"with header":    "currency,date,base,rate\nusd,2025-01-02,eur,1.0345\nJPY,2025-01-02,EUR,162.5\n",
+		"without header": "2025-01-02,eur,usd,1.0345\n2025-01-02,EUR,JPY,162.5\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			rates, err := ParseExchangeRatesCSV(strings.NewReader(file))
+			if err != nil {
+				t.Fatalf("ParseExchangeRatesCSV() error = %v", err)
+			}
+
+			if len(rates) != 2 || rates[0].Base != "EUR" || rates[0].Currency != "USD" || rates[0].Rate.String() != "1.0345" ||
+				!rates[0].Date.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) || rates[1].Currency != "JPY" || rates[1].Rate.String() != "162.5" {
+				t.Errorf("ParseExchangeRatesCSV() = %+v %+v", rates[0], rates[1])
+			}
+		})
+	}
+
+	for _, file := range []string{"2025-01-02,EUR,USD,abc\n", "2025-01-02,EUR,US,1.1\n", "yesterday,EUR,USD,1.1\n2025-13-01,EUR,USD,1.1\n"} {
+		if _, err := ParseExchangeRatesCSV(strings.NewReader(file)); err == nil {
+			t.Errorf("ParseExchangeRatesCSV(%q) error = nil, want an error", file)
+		}
+	}
+}
+
+func TestParseECBXML(t *testing.T) {
+	xml := `<?xml version="1.0" encoding="UTF-8"?>
+<gesmes:Envelope xmlns:gesmes="http://www.gesmes.org/xml/2002-08-01" xmlns="http://www.ecb.int/vocabulary/2002-08-01/eurofxref">
+	<Cube>
+		<Cube time="2025-01-03">
+			<Cube currency="USD" rate="1.0299"/>
+			<Cube currency="JPY" rate="162.66"/>
+		</Cube>
+		<Cube time="2025-01-02">
+			<Cube currency="USD" rate="1.0321"/>
+		</Cube>
+	</Cube>
+</gesmes:Envelope>`
+
+	rates, err := ParseECBXML(strings.NewReader(xml))
+	if err != nil {
+		t.Fatalf("ParseECBXML() error = %v", err)
+	}
+
+	if len(rates) != 3 || rates[0].Base != "EUR" || rates[0].Currency != "USD" || rates[0].Rate.String() != "1.0299" ||
+		!rates[2].Date.Equal(time.Date(2025, 1, 2, 0, 0, 0, 0, time.UTC)) || rates[2].Rate.String() != "1.0321" {
+		t.Errorf("ParseECBXML() = %+v", rates)
+	}
+}
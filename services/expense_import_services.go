@@ -0,0 +1,470 @@
+package services
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"go-expense-tracker-api/models"
+	"go-expense-tracker-api/utils"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// MAXIMUM NUMBER OF ROWS ACCEPTED IN ONE IMPORT
+const maxImportRows = 10000
+
+// DATE FORMATS ACCEPTED BY THE CSV IMPORT BESIDES YYYY-MM-DD AND RFC3339
+var importDateLayouts = map[string]string{
+	"DD/MM/YYYY": "02/01/2006",
+	"MM/DD/YYYY": "01/02/2006",
+	"DD-MM-YYYY": "02-01-2006",
+	"DD.MM.YYYY": "02.01.2006",
+	"YYYY/MM/DD": "2006/01/02",
+}
+
+var ErrInvalidImportOption = errors.New("invalid import option")
+
+// IMPLEMENTED BY repositories.ExpenseRepository
+type ExpenseImportStore interface {
+	CreateMany(expenses []*models.Expense) error
+	GetExistingExternalIDs(userID uint, externalIDs []string) (map[string]bool, error)
+}
+
+// IMPLEMENTED BY repositories.CategoryRepository
+type CategoryStore interface {
+	GetAvailableByUserID(userID uint) ([]models.Category, error)
+}
+
+// ONE PARSED ROW OF AN IMPORT FILE, BEFORE IT IS VALIDATED AGAINST THE USER DATA
+type ExpenseCandidate struct {
+	Row          int
+	Name         string
+	Amount       models.Money
+	Currency     string // EMPTY MEANS THE USER BASE CURRENCY
+	SpentAt      time.Time
+	CategoryName string
+	CategoryType string // income OR expense, LIMITS RULES TO CATEGORIES OF THAT TYPE AND PICKS THE FALLBACK CATEGORY WHEN CategoryName IS EMPTY, UNKNOWN OR OF THE OTHER TYPE
+	ExternalID   string // OPTIONAL, CANDIDATES WITH AN ALREADY IMPORTED EXTERNAL ID ARE SKIPPED
+}
+
+type ExpenseImportOptions struct {
+	DryRun          bool
+	ExpenseCategory string // OPTIONAL FALLBACK CATEGORY NAME FOR DEBITS
+	IncomeCategory  string // OPTIONAL FALLBACK CATEGORY NAME FOR CREDITS
+}
+
+// COLUMNS ARE HEADER NAMES (CASE INSENSITIVE) OR 1-BASED COLUMN NUMBERS
+type ExpenseCSVOptions struct {
+	Delimiter      rune
+	HasHeader      bool
+	DateColumn     string
+	NameColumn     string
+	AmountColumn   string
+	CategoryColumn string
+	CurrencyColumn string // OPTIONAL
+	DateFormat     string // OPTIONAL, ONE OF THE KEYS OF importDateLayouts
+}
+
+type ExpenseImportService struct {
+	expenses              ExpenseImportStore
+	categories            CategoryStore
+	exchangeRateService   *ExchangeRateService
+	payeeService          *PayeeService
+	categorizationService *CategorizationService
+}
+
+func NewExpenseImportService(expenses ExpenseImportStore, categories CategoryStore, exchangeRateService *ExchangeRateService, payeeService *PayeeService, categorizationService *CategorizationService) *ExpenseImportService {
+	return &ExpenseImportService{
+		expenses:              expenses,
+		categories:            categories,
+		exchangeRateService:   exchangeRateService,
+		payeeService:          payeeService,
+		categorizationService: categorizationService,
+	}
+}
+
+// VALIDATE CANDIDATES AND, UNLESS DryRun OR ANY ROW HAS ERRORS, INSERT THEM ALL IN ONE TRANSACTION
+// parseErrors ARE ROWS THE PARSER ALREADY REJECTED, THEY COUNT AS INVALID ROWS
+func (s *ExpenseImportService) Import(user *models.User, candidates []ExpenseCandidate, parseErrors []models.ExpenseImportError, opts ExpenseImportOptions) (*models.ExpenseImportResult, error) {
+	categories, err := s.categoriesByName(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// RESOLVE FALLBACK CATEGORIES BY TYPE
+	fallbacks := make(map[string]*models.Category)
+	for categoryType, name := range map[string]string{"expense": opts.ExpenseCategory, "income": opts.IncomeCategory} {
+		if name == "" {
+			continue
+		}
+		category, ok := categories[normalizeCategoryName(name)]
+		if !ok {
+			return nil, fmt.Errorf("%w: unknown %s category %s", ErrInvalidImportOption, categoryType, name)
+		}
+		if category.Type != categoryType {
+			return nil, fmt.Errorf("%w: %s is not an %s category", ErrInvalidImportOption, name, categoryType)
+		}
+		fallbacks[categoryType] = category
+	}
+
+	existing, err := s.existingExternalIDs(user.ID, candidates)
+	if err != nil {
+		return nil, err
+	}
+
+	payees, err := s.payeeService.Matcher(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	rules, err := s.categorizationService.Matcher(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &models.ExpenseImportResult{
+		DryRun: opts.DryRun,
+		Total:  len(candidates) + countRows(parseErrors),
+		Errors: append([]models.ExpenseImportError{}, parseErrors...),
+	}
+
+	for _, candidate := range candidates {
+		// SKIP TRANSACTIONS ALREADY IMPORTED OR REPEATED IN THE SAME FILE
+		if candidate.ExternalID != "" {
+			if existing[candidate.ExternalID] {
+				result.Skipped++
+				continue
+			}
+			existing[candidate.ExternalID] = true
+		}
+
+		expense, rowErrors := s.prepare(user, candidate, categories, fallbacks, payees, rules)
+		if len(rowErrors) > 0 {
+			result.Errors = append(result.Errors, rowErrors...)
+			continue
+		}
+
+		result.Expenses = append(result.Expenses, expense)
+	}
+	result.Valid = len(result.Expenses)
+
+	if opts.DryRun || len(result.Errors) > 0 || len(result.Expenses) == 0 {
+		return result, nil
+	}
+
+	if err := s.expenses.CreateMany(result.Expenses); err != nil {
+		return nil, err
+	}
+	result.Imported = len(result.Expenses)
+
+	return result, nil
+}
+
+func (s *ExpenseImportService) existingExternalIDs(userID uint, candidates []ExpenseCandidate) (map[string]bool, error) {
+	var externalIDs []string
+	for _, candidate := range candidates {
+		if candidate.ExternalID != "" {
+			externalIDs = append(externalIDs, candidate.ExternalID)
+		}
+	}
+
+	if len(externalIDs) == 0 {
+		return make(map[string]bool), nil
+	}
+
+	return s.expenses.GetExistingExternalIDs(userID, externalIDs)
+}
+
+// BUILD THE EXPENSE OF ONE CANDIDATE, OR THE REASONS IT CANNOT BE IMPORTED
+// A CANDIDATE WITHOUT A CATEGORY NAME TAKES THE CATEGORY AND TAGS OF THE FIRST MATCHING RULE, THEN THE FALLBACK
+func (s *ExpenseImportService) prepare(user *models.User, candidate ExpenseCandidate, categories, fallbacks map[string]*models.Category, payees *PayeeMatcher, rules *RuleMatcher) (*models.Expense, []models.ExpenseImportError) {
+	var rowErrors []models.ExpenseImportError
+	fail := func(field, message string) {
+		rowErrors = append(rowErrors, models.ExpenseImportError{Row: candidate.Row, Field: field, Message: message})
+	}
+
+	name := strings.TrimSpace(candidate.Name)
+	if name == "" {
+		fail("name", "name is required")
+	}
+
+	currency := user.BaseCurrency
+	if candidate.Currency != "" {
+		currency = models.NormalizeCurrency(candidate.Currency)
+		if currency == "" {
+			fail("currency", "invalid currency "+candidate.Currency)
+		}
+	}
+
+	if candidate.Amount <= 0 {
+		fail("amount", "amount must be greater than 0")
+	} else if currency != "" && !candidate.Amount.FitsExponent(models.CurrencyExponent(currency)) {
+		fail("amount", "amount has more decimal places than "+currency+" allows")
+	}
+
+	// NORMALIZE THE RAW NAME INTO A PAYEE
+	var payeeID *uint
+	payee := payees.Match(name)
+	if payee != nil {
+		payeeID = &payee.ID
+	}
+
+	var rule *models.CategorizationRule
+	category, ok := categories[normalizeCategoryName(candidate.CategoryName)]
+
+	// THE SIGN OF A STATEMENT AMOUNT WINS OVER A NAMED CATEGORY OF THE OTHER TYPE
+	mismatched := ok && candidate.CategoryType != "" && category.Type != candidate.CategoryType
+	if mismatched {
+		ok = false
+	}
+	if candidate.CategoryName == "" {
+		ok = false
+		if rule = rules.Match(name, candidate.Amount, payeeID, candidate.CategoryType); rule != nil {
+			category, ok = &rule.Category, true
+		}
+	}
+	if !ok {
+		category, ok = fallbacks[candidate.CategoryType]
+	}
+	if !ok {
+		switch {
+		case mismatched:
+			fail("category", candidate.CategoryName+" is not an "+candidate.CategoryType+" category, set "+candidate.CategoryType+"_category")
+		case candidate.CategoryName != "":
+			fail("category", "unknown category "+candidate.CategoryName)
+		case candidate.CategoryType != "":
+			fail("category", "no "+candidate.CategoryType+" category, set "+candidate.CategoryType+"_category")
+		default:
+			fail("category", "category is required")
+		}
+	}
+
+	if len(rowErrors) > 0 {
+		return nil, rowErrors
+	}
+
+	expense := &models.Expense{
+		Name:       name,
+		Amount:     candidate.Amount,
+		Currency:   currency,
+		SpentAt:    candidate.SpentAt,
+		UserID:     user.ID,
+		CategoryID: category.ID,
+		Category:   *category,
+		PayeeID:    payeeID,
+		Payee:      payee,
+	}
+	if rule != nil {
+		expense.Tags = append([]models.Tag(nil), rule.Tags...)
+	}
+	if candidate.ExternalID != "" {
+		externalID := candidate.ExternalID
+		expense.ExternalID = &externalID
+	}
+
+	if err := s.exchangeRateService.ConvertExpense(user.ID, user.BaseCurrency, expense); err != nil {
+		fail("currency", err.Error())
+		return nil, rowErrors
+	}
+
+	return expense, nil
+}
+
+// USER CATEGORIES BY LOWERCASE NAME, THEY TAKE PRECEDENCE OVER DEFAULT CATEGORIES WITH THE SAME NAME
+func (s *ExpenseImportService) categoriesByName(userID uint) (map[string]*models.Category, error) {
+	categories, err := s.categories.GetAvailableByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*models.Category, len(categories))
+	for i := range categories {
+		category := &categories[i]
+		key := normalizeCategoryName(category.Name)
+		if existing, ok := byName[key]; ok && existing.UserID != nil {
+			continue
+		}
+		byName[key] = category
+	}
+
+	return byName, nil
+}
+
+func normalizeCategoryName(name string) string {
+	return strings.ToLower(strings.TrimSpace(name))
+}
+
+// NUMBER OF DISTINCT ROWS WITH ERRORS
+func countRows(rowErrors []models.ExpenseImportError) int {
+	rows := make(map[int]bool)
+	for _, rowError := range rowErrors {
+		rows[rowError.Row] = true
+	}
+
+	return len(rows)
+}
+
+// CHECK THAT A DATE FORMAT IS SUPPORTED, AN EMPTY FORMAT MEANS YYYY-MM-DD OR RFC3339
+func ValidateImportDateFormat(format string) error {
+	if _, ok := importDateLayouts[format]; format != "" && !ok {
+		formats := make([]string, 0, len(importDateLayouts))
+		for key := range importDateLayouts {
+			formats = append(formats, key)
+		}
+		sort.Strings(formats)
+		return fmt.Errorf("date_format must be one of %s", strings.Join(formats, ", "))
+	}
+
+	return nil
+}
+
+// PARSE A CSV FILE INTO EXPENSE CANDIDATES USING THE COLUMN MAPPING OF opts
+// ROWS THAT CANNOT BE PARSED ARE RETURNED AS ROW ERRORS, A BROKEN FILE OR MAPPING IS RETURNED AS AN ERROR
+func ParseExpensesCSV(r io.Reader, opts ExpenseCSVOptions, loc *time.Location) ([]ExpenseCandidate, []models.ExpenseImportError, error) {
+	if err := ValidateImportDateFormat(opts.DateFormat); err != nil {
+		return nil, nil, err
+	}
+
+	reader := csv.NewReader(r)
+	reader.Comma = opts.Delimiter
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var header []string
+	if opts.HasHeader {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil, nil, errors.New("file is empty")
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		header = record
+	}
+
+	// RESOLVE THE MAPPING TO COLUMN INDEXES, -1 FOR UNMAPPED OPTIONAL COLUMNS
+	columns := make(map[string]int)
+	for field, column := range map[string]string{
+		"date":     opts.DateColumn,
+		"name":     opts.NameColumn,
+		"amount":   opts.AmountColumn,
+		"category": opts.CategoryColumn,
+		"currency": opts.CurrencyColumn,
+	} {
+		index, err := columnIndex(column, header)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%s column: %w", field, err)
+		}
+		if index < 0 && field != "currency" {
+			return nil, nil, fmt.Errorf("%s column is required", field)
+		}
+		columns[field] = index
+	}
+
+	var candidates []ExpenseCandidate
+	var rowErrors []models.ExpenseImportError
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		line, _ := reader.FieldPos(0)
+		if err != nil {
+			var parseErr *csv.ParseError
+			if errors.As(err, &parseErr) {
+				rowErrors = append(rowErrors, models.ExpenseImportError{Row: parseErr.StartLine, Message: parseErr.Err.Error()})
+				continue
+			}
+			return nil, nil, err
+		}
+
+		// SKIP BLANK LINES
+		if len(record) == 1 && strings.TrimSpace(record[0]) == "" {
+			continue
+		}
+
+		if len(candidates)+countRows(rowErrors) >= maxImportRows {
+			return nil, nil, fmt.Errorf("file has more than %d rows", maxImportRows)
+		}
+
+		value := func(field string) string {
+			index := columns[field]
+			if index < 0 || index >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[index])
+		}
+
+		candidate := ExpenseCandidate{
+			Row:          line,
+			Name:         value("name"),
+			Currency:     value("currency"),
+			CategoryName: value("category"),
+		}
+		valid := true
+
+		candidate.SpentAt, err = parseImportDate(value("date"), opts.DateFormat, loc)
+		if err != nil {
+			rowErrors = append(rowErrors, models.ExpenseImportError{Row: line, Field: "date", Message: err.Error()})
+			valid = false
+		}
+
+		candidate.Amount, err = models.ParseMoney(strings.ReplaceAll(value("amount"), " ", ""))
+		if err != nil {
+			rowErrors = append(rowErrors, models.ExpenseImportError{Row: line, Field: "amount", Message: "invalid amount " + value("amount")})
+			valid = false
+		}
+
+		if valid {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	return candidates, rowErrors, nil
+}
+
+// RESOLVE A HEADER NAME OR 1-BASED COLUMN NUMBER, -1 WHEN column IS EMPTY
+func columnIndex(column string, header []string) (int, error) {
+	column = strings.TrimSpace(column)
+	if column == "" {
+		return -1, nil
+	}
+
+	if number, err := strconv.Atoi(column); err == nil {
+		if number < 1 {
+			return 0, errors.New("column numbers start at 1")
+		}
+		return number - 1, nil
+	}
+
+	for i, name := range header {
+		if strings.EqualFold(strings.TrimSpace(name), column) {
+			return i, nil
+		}
+	}
+
+	return 0, fmt.Errorf("no column named %q", column)
+}
+
+func parseImportDate(value, format string, loc *time.Location) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, errors.New("date is required")
+	}
+
+	if format == "" {
+		parsed, _, err := utils.ParseDateTime(value, loc)
+		return parsed, err
+	}
+
+	parsed, err := time.ParseInLocation(importDateLayouts[format], value, loc)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date %s, expected %s", value, format)
+	}
+
+	return parsed, nil
+}
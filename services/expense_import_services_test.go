@@ -0,0 +1,199 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+type fakeExpenseImportStore struct {
+	existing map[string]bool
+	created  []*models.Expense
+}
+
+func (s *fakeExpenseImportStore) CreateMany(expenses []*models.Expense) error {
+	s.created = append(s.created, expenses...)
+	return nil
+}
+
+func (s *fakeExpenseImportStore) GetExistingExternalIDs(userID uint, externalIDs []string) (map[string]bool, error) {
+	existing := make(map[string]bool)
+	for _, externalID := range externalIDs {
+		if s.existing[externalID] {
+			existing[externalID] = true
+		}
+	}
+
+	return existing, nil
+}
+
+type fakeCategoryStore struct {
+	categories []models.Category
+}
+
+func (s *fakeCategoryStore) GetAvailableByUserID(userID uint) ([]models.Category, error) {
+	return s.categories, nil
+}
+
+func newTestImportService(store *fakeExpenseImportStore, categories ...models.Category) *ExpenseImportService {
+	return NewExpenseImportService(store, &fakeCategoryStore{categories: categories}, NewExchangeRateService(nil), NewPayeeService(&fakePayeeStore{}), NewCategorizationService(&fakeRuleStore{}, &fakeCategorizationExpenseStore{}))
+}
+
+func TestImportCategoryType(t *testing.T) {
+	salary := models.Category{ID: 1, Name: "Salary", Type: "income"}
+	groceries := models.Category{ID: 2, Name: "Groceries", Type: "expense"}
+	other := models.Category{ID: 3, Name: "Other", Type: "expense"}
+	misc := models.Category{ID: 4, Name: "Misc", Type: "income"}
+	fallbacks := ExpenseImportOptions{DryRun: true, ExpenseCategory: "Other", IncomeCategory: "misc"}
+
+	tests := []struct {
+		name      string
+		candidate ExpenseCandidate
+		opts      ExpenseImportOptions
+		want      uint
+		wantError string
+	}{
+		{name: "debit in an expense category", candidate: ExpenseCandidate{CategoryName: "groceries", CategoryType: "expense"}, opts: fallbacks, want: groceries.ID},
+		{name: "credit in an income category", candidate: ExpenseCandidate{CategoryName: "Salary", CategoryType: "income"}, opts: fallbacks, want: salary.ID},
+		{name: "debit in an income category", candidate: ExpenseCandidate{CategoryName: "Salary", CategoryType: "expense"}, opts: fallbacks, want: other.ID},
+		{name: "credit in an expense category", candidate: ExpenseCandidate{CategoryName: "Groceries", CategoryType: "income"}, opts: fallbacks, want: misc.ID},
+		{name: "unknown category", candidate: ExpenseCandidate{CategoryName: "Rent", CategoryType: "expense"}, opts: fallbacks, want: other.ID},
+		{name: "csv row without a type", candidate: ExpenseCandidate{CategoryName: "Salary"}, opts: fallbacks, want: salary.ID},
+		{name: "debit in an income category without fallback", candidate: ExpenseCandidate{CategoryName: "Salary", CategoryType: "expense"}, opts: ExpenseImportOptions{DryRun: true}, wantError: "Salary is not an expense category, set expense_category"},
+		{name: "no category without fallback", candidate: ExpenseCandidate{CategoryType: "income"}, opts: ExpenseImportOptions{DryRun: true}, wantError: "no income category, set income_category"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			service := newTestImportService(&fakeExpenseImportStore{}, salary, groceries, other, misc)
+
+			tt.candidate.Row = 1
+			tt.candidate.Name = "Transaction"
+			tt.candidate.Amount = money(t, "10")
+			tt.candidate.SpentAt = time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)
+
+			result, err := service.Import(&models.User{ID: 1, BaseCurrency: "EUR"}, []ExpenseCandidate{tt.candidate}, nil, tt.opts)
+			if err != nil {
+				t.Fatalf("Import() error = %v", err)
+			}
+
+			if tt.wantError != "" {
+				if len(result.Errors) != 1 || result.Errors[0].Message != tt.wantError {
+					t.Errorf("Import() errors = %+v, want %q", result.Errors, tt.wantError)
+				}
+				return
+			}
+			if len(result.Errors) > 0 || len(result.Expenses) != 1 {
+				t.Fatalf("Import() = %+v, want one expense", result)
+			}
+			if got := result.Expenses[0].CategoryID; got != tt.want {
+				t.Errorf("category = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImportSkipsImportedTransactions(t *testing.T) {
+	store := &fakeExpenseImportStore{existing: map[string]bool{"ofx:1:A": true}}
+	service := newTestImportService(store, models.Category{ID: 1, Name: "Other", Type: "expense"})
+
+	candidate := func(row int, externalID string) ExpenseCandidate {
+		return ExpenseCandidate{Row: row, Name: "Coffee", Amount: money(t, "3.5"), SpentAt: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), CategoryType: "expense", ExternalID: externalID}
+	}
+
+	result, err := service.Import(&models.User{ID: 1, BaseCurrency: "EUR"}, []ExpenseCandidate{candidate(1, "ofx:1:A"), candidate(2, "ofx:1:B"), candidate(3, "ofx:1:B")}, nil, ExpenseImportOptions{ExpenseCategory: "Other"})
+	if err != nil {
+		t.Fatalf("Import() error = %v", err)
+	}
+
+	if result.Total != 3 || result.Skipped != 2 || result.Imported != 1 || len(store.created) != 1 || *store.created[0].ExternalID != "ofx:1:B" {
+		t.Errorf("Import() = %+v, want ofx:1:B imported and 2 skipped", result)
+	}
+}
+
+func TestImportInvalidFallback(t *testing.T) {
+	service := newTestImportService(&fakeExpenseImportStore{}, models.Category{ID: 1, Name: "Salary", Type: "income"})
+
+	for _, opts := range []ExpenseImportOptions{{ExpenseCategory: "Salary"}, {IncomeCategory: "Rent"}} {
+		if _, err := service.Import(&models.User{ID: 1, BaseCurrency: "EUR"}, nil, nil, opts); err == nil {
+			t.Errorf("Import(%+v) error = nil, want ErrInvalidImportOption", opts)
+		}
+	}
+}
+
+func TestParseExpensesCSV(t *testing.T) {
+	file := strings.Join([]string{
+		"Date;Description;Amount;Category;Currency",
+		"15/01/2025;Coffee;3.50;Food;",
+		"32/01/2025;Bad date;1;Food;EUR",
+		"16/01/2025;Bad amount;abc;Food;EUR",
+		"",
+		"17/01/2025;Lunch;1 000.00;Food;usd",
+	}, "\n")
+
+	opts := ExpenseCSVOptions{
+		Delimiter:      ';',
+		HasHeader:      true,
+		DateColumn:     "date",
+		NameColumn:     "2",
+		AmountColumn:   "AMOUNT",
+		CategoryColumn: "category",
+		CurrencyColumn: "currency",
+		DateFormat:     "DD/MM/YYYY",
+	}
+
+	candidates, rowErrors, err := ParseExpensesCSV(strings.NewReader(file), opts, time.UTC)
+	if err != nil {
+		t.Fatalf("ParseExpensesCSV() error = %v", err)
+	}
+
+	want := []ExpenseCandidate{
+		{Row: 2, Name: "Coffee", Amount: money(t, "3.5"), SpentAt: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), CategoryName: "Food"},
+		{Row: 6, Name: "Lunch", Amount: money(t, "1000"), Currency: "usd", SpentAt: time.Date(2025, 1, 17, 0, 0, 0, 0, time.UTC), CategoryName: "Food"},
+	}
+	if len(candidates) != len(want) {
+		t.Fatalf("got %d candidates, want %d: %+v", len(candidates), len(want), candidates)
+	}
+	for i := range want {
+		if !sameCandidate(candidates[i], want[i]) {
+			t.Errorf("candidate %d = %+v, want %+v", i, candidates[i], want[i])
+		}
+	}
+
+	if len(rowErrors) != 2 || rowErrors[0].Row != 3 || rowErrors[0].Field != "date" || rowErrors[1].Row != 4 || rowErrors[1].Field != "amount" {
+		t.Errorf("row errors = %+v, want a date error on row 3 and an amount error on row 4", rowErrors)
+	}
+}
+
+func TestParseExpensesCSVMapping(t *testing.T) {
+	tests := []struct {
+		name string
+		opts ExpenseCSVOptions
+	}{
+		{name: "unknown column", opts: ExpenseCSVOptions{Delimiter: ',', HasHeader: true, DateColumn: "when", NameColumn: "name", AmountColumn: "amount", CategoryColumn: "category"}},
+		{name: "column number 0", opts: ExpenseCSVOptions{Delimiter: ',', HasHeader: true, DateColumn: "0", NameColumn: "name", AmountColumn: "amount", CategoryColumn: "category"}},
+		{name: "missing required column", opts: ExpenseCSVOptions{Delimiter: ',', HasHeader: true, DateColumn: "date", NameColumn: "name", CategoryColumn: "category"}},
+		{name: "unsupported date format", opts: ExpenseCSVOptions{Delimiter: ',', HasHeader: true, DateColumn: "date", NameColumn: "name", AmountColumn: "amount", CategoryColumn: "category", DateFormat: "YYYYMMDD"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := ParseExpensesCSV(strings.NewReader("date,name,amount,category\n"), tt.opts, time.UTC); err == nil {
+				t.Error("ParseExpensesCSV() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func sameCandidate(got, want ExpenseCandidate) bool {
+	return got.Row == want.Row &&
+		got.Name == want.Name &&
+		got.Amount == want.Amount &&
+		got.Currency == want.Currency &&
+		got.SpentAt.Equal(want.SpentAt) &&
+		got.CategoryName == want.CategoryName &&
+		got.CategoryType == want.CategoryType &&
+		got.ExternalID == want.ExternalID
+}
@@ -0,0 +1,98 @@
+package services
+
+import (
+	"go-expense-tracker-api/models"
+	"math"
+	"time"
+)
+
+// IMPLEMENTED BY repositories.ExpenseRepository
+type GoalContributionStore interface {
+	SumByGoal(userID, goalID uint) (models.Money, int64, error)
+}
+
+type GoalService struct {
+	contributions       GoalContributionStore
+	exchangeRateService *ExchangeRateService
+}
+
+func NewGoalService(contributions GoalContributionStore, exchangeRateService *ExchangeRateService) *GoalService {
+	return &GoalService{
+		contributions:       contributions,
+		exchangeRateService: exchangeRateService,
+	}
+}
+
+// COMPUTE SAVED, REMAINING AND THE MONTHLY CONTRIBUTION STILL NEEDED TO REACH A GOAL BY ITS TARGET DATE
+func (s *GoalService) Progress(user *models.User, goal *models.Goal, now time.Time) (*models.GoalProgress, error) {
+	saved, count, err := s.contributions.SumByGoal(user.ID, goal.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// GOALS CREATED BEFORE A BASE CURRENCY CHANGE ARE CONVERTED AT TODAY'S RATE
+	target, err := s.exchangeRateService.Convert(user.ID, goal.TargetAmount, goal.Currency, user.BaseCurrency, now)
+	if err != nil {
+		return nil, err
+	}
+
+	remaining := max(target-saved, 0)
+
+	percentageSaved := 0.0
+	if target > 0 {
+		percentageSaved = math.Round(saved.Float64()/target.Float64()*10000) / 100
+	}
+
+	progress := &models.GoalProgress{
+		Goal:            *goal,
+		Currency:        user.BaseCurrency,
+		Target:          target,
+		Saved:           saved,
+		Remaining:       remaining,
+		PercentageSaved: percentageSaved,
+		Contributions:   count,
+		AsOf:            now,
+	}
+
+	if goal.TargetDate != nil {
+		months := monthsUntil(now.In(user.Location()), goal.TargetDate.In(user.Location()))
+		required := remaining
+		if months > 0 {
+			required = divideRoundingUp(remaining, months, models.CurrencyExponent(user.BaseCurrency))
+		}
+		progress.MonthsLeft = &months
+		progress.RequiredMonthly = &required
+	}
+
+	return progress, nil
+}
+
+// MONTHLY CONTRIBUTIONS LEFT FROM now UNTIL THE DAY OF target, A PARTIAL MONTH COUNTS AS ONE
+// A TARGET LATER TODAY OR THIS MONTH STILL LEAVES ONE, A PAST TARGET NONE
+func monthsUntil(now, target time.Time) int {
+	nowYear, nowMonth, nowDay := now.Date()
+	targetYear, targetMonth, targetDay := target.Date()
+
+	today := time.Date(nowYear, nowMonth, nowDay, 0, 0, 0, 0, now.Location())
+	if target.Before(today) {
+		return 0
+	}
+
+	months := (targetYear-nowYear)*12 + int(targetMonth-nowMonth)
+	if targetDay > nowDay {
+		months++
+	}
+
+	return max(months, 1)
+}
+
+// DIVIDE amount INTO parts EQUAL PARTS ROUNDED UP TO THE MINOR UNITS OF exponent, SO parts OF THEM COVER amount
+func divideRoundingUp(amount models.Money, parts, exponent int) models.Money {
+	step := models.Money(1)
+	for i := exponent; i < models.MoneyScale; i++ {
+		step *= 10
+	}
+
+	units := (amount + step - 1) / step
+	return (units + models.Money(parts) - 1) / models.Money(parts) * step
+}
@@ -0,0 +1,64 @@
+package services
+
+import (
+	"errors"
+	"go-expense-tracker-api/models"
+
+	"gorm.io/gorm"
+)
+
+var (
+	ErrLedgerAccessDenied = errors.New("not a member of this ledger")
+	ErrLedgerReadOnly     = errors.New("viewers cannot change this ledger")
+)
+
+// IMPLEMENTED BY repositories.LedgerRepository
+type LedgerStore interface {
+	GetMember(ledgerID, userID uint) (*models.LedgerMember, error)
+}
+
+type LedgerService struct {
+	store LedgerStore
+}
+
+func NewLedgerService(store LedgerStore) *LedgerService {
+	return &LedgerService{
+		store: store,
+	}
+}
+
+// MEMBERSHIP OF A USER IN A LEDGER WITH THE LEDGER LOADED, ErrLedgerAccessDenied WHEN THE USER IS NOT A MEMBER
+func (s *LedgerService) Member(ledgerID, userID uint) (*models.LedgerMember, error) {
+	member, err := s.store.GetMember(ledgerID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrLedgerAccessDenied
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return member, nil
+}
+
+// AUTHORIZE A USER ON A RECORD CREATED BY ownerID. RECORDS OF A LEDGER ARE AUTHORIZED BY MEMBERSHIP,
+// WRITES NEED THE OWNER OR EDITOR ROLE. PERSONAL RECORDS (ledgerID NIL) ONLY BY THEIR OWNER
+// RETURNS THE MEMBERSHIP FOR LEDGER RECORDS, NIL FOR PERSONAL ONES
+func (s *LedgerService) Authorize(userID, ownerID uint, ledgerID *uint, write bool) (*models.LedgerMember, error) {
+	if ledgerID == nil {
+		if ownerID != userID {
+			return nil, ErrLedgerAccessDenied
+		}
+		return nil, nil
+	}
+
+	member, err := s.Member(*ledgerID, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if write && !member.CanWrite() {
+		return nil, ErrLedgerReadOnly
+	}
+
+	return member, nil
+}
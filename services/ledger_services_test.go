@@ -0,0 +1,106 @@
+package services
+
+import (
+	"errors"
+	"testing"
+
+	"go-expense-tracker-api/models"
+
+	"gorm.io/gorm"
+)
+
+// MEMBERS BY LEDGER ID THEN USER ID
+type fakeLedgerStore struct {
+	members map[uint]map[uint]string
+	err     error
+}
+
+func (s *fakeLedgerStore) GetMember(ledgerID, userID uint) (*models.LedgerMember, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+
+	role, ok := s.members[ledgerID][userID]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &models.LedgerMember{LedgerID: ledgerID, UserID: userID, Role: role}, nil
+}
+
+func TestLedgerServiceAuthorize(t *testing.T) {
+	const (
+		owner uint = iota + 1
+		editor
+		viewer
+		stranger
+	)
+	ledgerID := uint(10)
+	service := NewLedgerService(&fakeLedgerStore{members: map[uint]map[uint]string{
+		ledgerID: {owner: models.LedgerRoleOwner, editor: models.LedgerRoleEditor, viewer: models.LedgerRoleViewer},
+	}})
+
+	tests := []struct {
+		name     string
+		userID   uint
+		ownerID  uint
+		ledgerID *uint
+		write    bool
+		wantRole string
+		wantErr  error
+	}{
+		{name: "personal record of the user", userID: owner, ownerID: owner, write: true},
+		{name: "personal record of another user", userID: editor, ownerID: owner, wantErr: ErrLedgerAccessDenied},
+		{name: "owner reads", userID: owner, ownerID: editor, ledgerID: &ledgerID, wantRole: models.LedgerRoleOwner},
+		{name: "owner writes", userID: owner, ownerID: editor, ledgerID: &ledgerID, write: true, wantRole: models.LedgerRoleOwner},
+		{name: "editor reads", userID: editor, ownerID: owner, ledgerID: &ledgerID, wantRole: models.LedgerRoleEditor},
+		{name: "editor writes", userID: editor, ownerID: owner, ledgerID: &ledgerID, write: true, wantRole: models.LedgerRoleEditor},
+		{name: "viewer reads", userID: viewer, ownerID: owner, ledgerID: &ledgerID, wantRole: models.LedgerRoleViewer},
+		{name: "viewer writes", userID: viewer, ownerID: owner, ledgerID: &ledgerID, write: true, wantErr: ErrLedgerReadOnly},
+		{name: "viewer writes a record they created", userID: viewer, ownerID: viewer, ledgerID: &ledgerID, write: true, wantErr: ErrLedgerReadOnly},
+		{name: "non-member reads", userID: stranger, ownerID: owner, ledgerID: &ledgerID, wantErr: ErrLedgerAccessDenied},
+		{name: "non-member writes", userID: stranger, ownerID: owner, ledgerID: &ledgerID, write: true, wantErr: ErrLedgerAccessDenied},
+		{name: "former member reads a record they created", userID: stranger, ownerID: stranger, ledgerID: &ledgerID, wantErr: ErrLedgerAccessDenied},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			member, err := service.Authorize(tt.userID, tt.ownerID, tt.ledgerID, tt.write)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Authorize() error = %v, want %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				return
+			}
+
+			var role string
+			if member != nil {
+				role = member.Role
+			}
+			if role != tt.wantRole {
+				t.Errorf("Authorize() role = %q, want %q", role, tt.wantRole)
+			}
+		})
+	}
+}
+
+func TestLedgerServiceMember(t *testing.T) {
+	service := NewLedgerService(&fakeLedgerStore{members: map[uint]map[uint]string{1: {2: models.LedgerRoleViewer}}})
+
+	member, err := service.Member(1, 2)
+	if err != nil || member.Role != models.LedgerRoleViewer || member.CanWrite() {
+		t.Errorf("Member(1, 2) = %+v, %v, want a viewer that cannot write", member, err)
+	}
+
+	if _, err := service.Member(1, 3); !errors.Is(err, ErrLedgerAccessDenied) {
+		t.Errorf("Member(1, 3) error = %v, want ErrLedgerAccessDenied", err)
+	}
+
+	// OTHER STORE ERRORS ARE NOT MISTAKEN FOR A MISSING MEMBERSHIP
+	errConnection := errors.New("connection lost")
+	ledgerID := uint(1)
+	failing := NewLedgerService(&fakeLedgerStore{err: errConnection})
+	if _, err := failing.Authorize(2, 2, &ledgerID, false); !errors.Is(err, errConnection) {
+		t.Errorf("Authorize() error = %v, want the store error", err)
+	}
+}
@@ -0,0 +1,17 @@
+package services
+
+import (
+	"go-expense-tracker-api/models"
+	"testing"
+)
+
+func money(t *testing.T, value string) models.Money {
+	t.Helper()
+
+	m, err := models.ParseMoney(value)
+	if err != nil {
+		t.Fatalf("ParseMoney(%q): %v", value, err)
+	}
+
+	return m
+}
@@ -0,0 +1,203 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"go-expense-tracker-api/models"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// DELIVERS BUDGET ALERTS, e.g. TO THE IN-APP NOTIFICATION LIST OR A WEBHOOK
+type Notifier interface {
+	Notify(alert *models.BudgetAlert) error
+}
+
+// IMPLEMENTED BY repositories.NotificationRepository
+type NotificationStore interface {
+	Create(notification *models.Notification) error
+}
+
+type InAppNotifier struct {
+	store NotificationStore
+}
+
+func NewInAppNotifier(store NotificationStore) *InAppNotifier {
+	return &InAppNotifier{
+		store: store,
+	}
+}
+
+func (n *InAppNotifier) Notify(alert *models.BudgetAlert) error {
+	return n.store.Create(&models.Notification{
+		UserID:        alert.UserID,
+		Type:          models.NotificationTypeBudgetAlert,
+		Title:         fmt.Sprintf("%s budget reached %d%%", alert.CategoryName, alert.Threshold),
+		Message:       fmt.Sprintf("You have spent %s of %s %s (%.2f%%) for %s.", alert.Spent.StringFixed(models.CurrencyExponent(alert.Currency)), alert.LimitAmount.StringFixed(models.CurrencyExponent(alert.Currency)), alert.Currency, alert.PercentageUsed, alert.CategoryName),
+		BudgetAlertID: &alert.ID,
+	})
+}
+
+// WEBHOOK PAYLOAD
+type WebhookEvent struct {
+	Event string              `json:"event"`
+	Alert *models.BudgetAlert `json:"alert"`
+}
+
+const WebhookEventBudgetThreshold = "budget.threshold_crossed"
+
+// DELAYS BEFORE EACH RETRY OF A WEBHOOK POST THAT FAILED, THE DELIVERY FAILS FOR GOOD WHEN THE LAST RETRY FAILS
+var webhookRetryDelays = []time.Duration{time.Minute, 5 * time.Minute, 30 * time.Minute, 2 * time.Hour, 12 * time.Hour}
+
+// MAXIMUM DELIVERIES RETRIED PER RUN, THE REST ARE RETRIED ON THE NEXT RUN
+const webhookRetryBatchSize = 100
+
+// IMPLEMENTED BY repositories.WebhookDeliveryRepository
+type WebhookDeliveryStore interface {
+	Create(delivery *models.WebhookDelivery) error
+	Update(delivery *models.WebhookDelivery) error
+	GetDue(now time.Time, limit int) ([]models.WebhookDelivery, error)
+}
+
+// POSTS ALERTS AS JSON TO A URL, SIGNED WITH HMAC-SHA256 IN X-Signature WHEN A SECRET IS SET
+// EVERY ALERT IS RECORDED AS A WebhookDelivery AND RETRIED UNTIL THE URL ANSWERS 2XX, X-Webhook-Delivery IDENTIFIES RETRIES OF ONE ALERT
+type WebhookNotifier struct {
+	URL      string
+	Secret   string
+	Client   *http.Client
+	store    WebhookDeliveryStore
+	interval time.Duration
+}
+
+func NewWebhookNotifier(url, secret string, store WebhookDeliveryStore, interval time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:      url,
+		Secret:   secret,
+		Client:   &http.Client{Timeout: 10 * time.Second},
+		store:    store,
+		interval: interval,
+	}
+}
+
+// RECORD A PENDING DELIVERY OF THE ALERT AND POST IT IN THE BACKGROUND SO A SLOW WEBHOOK DOES NOT DELAY THE REQUEST
+func (n *WebhookNotifier) Notify(alert *models.BudgetAlert) error {
+	body, err := json.Marshal(WebhookEvent{Event: WebhookEventBudgetThreshold, Alert: alert})
+	if err != nil {
+		return err
+	}
+
+	// THE RETRY LOOP LEAVES IT TO THE FIRST ATTEMPT BELOW UNTIL ITS FIRST RETRY IS DUE
+	now := time.Now()
+	next := now.Add(webhookRetryDelays[0])
+	delivery := &models.WebhookDelivery{
+		BudgetAlertID: alert.ID,
+		Payload:       string(body),
+		Status:        models.WebhookDeliveryPending,
+		NextAttemptAt: &next,
+	}
+	if err := n.store.Create(delivery); err != nil {
+		return err
+	}
+
+	go func() {
+		if err := n.Deliver(delivery, now); err != nil {
+			log.Printf("Failed to deliver budget alert %d to the webhook: %v", alert.ID, err)
+		}
+	}()
+
+	return nil
+}
+
+// RETRY DUE DELIVERIES NOW AND THEN EVERY INTERVAL UNTIL ctx IS DONE
+func (n *WebhookNotifier) Start(ctx context.Context) {
+	ticker := time.NewTicker(n.interval)
+	defer ticker.Stop()
+
+	for {
+		n.RetryDue(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (n *WebhookNotifier) RetryDue(now time.Time) {
+	deliveries, err := n.store.GetDue(now, webhookRetryBatchSize)
+	if err != nil {
+		log.Println("Webhook retry: failed to get due deliveries:", err)
+		return
+	}
+
+	for i := range deliveries {
+		if err := n.Deliver(&deliveries[i], now); err != nil {
+			log.Printf("Webhook retry: failed to deliver budget alert %d (attempt %d): %v", deliveries[i].BudgetAlertID, deliveries[i].Attempts, err)
+		}
+	}
+}
+
+// POST THE DELIVERY ONCE AND RECORD THE OUTCOME, SCHEDULING THE NEXT RETRY WHEN IT FAILED
+func (n *WebhookNotifier) Deliver(delivery *models.WebhookDelivery, now time.Time) error {
+	postErr := n.post(delivery)
+
+	delivery.Attempts++
+	switch {
+	case postErr == nil:
+		delivery.Status = models.WebhookDeliveryDelivered
+		delivery.LastError = ""
+		delivery.NextAttemptAt = nil
+		delivery.DeliveredAt = &now
+	case delivery.Attempts > len(webhookRetryDelays):
+		delivery.Status = models.WebhookDeliveryFailed
+		delivery.LastError = postErr.Error()
+		delivery.NextAttemptAt = nil
+	default:
+		next := now.Add(webhookRetryDelays[delivery.Attempts-1])
+		delivery.LastError = postErr.Error()
+		delivery.NextAttemptAt = &next
+	}
+
+	if err := n.store.Update(delivery); err != nil {
+		return err
+	}
+
+	return postErr
+}
+
+func (n *WebhookNotifier) post(delivery *models.WebhookDelivery) error {
+	body := []byte(delivery.Payload)
+
+	req, err := http.NewRequest(http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Webhook-Delivery", strconv.FormatUint(uint64(delivery.ID), 10))
+
+	if n.Secret != "" {
+		mac := hmac.New(sha256.New, []byte(n.Secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status %d", resp.StatusCode)
+	}
+
+	return nil
+}
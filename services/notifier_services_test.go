@@ -0,0 +1,227 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+type fakeWebhookDeliveryStore struct {
+	mu         sync.Mutex
+	deliveries map[uint]models.WebhookDelivery
+	updated    chan models.WebhookDelivery
+}
+
+func newFakeWebhookDeliveryStore() *fakeWebhookDeliveryStore {
+	return &fakeWebhookDeliveryStore{deliveries: make(map[uint]models.WebhookDelivery), updated: make(chan models.WebhookDelivery, 10)}
+}
+
+func (s *fakeWebhookDeliveryStore) Create(delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delivery.ID = uint(len(s.deliveries) + 1)
+	s.deliveries[delivery.ID] = *delivery
+	return nil
+}
+
+func (s *fakeWebhookDeliveryStore) Update(delivery *models.WebhookDelivery) error {
+	s.mu.Lock()
+	s.deliveries[delivery.ID] = *delivery
+	s.mu.Unlock()
+
+	s.updated <- *delivery
+	return nil
+}
+
+func (s *fakeWebhookDeliveryStore) GetDue(now time.Time, limit int) ([]models.WebhookDelivery, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var due []models.WebhookDelivery
+	for _, delivery := range s.deliveries {
+		if delivery.Status == models.WebhookDeliveryPending && !delivery.NextAttemptAt.After(now) {
+			due = append(due, delivery)
+		}
+	}
+	return due, nil
+}
+
+// A WEBHOOK ANSWERING WITH THE GIVEN STATUSES IN TURN, THEN 200, RECORDING EVERY REQUEST
+type fakeWebhook struct {
+	mu       sync.Mutex
+	statuses []int
+	bodies   []string
+	headers  []http.Header
+}
+
+func (f *fakeWebhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	body, _ := io.ReadAll(r.Body)
+	f.bodies = append(f.bodies, string(body))
+	f.headers = append(f.headers, r.Header.Clone())
+
+	status := http.StatusOK
+	if len(f.statuses) > 0 {
+		status, f.statuses = f.statuses[0], f.statuses[1:]
+	}
+	w.WriteHeader(status)
+}
+
+func waitForUpdate(t *testing.T, store *fakeWebhookDeliveryStore) models.WebhookDelivery {
+	t.Helper()
+
+	select {
+	case delivery := <-store.updated:
+		return delivery
+	case <-time.After(5 * time.Second):
+		t.Fatal("the delivery was never updated")
+		return models.WebhookDelivery{}
+	}
+}
+
+func TestWebhookNotifierNotify(t *testing.T) {
+	webhook := &fakeWebhook{}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	store := newFakeWebhookDeliveryStore()
+	notifier := NewWebhookNotifier(server.URL, "s3cret", store, time.Minute)
+
+	alert := &models.BudgetAlert{ID: 7, UserID: 1, BudgetID: 2, CategoryName: "Food", Threshold: 80, Currency: "EUR"}
+	if err := notifier.Notify(alert); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	delivery := waitForUpdate(t, store)
+	if delivery.Status != models.WebhookDeliveryDelivered || delivery.Attempts != 1 || delivery.DeliveredAt == nil || delivery.NextAttemptAt != nil {
+		t.Errorf("delivery = %+v, want delivered on the first attempt", delivery)
+	}
+
+	webhook.mu.Lock()
+	defer webhook.mu.Unlock()
+
+	if len(webhook.bodies) != 1 {
+		t.Fatalf("webhook received %d requests, want 1", len(webhook.bodies))
+	}
+	header, body := webhook.headers[0], webhook.bodies[0]
+
+	if header.Get("Content-Type") != "application/json" {
+		t.Errorf("Content-Type = %q", header.Get("Content-Type"))
+	}
+	if header.Get("X-Webhook-Delivery") != strconv.FormatUint(uint64(delivery.ID), 10) {
+		t.Errorf("X-Webhook-Delivery = %q, want %d", header.Get("X-Webhook-Delivery"), delivery.ID)
+	}
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(body))
+	if want := "sha256=" + hex.EncodeToString(mac.Sum(nil)); header.Get("X-Signature") != want {
+		t.Errorf("X-Signature = %q, want %q", header.Get("X-Signature"), want)
+	}
+
+	var event struct {
+		Event string             `json:"event"`
+		Alert models.BudgetAlert `json:"alert"`
+	}
+	if err := json.Unmarshal([]byte(body), &event); err != nil {
+		t.Fatalf("invalid payload %s: %v", body, err)
+	}
+	if event.Event != WebhookEventBudgetThreshold || event.Alert.ID != alert.ID || event.Alert.CategoryName != "Food" {
+		t.Errorf("payload = %s", body)
+	}
+}
+
+func TestWebhookNotifierRetries(t *testing.T) {
+	webhook := &fakeWebhook{statuses: []int{http.StatusInternalServerError, http.StatusBadGateway}}
+	server := httptest.NewServer(webhook)
+	defer server.Close()
+
+	store := newFakeWebhookDeliveryStore()
+	notifier := NewWebhookNotifier(server.URL, "", store, time.Minute)
+
+	if err := notifier.Notify(&models.BudgetAlert{ID: 3}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	first := waitForUpdate(t, store)
+	if first.Status != models.WebhookDeliveryPending || first.Attempts != 1 || !strings.Contains(first.LastError, "500") {
+		t.Fatalf("after the first attempt: %+v, want pending with the 500 recorded", first)
+	}
+
+	// NOTHING IS RETRIED BEFORE THE DELAY
+	retryAt := *first.NextAttemptAt
+	notifier.RetryDue(retryAt.Add(-time.Second))
+	select {
+	case delivery := <-store.updated:
+		t.Fatalf("retried before the delay: %+v", delivery)
+	default:
+	}
+
+	notifier.RetryDue(retryAt)
+	second := waitForUpdate(t, store)
+	if second.Status != models.WebhookDeliveryPending || second.Attempts != 2 || !second.NextAttemptAt.Equal(retryAt.Add(webhookRetryDelays[1])) {
+		t.Fatalf("after the second attempt: %+v, want pending until %s", second, retryAt.Add(webhookRetryDelays[1]))
+	}
+
+	notifier.RetryDue(*second.NextAttemptAt)
+	third := waitForUpdate(t, store)
+	if third.Status != models.WebhookDeliveryDelivered || third.Attempts != 3 || third.LastError != "" || third.NextAttemptAt != nil {
+		t.Fatalf("after the third attempt: %+v, want delivered", third)
+	}
+
+	// EVERY ATTEMPT POSTS THE SAME BODY UNDER THE SAME DELIVERY ID
+	webhook.mu.Lock()
+	defer webhook.mu.Unlock()
+	for i := range webhook.bodies {
+		if webhook.bodies[i] != webhook.bodies[0] || webhook.headers[i].Get("X-Webhook-Delivery") != "1" {
+			t.Errorf("attempt %d posted %s as delivery %s", i+1, webhook.bodies[i], webhook.headers[i].Get("X-Webhook-Delivery"))
+		}
+	}
+}
+
+func TestWebhookNotifierGivesUp(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	store := newFakeWebhookDeliveryStore()
+	notifier := NewWebhookNotifier(server.URL, "", store, time.Minute)
+
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	delivery := &models.WebhookDelivery{Payload: `{"event":"budget.threshold_crossed"}`, Status: models.WebhookDeliveryPending, NextAttemptAt: &now}
+	store.Create(delivery)
+
+	for attempt := 1; attempt <= len(webhookRetryDelays)+1; attempt++ {
+		if err := notifier.Deliver(delivery, now); err == nil {
+			t.Fatalf("attempt %d: expected an error", attempt)
+		}
+		<-store.updated
+
+		if attempt <= len(webhookRetryDelays) && (delivery.Status != models.WebhookDeliveryPending || !delivery.NextAttemptAt.Equal(now.Add(webhookRetryDelays[attempt-1]))) {
+			t.Fatalf("attempt %d: %+v, want a retry after %s", attempt, delivery, webhookRetryDelays[attempt-1])
+		}
+		if delivery.NextAttemptAt != nil {
+			now = *delivery.NextAttemptAt
+		}
+	}
+
+	if delivery.Status != models.WebhookDeliveryFailed || delivery.NextAttemptAt != nil || !strings.Contains(delivery.LastError, "503") {
+		t.Errorf("after the last retry: %+v, want failed with the 503 recorded", delivery)
+	}
+	if due, _ := store.GetDue(now.Add(24*time.Hour), 10); len(due) != 0 {
+		t.Errorf("%d deliveries still due after giving up", len(due))
+	}
+}
@@ -0,0 +1,123 @@
+package services
+
+import (
+	"fmt"
+	"go-expense-tracker-api/models"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// IMPLEMENTED BY repositories.PayeeRepository
+type PayeeStore interface {
+	GetAllWithAliasesByUserID(userID uint) ([]models.Payee, error)
+}
+
+type PayeeService struct {
+	store PayeeStore
+}
+
+func NewPayeeService(store PayeeStore) *PayeeService {
+	return &PayeeService{
+		store: store,
+	}
+}
+
+// COMPILED ALIAS RULES OF ONE USER
+type PayeeMatcher struct {
+	rules []payeeRule
+}
+
+type payeeRule struct {
+	payee     *models.Payee
+	matchType string
+	pattern   string
+	regex     *regexp.Regexp
+}
+
+// LOAD THE ALIAS RULES OF A USER, BUILD ONE MATCHER PER REQUEST OR IMPORT
+func (s *PayeeService) Matcher(userID uint) (*PayeeMatcher, error) {
+	payees, err := s.store.GetAllWithAliasesByUserID(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	matcher := &PayeeMatcher{}
+	for i := range payees {
+		payee := &payees[i]
+
+		// THE CANONICAL NAME ITSELF IS AN EXACT ALIAS
+		matcher.rules = append(matcher.rules, payeeRule{payee: payee, matchType: models.PayeeMatchExact, pattern: normalizePayeeName(payee.Name)})
+
+		for _, alias := range payee.Aliases {
+			rule := payeeRule{payee: payee, matchType: alias.MatchType, pattern: normalizePayeeName(alias.Pattern)}
+			switch alias.MatchType {
+			case models.PayeeMatchPrefix:
+				// "GRAB*" READS AS A PREFIX TOO, THE STAR IS NOT PART OF THE NAME
+				if rule.pattern = strings.TrimRight(rule.pattern, "*"); rule.pattern == "" {
+					continue
+				}
+			case models.PayeeMatchRegex:
+				// PATTERNS ARE VALIDATED ON SAVE, SKIP ANY THAT NO LONGER COMPILE
+				if rule.regex, err = CompilePayeePattern(alias.Pattern); err != nil {
+					continue
+				}
+			}
+			matcher.rules = append(matcher.rules, rule)
+		}
+	}
+
+	// EXACT BEFORE PREFIX BEFORE CONTAINS BEFORE REGEX, LONGER PATTERNS FIRST WITHIN A TYPE
+	rank := map[string]int{models.PayeeMatchExact: 0, models.PayeeMatchPrefix: 1, models.PayeeMatchContains: 2, models.PayeeMatchRegex: 3}
+	sort.SliceStable(matcher.rules, func(i, j int) bool {
+		a, b := matcher.rules[i], matcher.rules[j]
+		if rank[a.matchType] != rank[b.matchType] {
+			return rank[a.matchType] < rank[b.matchType]
+		}
+		return len(a.pattern) > len(b.pattern)
+	})
+
+	return matcher, nil
+}
+
+// THE PAYEE OF A RAW EXPENSE NAME, NIL WHEN NO ALIAS MATCHES
+func (m *PayeeMatcher) Match(name string) *models.Payee {
+	normalized := normalizePayeeName(name)
+	if normalized == "" {
+		return nil
+	}
+
+	for _, rule := range m.rules {
+		var matched bool
+		switch rule.matchType {
+		case models.PayeeMatchExact:
+			matched = normalized == rule.pattern
+		case models.PayeeMatchPrefix:
+			matched = strings.HasPrefix(normalized, rule.pattern)
+		case models.PayeeMatchContains:
+			matched = strings.Contains(normalized, rule.pattern)
+		case models.PayeeMatchRegex:
+			matched = rule.regex.MatchString(name)
+		}
+		if matched {
+			return rule.payee
+		}
+	}
+
+	return nil
+}
+
+// COMPILE A REGEX ALIAS, MATCHING IS CASE INSENSITIVE
+func CompilePayeePattern(pattern string) (*regexp.Regexp, error) {
+	regex, err := regexp.Compile("(?i)" + pattern)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pattern %s: %w", pattern, err)
+	}
+
+	return regex, nil
+}
+
+// LOWERCASE WITH COLLAPSED WHITESPACE, SO "GRAB  Food" AND "grab food" ARE THE SAME
+func normalizePayeeName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"testing"
+
+	"go-expense-tracker-api/models"
+)
+
+type fakePayeeStore struct {
+	payees []models.Payee
+}
+
+func (s *fakePayeeStore) GetAllWithAliasesByUserID(userID uint) ([]models.Payee, error) {
+	return s.payees, nil
+}
+
+func TestPayeeMatcher(t *testing.T) {
+	alias := func(matchType, pattern string) models.PayeeAlias {
+		return models.PayeeAlias{MatchType: matchType, Pattern: pattern}
+	}
+
+	store := &fakePayeeStore{payees: []models.Payee{
+		{ID: 1, Name: "Grab", Aliases: []models.PayeeAlias{alias(models.PayeeMatchPrefix, "GRAB*")}},
+		{ID: 2, Name: "Grab Food", Aliases: []models.PayeeAlias{alias(models.PayeeMatchPrefix, "grab  food")}},
+		{ID: 3, Name: "Starbucks", Aliases: []models.PayeeAlias{alias(models.PayeeMatchContains, "sbux"), alias(models.PayeeMatchRegex, `^STARBUCKS\s+#\d+$`)}},
+		{ID: 4, Name: "Amazon", Aliases: []models.PayeeAlias{alias(models.PayeeMatchExact, "AMZN Mktp"), alias(models.PayeeMatchRegex, "(")}},
+		{ID: 5, Name: "Everything", Aliases: []models.PayeeAlias{alias(models.PayeeMatchPrefix, "*")}},
+	}}
+
+	matcher, err := NewPayeeService(store).Matcher(1)
+	if err != nil {
+		t.Fatalf("Matcher() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want uint
+	}{
+		{name: "  grab ", want: 1},
+		{name: "GRAB*RIDE 1234", want: 1},
+		{name: "GRAB   FOOD jakarta", want: 2},
+		{name: "Coffee SBUX 12", want: 3},
+		{name: "Starbucks #42", want: 3},
+		{name: "amzn   mktp", want: 4},
+		{name: "AMZN Mktp DE", want: 0},
+		{name: "Bakery", want: 0},
+		{name: "   ", want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got uint
+			if payee := matcher.Match(tt.name); payee != nil {
+				got = payee.ID
+			}
+			if got != tt.want {
+				t.Errorf("Match(%q) = payee %d, want %d", tt.name, got, tt.want)
+			}
+		})
+	}
+}
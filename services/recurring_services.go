@@ -0,0 +1,160 @@
+package services
+
+import (
+	"context"
+	"go-expense-tracker-api/models"
+	"log"
+	"time"
+)
+
+// MAXIMUM OCCURRENCES GENERATED FOR ONE RECURRING EXPENSE PER RUN, THE REST IS CAUGHT UP ON THE NEXT RUN
+const maxOccurrencesPerRun = 500
+
+// IMPLEMENTED BY repositories.RecurringExpenseRepository
+type RecurringExpenseStore interface {
+	GetDueIDs(now time.Time) ([]uint, error)
+	Materialize(id uint, generate func(recurringExpense *models.RecurringExpense) ([]*models.Expense, error)) (int64, error)
+}
+
+// IMPLEMENTED BY repositories.UserRepository
+type UserStore interface {
+	GetByID(id uint) (*models.User, error)
+}
+
+type RecurringScheduler struct {
+	store               RecurringExpenseStore
+	users               UserStore
+	exchangeRateService *ExchangeRateService
+	interval            time.Duration
+}
+
+func NewRecurringScheduler(store RecurringExpenseStore, users UserStore, exchangeRateService *ExchangeRateService, interval time.Duration) *RecurringScheduler {
+	return &RecurringScheduler{
+		store:               store,
+		users:               users,
+		exchangeRateService: exchangeRateService,
+		interval:            interval,
+	}
+}
+
+// RUN DUE RECURRING EXPENSES NOW AND THEN EVERY INTERVAL UNTIL ctx IS DONE
+// OCCURRENCES MISSED WHILE THE SERVER WAS DOWN ARE CAUGHT UP ON THE FIRST RUN
+func (s *RecurringScheduler) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.RunDue(time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *RecurringScheduler) RunDue(now time.Time) {
+	ids, err := s.store.GetDueIDs(now)
+	if err != nil {
+		log.Println("Recurring scheduler: failed to get due recurring expenses:", err)
+		return
+	}
+
+	for _, id := range ids {
+		created, err := s.store.Materialize(id, func(recurringExpense *models.RecurringExpense) ([]*models.Expense, error) {
+			return s.generate(recurringExpense, now)
+		})
+		if err != nil {
+			log.Printf("Recurring scheduler: failed to materialize recurring expense %d: %v", id, err)
+			continue
+		}
+		if created > 0 {
+			log.Printf("Recurring scheduler: created %d expenses from recurring expense %d", created, id)
+		}
+	}
+}
+
+// BUILD EXPENSES FOR EVERY OCCURRENCE DUE AT OR BEFORE now AND ADVANCE THE SCHEDULE
+func (s *RecurringScheduler) generate(recurringExpense *models.RecurringExpense, now time.Time) ([]*models.Expense, error) {
+	if !recurringExpense.IsActive {
+		return nil, nil
+	}
+
+	user, err := s.users.GetByID(recurringExpense.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	var expenses []*models.Expense
+	for recurringExpense.NextRunAt != nil && !recurringExpense.NextRunAt.After(now) && len(expenses) < maxOccurrencesPerRun {
+		expense := &models.Expense{
+			Name:               recurringExpense.Name,
+			Amount:             recurringExpense.Amount,
+			Currency:           recurringExpense.Currency,
+			SpentAt:            *recurringExpense.NextRunAt,
+			UserID:             recurringExpense.UserID,
+			CategoryID:         recurringExpense.CategoryID,
+			RecurringExpenseID: &recurringExpense.ID,
+		}
+
+		if err := s.exchangeRateService.ConvertExpense(user.ID, user.BaseCurrency, expense); err != nil {
+			return nil, err
+		}
+
+		expenses = append(expenses, expense)
+		recurringExpense.OccurrenceCount++
+		recurringExpense.NextRunAt = NextOccurrence(recurringExpense)
+	}
+
+	return expenses, nil
+}
+
+// GET THE n-TH (0-BASED) OCCURRENCE, COUNTED FROM THE START DATE TO AVOID DRIFT
+// MONTHLY AND YEARLY OCCURRENCES ARE CLAMPED TO THE LAST DAY OF SHORTER MONTHS (e.g. JAN 31 -> FEB 28)
+func Occurrence(recurringExpense *models.RecurringExpense, n int) time.Time {
+	start := recurringExpense.StartDate
+	interval := recurringExpense.Interval
+	if interval < 1 {
+		interval = 1
+	}
+
+	switch recurringExpense.Frequency {
+	case models.FrequencyDaily:
+		return start.AddDate(0, 0, n*interval)
+	case models.FrequencyWeekly:
+		return start.AddDate(0, 0, 7*n*interval)
+	case models.FrequencyYearly:
+		return addMonthsClamped(start, 12*n*interval)
+	default:
+		return addMonthsClamped(start, n*interval)
+	}
+}
+
+// GET THE OCCURRENCE AFTER OccurrenceCount, NIL WHEN IT IS PAST THE END DATE
+func NextOccurrence(recurringExpense *models.RecurringExpense) *time.Time {
+	next := Occurrence(recurringExpense, recurringExpense.OccurrenceCount)
+	if recurringExpense.EndDate != nil && next.After(*recurringExpense.EndDate) {
+		return nil
+	}
+	return &next
+}
+
+// ADVANCE OccurrenceCount PAST EVERY OCCURRENCE BEFORE now SO THE SCHEDULER DOES NOT GENERATE THEM
+func SkipOccurrencesBefore(recurringExpense *models.RecurringExpense, now time.Time) {
+	for Occurrence(recurringExpense, recurringExpense.OccurrenceCount).Before(now) {
+		recurringExpense.OccurrenceCount++
+	}
+}
+
+func addMonthsClamped(t time.Time, months int) time.Time {
+	year, month, day := t.Date()
+	firstOfMonth := time.Date(year, month+time.Month(months), 1, t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), t.Location())
+
+	lastDay := firstOfMonth.AddDate(0, 1, -1).Day()
+	if day > lastDay {
+		day = lastDay
+	}
+
+	return firstOfMonth.AddDate(0, 0, day-1)
+}
@@ -0,0 +1,84 @@
+package services
+
+import (
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 9, 0, 0, 0, time.UTC)
+}
+
+func TestOccurrence(t *testing.T) {
+	tests := []struct {
+		name      string
+		frequency string
+		interval  int
+		start     time.Time
+		n         int
+		want      time.Time
+	}{
+		{name: "first occurrence is the start date", frequency: models.FrequencyMonthly, interval: 1, start: date(2025, 1, 15), n: 0, want: date(2025, 1, 15)},
+		{name: "daily", frequency: models.FrequencyDaily, interval: 1, start: date(2025, 1, 30), n: 3, want: date(2025, 2, 2)},
+		{name: "every other week", frequency: models.FrequencyWeekly, interval: 2, start: date(2025, 1, 1), n: 2, want: date(2025, 1, 29)},
+		{name: "monthly clamped to a shorter month", frequency: models.FrequencyMonthly, interval: 1, start: date(2025, 1, 31), n: 1, want: date(2025, 2, 28)},
+		{name: "monthly clamped in a leap year", frequency: models.FrequencyMonthly, interval: 1, start: date(2024, 1, 31), n: 1, want: date(2024, 2, 29)},
+		{name: "monthly does not drift after a short month", frequency: models.FrequencyMonthly, interval: 1, start: date(2025, 1, 31), n: 2, want: date(2025, 3, 31)},
+		{name: "quarterly", frequency: models.FrequencyMonthly, interval: 3, start: date(2025, 11, 30), n: 1, want: date(2026, 2, 28)},
+		{name: "yearly from a leap day", frequency: models.FrequencyYearly, interval: 1, start: date(2024, 2, 29), n: 1, want: date(2025, 2, 28)},
+		{name: "interval below one counts as one", frequency: models.FrequencyDaily, interval: 0, start: date(2025, 1, 1), n: 2, want: date(2025, 1, 3)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurringExpense := &models.RecurringExpense{Frequency: tt.frequency, Interval: tt.interval, StartDate: tt.start}
+
+			if got := Occurrence(recurringExpense, tt.n); !got.Equal(tt.want) {
+				t.Errorf("Occurrence(%d) = %s, want %s", tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	first, second, end := date(2025, 1, 15), date(2025, 2, 15), date(2025, 3, 15)
+	recurringExpense := &models.RecurringExpense{Frequency: models.FrequencyMonthly, Interval: 1, StartDate: first, EndDate: &end}
+
+	// THE END DATE ITSELF IS STILL AN OCCURRENCE
+	for count, want := range []*time.Time{&first, &second, &end, nil} {
+		recurringExpense.OccurrenceCount = count
+		got := NextOccurrence(recurringExpense)
+
+		if (got == nil) != (want == nil) || (got != nil && !got.Equal(*want)) {
+			t.Errorf("after %d occurrences: NextOccurrence = %v, want %v", count, got, want)
+		}
+	}
+}
+
+func TestSkipOccurrencesBefore(t *testing.T) {
+	tests := []struct {
+		name  string
+		count int
+		now   time.Time
+		want  int
+	}{
+		{name: "paused for two months", count: 1, now: date(2025, 4, 1), want: 3},
+		{name: "an occurrence due exactly now is kept", count: 1, now: date(2025, 4, 10), want: 3},
+		{name: "nothing missed", count: 3, now: date(2025, 4, 1), want: 3},
+		{name: "start date in the future", count: 0, now: date(2024, 12, 1), want: 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			recurringExpense := &models.RecurringExpense{Frequency: models.FrequencyMonthly, Interval: 1, StartDate: date(2025, 1, 10), OccurrenceCount: tt.count}
+
+			SkipOccurrencesBefore(recurringExpense, tt.now)
+
+			if recurringExpense.OccurrenceCount != tt.want {
+				t.Errorf("OccurrenceCount = %d, want %d", recurringExpense.OccurrenceCount, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,154 @@
+package services
+
+import (
+	"go-expense-tracker-api/models"
+	"sort"
+)
+
+// UP TO THIS MANY OPEN BALANCES THE FEWEST PAYMENTS ARE SEARCHED EXACTLY (2^n STATES), ABOVE IT GREEDILY
+const maxExactSettleUp = 16
+
+type debtBalance struct {
+	userID uint
+	amount models.Money
+}
+
+// THE FEWEST PAYMENTS THAT BRING EVERY BALANCE TO ZERO, BALANCES MUST SUM TO ZERO
+// n OPEN BALANCES NEED n - k PAYMENTS WHERE k IS THE LARGEST NUMBER OF GROUPS THEY SPLIT INTO THAT EACH SUM TO ZERO,
+// SO THE GROUPS ARE FOUND FIRST AND EACH IS THEN SETTLED BY MATCHING ITS LARGEST CREDITOR WITH ITS LARGEST DEBTOR
+func SimplifyDebts(balances []models.LedgerMemberBalance) []models.SettleUpPayment {
+	var open []debtBalance
+	for _, balance := range balances {
+		if balance.Balance != 0 {
+			open = append(open, debtBalance{userID: balance.UserID, amount: balance.Balance})
+		}
+	}
+
+	payments := []models.SettleUpPayment{}
+	if len(open) > maxExactSettleUp {
+		return settleGreedily(open, payments)
+	}
+
+	for _, group := range zeroSumGroups(open) {
+		payments = settleGreedily(group, payments)
+	}
+
+	return payments
+}
+
+// PARTITION balances INTO THE LARGEST NUMBER OF GROUPS THAT EACH SUM TO ZERO
+func zeroSumGroups(balances []debtBalance) [][]debtBalance {
+	n := len(balances)
+	if n == 0 {
+		return nil
+	}
+	full := 1<<n - 1
+
+	// sums[mask] IS THE SUM OF THE BALANCES IN mask, groups[mask] THE MOST ZERO SUM GROUPS mask CAN BE CUT INTO
+	// WHEN ITS MEMBERS ARE ADDED ONE AT A TIME, EVERY PREFIX THAT SUMS TO ZERO CLOSES A GROUP
+	sums := make([]models.Money, full+1)
+	groups := make([]int, full+1)
+	for mask := 1; mask <= full; mask++ {
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) == 0 {
+				continue
+			}
+			rest := mask &^ (1 << i)
+			sums[mask] = sums[rest] + balances[i].amount
+			break
+		}
+
+		best := 0
+		for i := 0; i < n; i++ {
+			if mask&(1<<i) != 0 && groups[mask&^(1<<i)] > best {
+				best = groups[mask&^(1<<i)]
+			}
+		}
+		if sums[mask] == 0 {
+			best++
+		}
+		groups[mask] = best
+	}
+
+	// WALK BACK FROM THE FULL SET, CUTTING A GROUP AT EVERY ZERO SUM PREFIX
+	var result [][]debtBalance
+	mask, closed := full, full
+	for mask != 0 {
+		closing := 0
+		if sums[mask] == 0 {
+			closing = 1
+		}
+
+		for i := 0; i < n; i++ {
+			rest := mask &^ (1 << i)
+			if mask&(1<<i) == 0 || groups[rest]+closing != groups[mask] {
+				continue
+			}
+
+			if sums[rest] == 0 {
+				result = append(result, pick(balances, closed&^rest))
+				closed = rest
+			}
+			mask = rest
+			break
+		}
+	}
+
+	return result
+}
+
+func pick(balances []debtBalance, mask int) []debtBalance {
+	var picked []debtBalance
+	for i := range balances {
+		if mask&(1<<i) != 0 {
+			picked = append(picked, balances[i])
+		}
+	}
+
+	return picked
+}
+
+// SETTLE balances BY REPEATEDLY PAYING THE LARGEST CREDITOR FROM THE LARGEST DEBTOR, EVERY PAYMENT CLEARS AT LEAST ONE OF THEM
+func settleGreedily(balances []debtBalance, payments []models.SettleUpPayment) []models.SettleUpPayment {
+	var creditors, debtors []debtBalance
+	for _, balance := range balances {
+		if balance.amount > 0 {
+			creditors = append(creditors, balance)
+		} else if balance.amount < 0 {
+			debtors = append(debtors, debtBalance{userID: balance.userID, amount: -balance.amount})
+		}
+	}
+
+	for len(creditors) > 0 && len(debtors) > 0 {
+		sortDebtBalances(creditors)
+		sortDebtBalances(debtors)
+
+		amount := min(creditors[0].amount, debtors[0].amount)
+		payments = append(payments, models.SettleUpPayment{
+			FromUserID: debtors[0].userID,
+			ToUserID:   creditors[0].userID,
+			Amount:     amount,
+		})
+
+		creditors[0].amount -= amount
+		debtors[0].amount -= amount
+		if creditors[0].amount == 0 {
+			creditors = creditors[1:]
+		}
+		if debtors[0].amount == 0 {
+			debtors = debtors[1:]
+		}
+	}
+
+	return payments
+}
+
+// LARGEST AMOUNT FIRST, TIES BY USER ID SO THE RESULT IS STABLE
+func sortDebtBalances(balances []debtBalance) {
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].amount != balances[j].amount {
+			return balances[i].amount > balances[j].amount
+		}
+		return balances[i].userID < balances[j].userID
+	})
+}
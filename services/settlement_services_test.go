@@ -0,0 +1,138 @@
+package services
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+
+	"go-expense-tracker-api/models"
+)
+
+func balances(t *testing.T, amounts map[uint]string) []models.LedgerMemberBalance {
+	t.Helper()
+
+	var result []models.LedgerMemberBalance
+	for userID := uint(1); userID <= uint(len(amounts)); userID++ {
+		result = append(result, models.LedgerMemberBalance{UserID: userID, Balance: money(t, amounts[userID])})
+	}
+
+	return result
+}
+
+func TestSimplifyDebts(t *testing.T) {
+	tests := []struct {
+		name     string
+		balances map[uint]string
+		want     []models.SettleUpPayment
+	}{
+		{
+			name:     "no members",
+			balances: map[uint]string{},
+			want:     []models.SettleUpPayment{},
+		},
+		{
+			name:     "already settled",
+			balances: map[uint]string{1: "0", 2: "0", 3: "0"},
+			want:     []models.SettleUpPayment{},
+		},
+		{
+			name:     "one debtor pays one creditor",
+			balances: map[uint]string{1: "25.5", 2: "-25.5"},
+			want:     []models.SettleUpPayment{{FromUserID: 2, ToUserID: 1, Amount: money(t, "25.5")}},
+		},
+		{
+			// 1 OWES 2 30, 2 OWES 3 20 AND 3 OWES 1 10, THE CYCLE NETS TO 1 PAYING 2 AND 3 10 EACH
+			name:     "cycle of three members",
+			balances: map[uint]string{1: "-20", 2: "10", 3: "10"},
+			want: []models.SettleUpPayment{
+				{FromUserID: 1, ToUserID: 2, Amount: money(t, "10")},
+				{FromUserID: 1, ToUserID: 3, Amount: money(t, "10")},
+			},
+		},
+		{
+			name:     "remainder cents of a three way split",
+			balances: map[uint]string{1: "6.66", 2: "-3.33", 3: "-3.33"},
+			want: []models.SettleUpPayment{
+				{FromUserID: 2, ToUserID: 1, Amount: money(t, "3.33")},
+				{FromUserID: 3, ToUserID: 1, Amount: money(t, "3.33")},
+			},
+		},
+		{
+			name:     "members already settled are left out",
+			balances: map[uint]string{1: "5", 2: "0", 3: "-5"},
+			want:     []models.SettleUpPayment{{FromUserID: 3, ToUserID: 1, Amount: money(t, "5")}},
+		},
+		{
+			// MATCHING THE LARGEST CREDITOR WITH THE LARGEST DEBTOR TAKES FOUR PAYMENTS, SETTLING {1, 4, 5} AND {2, 3} SEPARATELY TAKES THREE
+			name:     "zero sum groups are settled separately",
+			balances: map[uint]string{1: "5", 2: "4", 3: "-4", 4: "-3", 5: "-2"},
+			want: []models.SettleUpPayment{
+				{FromUserID: 4, ToUserID: 1, Amount: money(t, "3")},
+				{FromUserID: 5, ToUserID: 1, Amount: money(t, "2")},
+				{FromUserID: 3, ToUserID: 2, Amount: money(t, "4")},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := balances(t, tt.balances)
+			payments := SimplifyDebts(input)
+
+			if !reflect.DeepEqual(payments, tt.want) {
+				t.Errorf("SimplifyDebts() = %+v, want %+v", payments, tt.want)
+			}
+
+			// EVERY PAYMENT BRINGS ITS PAYER UP AND ITS RECEIVER DOWN, ALL BALANCES END AT ZERO
+			left := make(map[uint]models.Money)
+			for _, balance := range input {
+				left[balance.UserID] = balance.Balance
+			}
+			for _, payment := range payments {
+				left[payment.FromUserID] += payment.Amount
+				left[payment.ToUserID] -= payment.Amount
+			}
+			for userID, amount := range left {
+				if amount != 0 {
+					t.Errorf("user %d left with %s", userID, amount)
+				}
+			}
+		})
+	}
+}
+
+func TestSimplifyDebtsManyMembers(t *testing.T) {
+	// ABOVE maxExactSettleUp THE BALANCES ARE SETTLED GREEDILY, STILL NEVER MORE THAN n - 1 PAYMENTS
+	amounts := make(map[uint]string)
+	for userID := uint(1); userID <= maxExactSettleUp+4; userID++ {
+		if userID%2 == 0 {
+			amounts[userID] = "-" + strconv.Itoa(int(userID))
+		} else {
+			amounts[userID] = strconv.Itoa(int(userID) + 1)
+		}
+	}
+
+	input := balances(t, amounts)
+	payments := SimplifyDebts(input)
+
+	if len(payments) > len(input)-1 {
+		t.Errorf("got %d payments for %d members", len(payments), len(input))
+	}
+
+	left := make(map[uint]models.Money)
+	for _, balance := range input {
+		left[balance.UserID] = balance.Balance
+	}
+	for _, payment := range payments {
+		if payment.Amount <= 0 {
+			t.Errorf("payment %+v is not positive", payment)
+		}
+		left[payment.FromUserID] += payment.Amount
+		left[payment.ToUserID] -= payment.Amount
+	}
+	for userID, amount := range left {
+		if amount != 0 {
+			t.Errorf("user %d left with %s", userID, amount)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"go-expense-tracker-api/models"
+	"log"
+	"time"
+)
+
+// MAXIMUM EXPIRED EXPENSES PURGED PER BATCH, BATCHES REPEAT UNTIL NONE ARE LEFT
+const trashPurgeBatchSize = 100
+
+// IMPLEMENTED BY repositories.ExpenseRepository
+type TrashStore interface {
+	GetTrashedBefore(before time.Time, limit int) ([]models.Expense, error)
+	Purge(expense *models.Expense) error
+}
+
+type TrashService struct {
+	store             TrashStore
+	attachmentService *AttachmentService
+	retention         time.Duration
+	interval          time.Duration
+}
+
+func NewTrashService(store TrashStore, attachmentService *AttachmentService, retention, interval time.Duration) *TrashService {
+	return &TrashService{
+		store:             store,
+		attachmentService: attachmentService,
+		retention:         retention,
+		interval:          interval,
+	}
+}
+
+// DELETE THE ATTACHMENTS OF AN EXPENSE, THEN THE EXPENSE ITSELF PERMANENTLY
+// A FAILED ATTACHMENT KEEPS THE EXPENSE IN THE TRASH SO A LATER PURGE CAN RETRY
+func (s *TrashService) Purge(ctx context.Context, expense *models.Expense) error {
+	if err := s.attachmentService.DeleteByExpense(ctx, expense.ID); err != nil {
+		return err
+	}
+
+	return s.store.Purge(expense)
+}
+
+// PURGE EXPENSES DELETED BEFORE now MINUS THE RETENTION NOW AND THEN EVERY INTERVAL UNTIL ctx IS DONE
+func (s *TrashService) Start(ctx context.Context) {
+	ticker := time.NewTicker(s.interval)
+	defer ticker.Stop()
+
+	for {
+		s.PurgeExpired(ctx, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *TrashService) PurgeExpired(ctx context.Context, now time.Time) {
+	before := now.Add(-s.retention)
+
+	var purged int
+	for {
+		expenses, err := s.store.GetTrashedBefore(before, trashPurgeBatchSize)
+		if err != nil {
+			log.Println("Trash purge: failed to get expired expenses:", err)
+			return
+		}
+
+		for i := range expenses {
+			if err := s.Purge(ctx, &expenses[i]); err != nil {
+				log.Printf("Trash purge: failed to purge expense %d: %v", expenses[i].ID, err)
+				return
+			}
+			purged++
+		}
+
+		if len(expenses) < trashPurgeBatchSize {
+			break
+		}
+	}
+
+	if purged > 0 {
+		log.Printf("Trash purge: purged %d expenses deleted before %s", purged, before.Format(time.RFC3339))
+	}
+}
@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+	"time"
+
+	"go-expense-tracker-api/models"
+)
+
+type fakeTrashStore struct {
+	trashed []models.Expense
+	purged  []uint
+}
+
+func (s *fakeTrashStore) GetTrashedBefore(before time.Time, limit int) ([]models.Expense, error) {
+	var expenses []models.Expense
+	for _, expense := range s.trashed {
+		if len(expenses) < limit && !s.isPurged(expense.ID) {
+			expenses = append(expenses, expense)
+		}
+	}
+
+	return expenses, nil
+}
+
+func (s *fakeTrashStore) Purge(expense *models.Expense) error {
+	s.purged = append(s.purged, expense.ID)
+	return nil
+}
+
+func (s *fakeTrashStore) isPurged(id uint) bool {
+	for _, purged := range s.purged {
+		if purged == id {
+			return true
+		}
+	}
+
+	return false
+}
+
+type fakeAttachmentStore struct {
+	attachments []models.Attachment
+}
+
+func (s *fakeAttachmentStore) Create(attachment *models.Attachment) error {
+	s.attachments = append(s.attachments, *attachment)
+	return nil
+}
+
+func (s *fakeAttachmentStore) GetByExpenseID(expenseID uint) ([]models.Attachment, error) {
+	var attachments []models.Attachment
+	for _, attachment := range s.attachments {
+		if attachment.ExpenseID == expenseID {
+			attachments = append(attachments, attachment)
+		}
+	}
+
+	return attachments, nil
+}
+
+func (s *fakeAttachmentStore) Delete(attachment *models.Attachment) error {
+	for i := range s.attachments {
+		if s.attachments[i].ID == attachment.ID {
+			s.attachments = append(s.attachments[:i], s.attachments[i+1:]...)
+			break
+		}
+	}
+
+	return nil
+}
+
+// A STORAGE WHOSE DELETES FAIL WHILE down IS SET
+type fakeStorage struct {
+	down bool
+}
+
+func (s *fakeStorage) Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error {
+	return nil
+}
+
+func (s *fakeStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	return nil, errors.New("not found")
+}
+
+func (s *fakeStorage) Delete(ctx context.Context, key string) error {
+	if s.down {
+		return errors.New("storage unavailable")
+	}
+
+	return nil
+}
+
+func TestTrashServicePurgeKeepsTheExpenseWhenAttachmentsFail(t *testing.T) {
+	store := &fakeTrashStore{trashed: []models.Expense{{ID: 1}, {ID: 2}}}
+	attachments := &fakeAttachmentStore{attachments: []models.Attachment{{ID: 10, ExpenseID: 1, StorageKey: "a"}, {ID: 20, ExpenseID: 2, StorageKey: "b"}}}
+	storage := &fakeStorage{down: true}
+	service := NewTrashService(store, NewAttachmentService(attachments, storage, 1<<20), time.Hour, time.Hour)
+
+	if err := service.Purge(context.Background(), &models.Expense{ID: 1}); err == nil {
+		t.Fatal("Purge() error = nil, want the storage error")
+	}
+	if len(store.purged) != 0 || len(attachments.attachments) != 2 {
+		t.Fatalf("purged %v with %d attachments left, want nothing purged", store.purged, len(attachments.attachments))
+	}
+
+	// THE NEXT RUN RETRIES ONCE THE STORAGE IS BACK
+	service.PurgeExpired(context.Background(), time.Now())
+	if len(store.purged) != 0 {
+		t.Fatalf("purged %v while the storage is down", store.purged)
+	}
+
+	storage.down = false
+	service.PurgeExpired(context.Background(), time.Now())
+	if len(store.purged) != 2 || len(attachments.attachments) != 0 {
+		t.Errorf("purged %v with %d attachments left, want both expenses and their attachments gone", store.purged, len(attachments.attachments))
+	}
+}
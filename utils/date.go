@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"errors"
+	"strings"
+	"time"
+)
+
+const DateLayout = "2006-01-02"
+
+var dateTimeLayouts = []string{
+	time.RFC3339,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04",
+	"2006-01-02 15:04:05",
+	"2006-01-02 15:04",
+}
+
+// PARSE DATE (YYYY-MM-DD) OR DATE TIME STRING IN THE GIVEN LOCATION
+// dateOnly IS TRUE WHEN THE VALUE HAS NO TIME PART
+func ParseDateTime(value string, loc *time.Location) (t time.Time, dateOnly bool, err error) {
+	value = strings.TrimSpace(value)
+	if loc == nil {
+		loc = time.UTC
+	}
+
+	if t, err := time.ParseInLocation(DateLayout, value, loc); err == nil {
+		return t, true, nil
+	}
+
+	for _, layout := range dateTimeLayouts {
+		if t, err := time.ParseInLocation(layout, value, loc); err == nil {
+			return t, false, nil
+		}
+	}
+
+	return time.Time{}, false, errors.New("invalid date " + value + ", expected YYYY-MM-DD or RFC3339")
+}
@@ -0,0 +1,47 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateTime(t *testing.T) {
+	jakarta := time.FixedZone("WIB", 7*60*60)
+
+	tests := []struct {
+		name         string
+		value        string
+		loc          *time.Location
+		want         time.Time
+		wantDateOnly bool
+		wantErr      bool
+	}{
+		{name: "date", value: "2025-01-15", loc: jakarta, want: time.Date(2025, 1, 15, 0, 0, 0, 0, jakarta), wantDateOnly: true},
+		{name: "date with spaces", value: " 2025-01-15 ", loc: jakarta, want: time.Date(2025, 1, 15, 0, 0, 0, 0, jakarta), wantDateOnly: true},
+		{name: "rfc3339 keeps its offset", value: "2025-01-15T10:00:00+02:00", loc: jakarta, want: time.Date(2025, 1, 15, 8, 0, 0, 0, time.UTC)},
+		{name: "rfc3339 utc", value: "2025-01-15T10:00:00Z", loc: jakarta, want: time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)},
+		{name: "local seconds", value: "2025-01-15T10:30:15", loc: jakarta, want: time.Date(2025, 1, 15, 10, 30, 15, 0, jakarta)},
+		{name: "local minutes", value: "2025-01-15T10:30", loc: jakarta, want: time.Date(2025, 1, 15, 10, 30, 0, 0, jakarta)},
+		{name: "space separated seconds", value: "2025-01-15 10:30:15", loc: jakarta, want: time.Date(2025, 1, 15, 10, 30, 15, 0, jakarta)},
+		{name: "space separated minutes", value: "2025-01-15 10:30", loc: jakarta, want: time.Date(2025, 1, 15, 10, 30, 0, 0, jakarta)},
+		{name: "no location is utc", value: "2025-01-15", want: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), wantDateOnly: true},
+		{name: "day first", value: "15/01/2025", loc: jakarta, wantErr: true},
+		{name: "invalid day", value: "2025-02-30", loc: jakarta, wantErr: true},
+		{name: "empty", value: "", loc: jakarta, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, dateOnly, err := ParseDateTime(tt.value, tt.loc)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseDateTime(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) || dateOnly != tt.wantDateOnly {
+				t.Errorf("ParseDateTime(%q) = %s, %t, want %s, %t", tt.value, got, dateOnly, tt.want, tt.wantDateOnly)
+			}
+		})
+	}
+}